@@ -16,23 +16,10 @@ import (
 )
 
 func main() {
-	// 支持通过环境变量 CONFIG_PATH 指定配置文件，默认使用 dev；不存在则回退 example
-	cfgPath := os.Getenv("CONFIG_PATH")
-	if cfgPath == "" {
-		cfgPath = "configs/config.dev.yaml"
-	}
-	if _, err := os.Stat(cfgPath); err != nil {
-		fallback := "configs/config.example.yaml"
-		if _, err2 := os.Stat(fallback); err2 == nil {
-			log.Printf("config %s not found, fallback to %s", cfgPath, fallback)
-			cfgPath = fallback
-		} else {
-			log.Fatalf("config file not found: %s (fallback %s also missing)", cfgPath, fallback)
-		}
-	}
-	// 归一化路径，方便日志可读
-	if abs, err := filepath.Abs(cfgPath); err == nil {
-		cfgPath = abs
+	cfgPath := configPath()
+	// 子命令：api sdk ...（生成客户端 SDK，仅连接数据库）
+	if len(os.Args) > 1 && os.Args[1] == "sdk" {
+		os.Exit(runSDK(cfgPath, os.Args[2:]))
 	}
 
 	app, err := boot.InitApp(cfgPath)
@@ -60,3 +47,25 @@ func main() {
 	app.Close()
 	app.Logger.Info("cleanup_done")
 }
+
+// configPath 支持通过环境变量 CONFIG_PATH 指定配置文件，默认使用 dev；不存在则回退 example
+func configPath() string {
+	cfgPath := os.Getenv("CONFIG_PATH")
+	if cfgPath == "" {
+		cfgPath = "configs/config.dev.yaml"
+	}
+	if _, err := os.Stat(cfgPath); err != nil {
+		fallback := "configs/config.example.yaml"
+		if _, err2 := os.Stat(fallback); err2 == nil {
+			log.Printf("config %s not found, fallback to %s", cfgPath, fallback)
+			cfgPath = fallback
+		} else {
+			log.Fatalf("config file not found: %s (fallback %s also missing)", cfgPath, fallback)
+		}
+	}
+	// 归一化路径，方便日志可读
+	if abs, err := filepath.Abs(cfgPath); err == nil {
+		cfgPath = abs
+	}
+	return cfgPath
+}
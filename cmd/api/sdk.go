@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go-apiadmin/internal/boot"
+	"go-apiadmin/internal/service"
+)
+
+// runSDK 生成 Go 客户端 SDK zip：
+//
+//	api sdk -app <app_id> -group <分组hash> -package apiclient -module example.com/apiclient -base-url https://gw.example.com -o apiclient.zip
+//
+// 未指定 -app 时包含全部启用接口。
+func runSDK(cfgPath string, args []string) int {
+	fs := flag.NewFlagSet("sdk", flag.ContinueOnError)
+	appID := fs.String("app", "", "应用 app_id，按其可见范围与签名配置生成")
+	group := fs.String("group", "", "分组 hash，只生成该分组的接口")
+	pkg := fs.String("package", "apiclient", "Go 包名")
+	module := fs.String("module", "", "go.mod 模块路径，默认同包名")
+	baseURL := fs.String("base-url", "", "默认网关地址")
+	out := fs.String("o", "", "输出 zip 路径，默认 <package>-go-sdk.zip")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	cli, err := boot.InitCLI(cfgPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "init:", err)
+		return 1
+	}
+	defer cli.Close()
+	user := service.WikiUserInfo{AppID: "-1"}
+	if *appID != "" {
+		user.AppID = *appID
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	var buf bytes.Buffer
+	err = cli.Wiki.GoSDK(ctx, service.GoSDKOptions{
+		Package: *pkg,
+		Module:  *module,
+		Group:   *group,
+		BaseURL: *baseURL,
+		Title:   cli.Config.AppMeta.Name,
+		Version: cli.Config.AppMeta.Version,
+		User:    user,
+	}, &buf)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sdk:", err)
+		return 1
+	}
+	if *out == "" {
+		*out = *pkg + "-go-sdk.zip"
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "write:", err)
+		return 1
+	}
+	fmt.Println(*out)
+	return 0
+}
@@ -0,0 +1,37 @@
+package boot
+
+import (
+	"go-apiadmin/internal/config"
+	"go-apiadmin/internal/repository/dao"
+	"go-apiadmin/internal/service"
+)
+
+// CLI 子命令（如 SDK 生成）使用：仅连接数据库构造 WikiService，不启动 HTTP、Redis、Kafka、etcd
+type CLI struct {
+	Config *config.Config
+	Wiki   *service.WikiService
+	close  func()
+}
+
+// InitCLI 读取配置并连接数据库
+func InitCLI(configPath string) (*CLI, error) {
+	c, err := ProvideConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	db, err := NewPostgres(c)
+	if err != nil {
+		return nil, err
+	}
+	app, list := dao.NewAdminAppDAO(db), dao.NewAdminInterfaceListDAO(db)
+	wiki := service.NewWikiService(app, dao.NewAdminGroupDAO(db), list, dao.NewAdminFieldsDAO(db), nil)
+	wiki.Visibility = service.NewVisibilityResolver(app, dao.NewAdminAppGroupDAO(db), list)
+	return &CLI{Config: c, Wiki: wiki, close: func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}}, nil
+}
+
+// Close 关闭数据库连接
+func (c *CLI) Close() { c.close() }
@@ -12,6 +12,7 @@ import (
 	"go-apiadmin/internal/repository/postgres"
 	redisrepo "go-apiadmin/internal/repository/redis"
 	"go-apiadmin/internal/security/jwt"
+	"go-apiadmin/internal/service"
 	"net"
 	"time"
 
@@ -56,6 +57,7 @@ type App struct {
 	HTTP   *gin.Engine
 
 	AsyncAccessSender *kafka.AccessAsyncSender
+	WikiViews         *service.WikiViewService // wiki 浏览统计落库协程
 
 	serviceKey string
 	leaseID    clientv3.LeaseID
@@ -111,6 +113,11 @@ func NewApp(c *config.Config, l *logging.Logger, db *gorm.DB, r *redisrepo.Clien
 			&model.AdminInterfaceList{},
 			&model.AdminField{},
 			&model.AdminUserData{},
+			&model.AdminRateLimit{},
+			&model.AdminInterfaceRevision{},
+			&model.AdminContractEvent{},
+			&model.AdminTryItLog{},
+			&model.AdminWikiView{},
 		); err != nil {
 			l.Error("auto_migrate_failed", zap.Error(err))
 		}
@@ -272,6 +279,14 @@ func NewApp(c *config.Config, l *logging.Logger, db *gorm.DB, r *redisrepo.Clien
 }
 
 func (a *App) Close() {
+	// wiki 浏览统计最后一次落库（需在关闭 DB / Redis 之前）
+	if a.WikiViews != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := a.WikiViews.Close(ctx); err != nil {
+			a.Logger.Error("wiki_view_flush_error", zap.Error(err))
+		}
+		cancel()
+	}
 	// 优雅下线 etcd
 	if a.Etcd != nil && a.serviceKey != "" && a.leaseID != 0 {
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -23,8 +23,8 @@ import (
 func ProvideConfig(path string) (*config.Config, error) { return config.Load(path) }
 
 // ProvideRouter 装配路由；这里为注入后的 service 提供。
-func ProvideRouter(j *jwtsec.Manager, l *logging.Logger, p *kafka.Producer, aks *kafka.AccessAsyncSender, db *gorm.DB, r *redisrepo.Client, a *service.AuthService, u *service.UserService, perm *service.PermissionService, menu *service.MenuService, ag *service.AuthGroupService, ar *service.AuthRuleService, app *service.AppService, appg *service.AppGroupService, ifg *service.InterfaceGroupService, ifl *service.InterfaceListService, fields *service.FieldsService, logSvc *service.LogService, e *etcd.Client, c *config.Config, wiki *service.WikiService) *gin.Engine {
-	return httpSrv.NewRouter(j, l, p, aks, db, r, a, u, perm, menu, ag, ar, app, appg, ifg, ifl, fields, logSvc, e, c, wiki)
+func ProvideRouter(j *jwtsec.Manager, l *logging.Logger, p *kafka.Producer, aks *kafka.AccessAsyncSender, db *gorm.DB, r *redisrepo.Client, a *service.AuthService, u *service.UserService, perm *service.PermissionService, menu *service.MenuService, ag *service.AuthGroupService, ar *service.AuthRuleService, app *service.AppService, appg *service.AppGroupService, ifg *service.InterfaceGroupService, ifl *service.InterfaceListService, fields *service.FieldsService, logSvc *service.LogService, e *etcd.Client, c *config.Config, wiki *service.WikiService, gw *service.GatewayService, tok *service.AppTokenService, sign *service.AppSignService, rl *service.RateLimitService, imp *service.ImportService, rev *service.RevisionService, ct *service.ContractService, try *service.TryItService) *gin.Engine {
+	return httpSrv.NewRouter(j, l, p, aks, db, r, a, u, perm, menu, ag, ar, app, appg, ifg, ifl, fields, logSvc, e, c, wiki, gw, tok, sign, rl, imp, rev, ct, try)
 }
 
 func ProvideApp(c *config.Config, l *logging.Logger, db *gorm.DB, r *redisrepo.Client, k *kafka.Producer, e *etcd.Client, j *jwtsec.Manager, engine *gin.Engine) *App {
@@ -58,8 +58,13 @@ var ProviderSet = wire.NewSet(
 	dao.NewAdminGroupDAO, // 新增: WikiService 需要
 	dao.NewAdminInterfaceGroupDAO,
 	dao.NewAdminInterfaceListDAO,
+	dao.NewAdminRateLimitDAO,
 	dao.NewAdminFieldsDAO,
 	dao.NewAdminUserActionDAO, // 新增
+	dao.NewAdminInterfaceRevisionDAO,
+	dao.NewAdminContractEventDAO,
+	dao.NewAdminTryItLogDAO,
+	dao.NewAdminWikiViewDAO,
 	// Service (基础)
 	service.NewAuthService,
 	// 使用带缓存版本
@@ -76,6 +81,16 @@ var ProviderSet = wire.NewSet(
 	NewFieldsServiceDefault,
 	NewLogServiceDefault,
 	NewWikiServiceWithLayered,
+	NewGatewayServiceDefault,
+	NewAppTokenServiceDefault,
+	NewAppSignServiceDefault,
+	NewRateLimitServiceWithLayered,
+	NewImportServiceWithLayered,
+	NewWikiSearchIndexDefault,
+	NewRevisionServiceDefault,
+	NewContractServiceDefault,
+	NewTryItServiceDefault,
+	NewWikiViewServiceDefault,
 	ProvideAccessAsyncSender,
 	ProvideRouter,
 	ProvideApp,
@@ -88,14 +103,101 @@ func NewMenuServiceWithLayered(d *dao.AdminMenuDAO, lc cache.Cache) *service.Men
 func NewUserServiceWithLayered(u *dao.AdminUserDAO, g *dao.AdminAuthGroupDAO, gr *dao.AdminAuthGroupAccessDAO, db *gorm.DB, lc cache.Cache) *service.UserService {
 	return service.NewUserServiceWithCache(u, g, gr, db, lc)
 }
-func NewFieldsServiceDefault(d *dao.AdminFieldsDAO, ifl *dao.AdminInterfaceListDAO) *service.FieldsService {
-	return service.NewFieldsService(d, ifl)
+func NewFieldsServiceDefault(d *dao.AdminFieldsDAO, ifl *dao.AdminInterfaceListDAO, idx *service.WikiSearchIndex) *service.FieldsService {
+	s := service.NewFieldsService(d, ifl)
+	s.Search = idx
+	return s
 }
 func NewLogServiceDefault(d *dao.AdminUserActionDAO) *service.LogService {
 	return service.NewLogService(d)
 }
-func NewWikiServiceWithLayered(app *dao.AdminAppDAO, grp *dao.AdminGroupDAO, list *dao.AdminInterfaceListDAO, fields *dao.AdminFieldsDAO, lc cache.Cache) *service.WikiService {
-	return service.NewWikiService(app, grp, list, fields, lc)
+func NewWikiServiceWithLayered(app *dao.AdminAppDAO, appg *dao.AdminAppGroupDAO, grp *dao.AdminGroupDAO, list *dao.AdminInterfaceListDAO, fields *dao.AdminFieldsDAO, lc cache.Cache, idx *service.WikiSearchIndex, rev *service.RevisionService, ct *service.ContractService, views *service.WikiViewService) *service.WikiService {
+	s := service.NewWikiService(app, grp, list, fields, lc)
+	s.Index = idx
+	s.Visibility = service.NewVisibilityResolver(app, appg, list)
+	s.Revisions = rev
+	s.Contracts = ct
+	s.Views = views
+	return s
+}
+
+// NewWikiViewServiceDefault wiki 浏览统计；Redis 计数按配置间隔落库，App.Close 时停止并落库最后一次
+func NewWikiViewServiceDefault(c *config.Config, r *redisrepo.Client, d *dao.AdminWikiViewDAO, grp *dao.AdminGroupDAO) *service.WikiViewService {
+	s := service.NewWikiViewService(r, d, grp)
+	s.Start(time.Duration(c.Wiki.Views.FlushSec) * time.Second)
+	return s
+}
+
+// NewContractServiceDefault 接口契约检查；构造后挂到接口 / 字段服务，Kafka 可用时发送变更事件
+func NewContractServiceDefault(d *dao.AdminContractEventDAO, list *dao.AdminInterfaceListDAO, fields *dao.AdminFieldsDAO, app *dao.AdminAppDAO, p *kafka.Producer, ifl *service.InterfaceListService, fieldsSvc *service.FieldsService) *service.ContractService {
+	s := service.NewContractService(d, list, fields, app)
+	if p != nil {
+		s.Publisher = p
+	}
+	ifl.Contracts = s
+	fieldsSvc.Contracts = s
+	return s
+}
+
+// NewRevisionServiceDefault 接口修订；构造后挂到接口 / 字段服务，写操作时记录快照
+func NewRevisionServiceDefault(d *dao.AdminInterfaceRevisionDAO, ifl *service.InterfaceListService, fields *service.FieldsService) *service.RevisionService {
+	s := service.NewRevisionService(d, ifl, fields)
+	ifl.Revisions = s
+	fields.Revisions = s
+	return s
+}
+
+// NewWikiSearchIndexDefault wiki 搜索索引：接口 / 字段服务写入变更，wiki 检索读取；多节点经 Redis 变更流同步
+func NewWikiSearchIndexDefault(list *dao.AdminInterfaceListDAO, grp *dao.AdminGroupDAO, fields *dao.AdminFieldsDAO, r *redisrepo.Client) *service.WikiSearchIndex {
+	return service.NewWikiSearchIndex(list, grp, fields, r)
+}
+func NewGatewayServiceDefault(c *config.Config, ifl *service.InterfaceListService, e *etcd.Client, r *redisrepo.Client, lc cache.Cache) *service.GatewayService {
+	var up service.UpstreamResolver = &service.StaticUpstreams{Default: c.Gateway.DefaultUpstream, Targets: c.Gateway.Upstreams}
+	if c.Gateway.Discovery.Enable && e != nil {
+		up = service.NewDiscoveryUpstreams(etcd.NewWatcher(e), c.Gateway.Discovery.Env, c.Gateway.Discovery.Balance, up)
+	}
+	s := service.NewGatewayService(ifl, up, time.Duration(c.Gateway.TimeoutMS)*time.Millisecond, int64(c.Gateway.MaxBodyKB)*1024)
+	s.Retries = c.Gateway.Retries
+	s.RejectAfterSunset = c.Gateway.Lifecycle.RejectAfterSunset
+	s.Cache = service.NewGatewayCache(lc, r)
+	s.Stats = service.NewGatewayStatsService(r)
+	ifl.Usage = s.Stats // 废弃接口调用方统计由网关写入、后台查询
+	if c.Gateway.Breaker.Enable {
+		s.Breakers = service.NewBreakerGroup(service.BreakerConfig{
+			FailureThreshold: c.Gateway.Breaker.FailureThreshold,
+			OpenTimeout:      time.Duration(c.Gateway.Breaker.OpenSec) * time.Second,
+			HalfOpenMax:      c.Gateway.Breaker.HalfOpenMax,
+		})
+	}
+	return s
+}
+func NewAppTokenServiceDefault(c *config.Config, app *dao.AdminAppDAO, r *redisrepo.Client, lc cache.Cache) *service.AppTokenService {
+	return service.NewAppTokenService(app, r, lc, time.Duration(c.Gateway.TokenTTLSec)*time.Second)
+}
+
+// NewTryItServiceDefault wiki 在线调试；未配置 base_url 时调用本服务网关，关闭或无可用网关时返回 nil
+func NewTryItServiceDefault(c *config.Config, wiki *service.WikiService, fields *service.FieldsService, tok *service.AppTokenService, d *dao.AdminTryItLogDAO, r *redisrepo.Client) *service.TryItService {
+	t := c.Wiki.TryIt
+	if !t.Enable {
+		return nil
+	}
+	base := t.BaseURL
+	if base == "" && c.Gateway.Enable {
+		base = service.LoopbackURL(c.HTTP.Addr)
+	}
+	if base == "" {
+		return nil
+	}
+	return service.NewTryItService(wiki, fields, tok, d, r, base, time.Duration(t.TimeoutMS)*time.Millisecond, t.RatePerMin, int64(t.MaxBodyKB)<<10)
+}
+func NewAppSignServiceDefault(c *config.Config, tok *service.AppTokenService, r *redisrepo.Client) *service.AppSignService {
+	return service.NewAppSignService(tok, r, time.Duration(c.Gateway.SignWindowSec)*time.Second)
+}
+func NewRateLimitServiceWithLayered(d *dao.AdminRateLimitDAO, r *redisrepo.Client, lc cache.Cache) *service.RateLimitService {
+	return service.NewRateLimitService(d, r, lc)
+}
+func NewImportServiceWithLayered(ifl *service.InterfaceListService, fields *service.FieldsService, g *dao.AdminGroupDAO) *service.ImportService {
+	return service.NewImportService(ifl, fields, g)
 }
 func NewAppServiceWithLayered(d *dao.AdminAppDAO, g *dao.AdminAppGroupDAO, c cache.Cache) *service.AppService {
 	return service.NewAppServiceWithCache(d, g, c)
@@ -112,8 +214,11 @@ func NewAppGroupServiceWithLayered(d *dao.AdminAppGroupDAO, c cache.Cache) *serv
 func NewInterfaceGroupServiceWithLayered(d *dao.AdminInterfaceGroupDAO, c cache.Cache) *service.InterfaceGroupService {
 	return service.NewInterfaceGroupServiceWithCache(d, c)
 }
-func NewInterfaceListServiceWithLayered(d *dao.AdminInterfaceListDAO, c cache.Cache) *service.InterfaceListService {
-	return service.NewInterfaceListServiceWithCache(d, c)
+func NewInterfaceListServiceWithLayered(d *dao.AdminInterfaceListDAO, app *dao.AdminAppDAO, c cache.Cache, idx *service.WikiSearchIndex) *service.InterfaceListService {
+	s := service.NewInterfaceListServiceWithCache(d, c)
+	s.Search = idx
+	s.AppDAO = app
+	return s
 }
 func NewPermissionServiceWithLayered(gr *dao.AdminAuthGroupAccessDAO, rule *dao.AdminAuthRuleDAO, u *dao.AdminUserDAO, m *dao.AdminMenuDAO, r *redisrepo.Client, c cache.Cache) *service.PermissionService {
 	return service.NewPermissionServiceWithCache(gr, rule, u, m, r, c)
@@ -52,16 +52,32 @@ func InitApp(configPath string) (*App, error) {
 	adminInterfaceGroupDAO := dao.NewAdminInterfaceGroupDAO(db)
 	interfaceGroupService := NewInterfaceGroupServiceWithLayered(adminInterfaceGroupDAO, cache)
 	adminInterfaceListDAO := dao.NewAdminInterfaceListDAO(db)
-	interfaceListService := NewInterfaceListServiceWithLayered(adminInterfaceListDAO, cache)
+	adminGroupDAO := dao.NewAdminGroupDAO(db)
 	adminFieldsDAO := dao.NewAdminFieldsDAO(db)
-	fieldsService := NewFieldsServiceDefault(adminFieldsDAO, adminInterfaceListDAO)
+	wikiSearchIndex := NewWikiSearchIndexDefault(adminInterfaceListDAO, adminGroupDAO, adminFieldsDAO, client)
+	interfaceListService := NewInterfaceListServiceWithLayered(adminInterfaceListDAO, adminAppDAO, cache, wikiSearchIndex)
+	fieldsService := NewFieldsServiceDefault(adminFieldsDAO, adminInterfaceListDAO, wikiSearchIndex)
 	adminUserActionDAO := dao.NewAdminUserActionDAO(db)
 	logService := NewLogServiceDefault(adminUserActionDAO)
-	adminGroupDAO := dao.NewAdminGroupDAO(db)
-	wikiService := NewWikiServiceWithLayered(adminAppDAO, adminGroupDAO, adminInterfaceListDAO, adminFieldsDAO, cache)
+	adminInterfaceRevisionDAO := dao.NewAdminInterfaceRevisionDAO(db)
+	revisionService := NewRevisionServiceDefault(adminInterfaceRevisionDAO, interfaceListService, fieldsService)
+	adminContractEventDAO := dao.NewAdminContractEventDAO(db)
+	contractService := NewContractServiceDefault(adminContractEventDAO, adminInterfaceListDAO, adminFieldsDAO, adminAppDAO, producer, interfaceListService, fieldsService)
+	adminWikiViewDAO := dao.NewAdminWikiViewDAO(db)
+	wikiViewService := NewWikiViewServiceDefault(config, client, adminWikiViewDAO, adminGroupDAO)
+	wikiService := NewWikiServiceWithLayered(adminAppDAO, adminAppGroupDAO, adminGroupDAO, adminInterfaceListDAO, adminFieldsDAO, cache, wikiSearchIndex, revisionService, contractService, wikiViewService)
+	gatewayService := NewGatewayServiceDefault(config, interfaceListService, etcdClient, client, cache)
+	appTokenService := NewAppTokenServiceDefault(config, adminAppDAO, client, cache)
+	appSignService := NewAppSignServiceDefault(config, appTokenService, client)
+	adminRateLimitDAO := dao.NewAdminRateLimitDAO(db)
+	rateLimitService := NewRateLimitServiceWithLayered(adminRateLimitDAO, client, cache)
+	importService := NewImportServiceWithLayered(interfaceListService, fieldsService, adminGroupDAO)
+	adminTryItLogDAO := dao.NewAdminTryItLogDAO(db)
+	tryItService := NewTryItServiceDefault(config, wikiService, fieldsService, appTokenService, adminTryItLogDAO, client)
 	accessAsyncSender := ProvideAccessAsyncSender(config, producer, logger)
-	engine := ProvideRouter(manager, logger, producer, accessAsyncSender, db, client, authService, userService, permissionService, menuService, authGroupService, authRuleService, appService, appGroupService, interfaceGroupService, interfaceListService, fieldsService, logService, etcdClient, config, wikiService)
+	engine := ProvideRouter(manager, logger, producer, accessAsyncSender, db, client, authService, userService, permissionService, menuService, authGroupService, authRuleService, appService, appGroupService, interfaceGroupService, interfaceListService, fieldsService, logService, etcdClient, config, wikiService, gatewayService, appTokenService, appSignService, rateLimitService, importService, revisionService, contractService, tryItService)
 	app := ProvideApp(config, logger, db, client, producer, etcdClient, manager, engine)
 	app.AsyncAccessSender = accessAsyncSender
+	app.WikiViews = wikiViewService
 	return app, nil
 }
@@ -68,7 +68,17 @@ type Config struct {
 		Env     string `mapstructure:"env"` // 新增: 运行环境 dev|staging|prod 等
 	} `mapstructure:"app_meta"`
 	Wiki struct {
-		OnlineTimeSeconds int `mapstructure:"online_time_seconds"`
+		OnlineTimeSeconds int      `mapstructure:"online_time_seconds"`
+		TryIt             struct { // 新增: wiki 在线调试 /wiki/tryIt
+			Enable     bool   `mapstructure:"enable"`
+			BaseURL    string `mapstructure:"base_url"`     // 网关地址（请求 {base_url}/api/{hash}），空则调用本服务网关
+			TimeoutMS  int    `mapstructure:"timeout_ms"`   // 单次调试超时
+			RatePerMin int    `mapstructure:"rate_per_min"` // 每个应用 / 后台用户每分钟调试次数
+			MaxBodyKB  int    `mapstructure:"max_body_kb"`  // 返回给调试页的响应体上限
+		} `mapstructure:"try_it"`
+		Views struct { // 新增: wiki 浏览统计（Redis 小时桶，定期落库）
+			FlushSec int `mapstructure:"flush_sec"` // 落库间隔
+		} `mapstructure:"views"`
 	} `mapstructure:"wiki"`
 	Upload struct { // 新增: 上传相关限制
 		MaxSizeMB  int      `mapstructure:"max_size_mb"`
@@ -80,6 +90,32 @@ type Config struct {
 		SamplerRatio float64 `mapstructure:"sampler_ratio"`
 		Enable       bool    `mapstructure:"enable"`
 	} `mapstructure:"otel"`
+	Gateway struct { // 新增: 接口网关 /api/{hash}，替代 PHP 运行时转发
+		Enable          bool              `mapstructure:"enable"`
+		TimeoutMS       int               `mapstructure:"timeout_ms"`       // 后端调用超时
+		MaxBodyKB       int               `mapstructure:"max_body_kb"`      // 请求/响应体读取上限
+		DefaultUpstream string            `mapstructure:"default_upstream"` // 未匹配服务名时使用的后端 base URL
+		Upstreams       map[string]string `mapstructure:"upstreams"`        // 服务名(api_class 首段, 小写) -> 后端 base URL
+		TokenTTLSec     int               `mapstructure:"token_ttl_sec"`    // 应用 access_token 有效期
+		SignWindowSec   int               `mapstructure:"sign_window_sec"`  // 签名时间戳容忍窗口（应用未单独配置时）
+		MockHeader      string            `mapstructure:"mock_header"`      // 请求头为 1/true 时返回模拟数据，空则仅按 is_test
+		CallLog         bool              `mapstructure:"call_log"`         // 网关调用日志(Kafka)与应用/接口分钟统计(Redis)
+		Retries         int               `mapstructure:"retries"`          // 幂等方法(GET/HEAD/OPTIONS/PUT/DELETE)失败重试次数
+		Breaker         struct {
+			Enable           bool `mapstructure:"enable"`
+			FailureThreshold int  `mapstructure:"failure_threshold"` // 连续失败次数达到后打开
+			OpenSec          int  `mapstructure:"open_sec"`          // 打开持续时间，之后进入半开探测
+			HalfOpenMax      int  `mapstructure:"half_open_max"`     // 半开期间放行的探测请求数，全部成功后关闭
+		} `mapstructure:"breaker"`
+		Discovery struct {
+			Enable  bool   `mapstructure:"enable"`  // 通过 etcd /services/{service}/{env}/ 发现后端，无实例时回退静态配置
+			Env     string `mapstructure:"env"`     // 实例环境段，缺省取 app.env
+			Balance string `mapstructure:"balance"` // round_robin | weighted
+		} `mapstructure:"discovery"`
+		Lifecycle struct {
+			RejectAfterSunset bool `mapstructure:"reject_after_sunset"` // 废弃接口过了 sunset_at 后拒绝调用（API_SUNSET），retired 接口始终拒绝
+		} `mapstructure:"lifecycle"`
+	} `mapstructure:"gateway"`
 }
 
 func Load(path string) (*Config, error) {
@@ -90,6 +126,10 @@ func Load(path string) (*Config, error) {
 	}
 	// 默认值
 	v.SetDefault("wiki.online_time_seconds", 86400)
+	v.SetDefault("wiki.try_it.enable", true)
+	v.SetDefault("wiki.try_it.timeout_ms", 10000)
+	v.SetDefault("wiki.try_it.rate_per_min", 20)
+	v.SetDefault("wiki.try_it.max_body_kb", 256)
 	v.SetDefault("app_meta.name", "GOAPIAdmin")
 	v.SetDefault("app_meta.version", "v1")
 	v.SetDefault("app_meta.env", "dev")
@@ -117,6 +157,22 @@ func Load(path string) (*Config, error) {
 	v.SetDefault("auth.max_multi_sessions", 0)
 	// Etcd 默认
 	v.SetDefault("etcd.heartbeat_seconds", 10)
+	// Gateway 默认
+	v.SetDefault("gateway.enable", false)
+	v.SetDefault("gateway.timeout_ms", 3000)
+	v.SetDefault("gateway.max_body_kb", 1024)
+	v.SetDefault("gateway.token_ttl_sec", 7200)
+	v.SetDefault("gateway.sign_window_sec", 300)
+	v.SetDefault("gateway.mock_header", "X-Api-Mock")
+	v.SetDefault("gateway.call_log", true)
+	v.SetDefault("gateway.retries", 1)
+	v.SetDefault("gateway.breaker.enable", true)
+	v.SetDefault("gateway.breaker.failure_threshold", 5)
+	v.SetDefault("gateway.breaker.open_sec", 30)
+	v.SetDefault("gateway.breaker.half_open_max", 1)
+	v.SetDefault("gateway.discovery.enable", false)
+	v.SetDefault("gateway.discovery.balance", "round_robin")
+	v.SetDefault("gateway.lifecycle.reject_after_sunset", false)
 	var c Config
 	if err := v.Unmarshal(&c); err != nil {
 		return nil, err
@@ -187,5 +243,49 @@ func Load(path string) (*Config, error) {
 	if c.Auth.MaxMultiSessions < 0 {
 		c.Auth.MaxMultiSessions = 0
 	}
+	// Gateway 容错
+	if c.Gateway.TimeoutMS <= 0 {
+		c.Gateway.TimeoutMS = 3000
+	}
+	if c.Gateway.MaxBodyKB <= 0 {
+		c.Gateway.MaxBodyKB = 1024
+	}
+	if c.Gateway.TokenTTLSec <= 0 {
+		c.Gateway.TokenTTLSec = 7200
+	}
+	if c.Gateway.SignWindowSec <= 0 {
+		c.Gateway.SignWindowSec = 300
+	}
+	if c.Gateway.Retries < 0 {
+		c.Gateway.Retries = 0
+	}
+	if c.Gateway.Breaker.FailureThreshold <= 0 {
+		c.Gateway.Breaker.FailureThreshold = 5
+	}
+	if c.Gateway.Breaker.OpenSec <= 0 {
+		c.Gateway.Breaker.OpenSec = 30
+	}
+	if c.Gateway.Breaker.HalfOpenMax <= 0 {
+		c.Gateway.Breaker.HalfOpenMax = 1
+	}
+	if c.Gateway.Discovery.Env == "" {
+		c.Gateway.Discovery.Env = c.AppMeta.Env
+	}
+	if c.Gateway.Discovery.Balance != "weighted" {
+		c.Gateway.Discovery.Balance = "round_robin"
+	}
+	// Wiki 在线调试容错
+	if c.Wiki.TryIt.TimeoutMS <= 0 {
+		c.Wiki.TryIt.TimeoutMS = 10000
+	}
+	if c.Wiki.TryIt.RatePerMin <= 0 {
+		c.Wiki.TryIt.RatePerMin = 20
+	}
+	if c.Wiki.TryIt.MaxBodyKB <= 0 {
+		c.Wiki.TryIt.MaxBodyKB = 256
+	}
+	if c.Wiki.Views.FlushSec <= 0 {
+		c.Wiki.Views.FlushSec = 60
+	}
 	return &c, nil
 }
@@ -0,0 +1,183 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Instance 服务实例（由注册 key/value 解析）
+type Instance struct {
+	Key    string `json:"key"`
+	Addr   string `json:"addr"`   // base URL，如 http://10.0.0.1:8080
+	Weight int    `json:"weight"` // 权重，缺省 1
+}
+
+// Watcher 按前缀维护服务实例列表：首次访问时 Get 全量并启动 Watch 增量同步，
+// 租约过期产生的 DELETE 事件会移除实例。
+type Watcher struct {
+	cli    *Client
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.RWMutex
+	sets   map[string]*instanceSet
+}
+
+type instanceSet struct {
+	mu    sync.RWMutex
+	ready chan struct{}
+	err   error
+	items map[string]Instance
+	list  []Instance // 按 key 排序的快照，供负载均衡读取
+}
+
+func NewWatcher(cli *Client) *Watcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Watcher{cli: cli, ctx: ctx, cancel: cancel, sets: map[string]*instanceSet{}}
+}
+
+// Instances 返回前缀下当前实例快照（调用方不可修改）
+func (w *Watcher) Instances(ctx context.Context, prefix string) ([]Instance, error) {
+	w.mu.Lock()
+	set, ok := w.sets[prefix]
+	if !ok {
+		set = &instanceSet{ready: make(chan struct{}), items: map[string]Instance{}}
+		w.sets[prefix] = set
+		go w.run(prefix, set)
+	}
+	w.mu.Unlock()
+	select {
+	case <-set.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	if set.err != nil && len(set.list) == 0 {
+		return nil, set.err
+	}
+	return set.list, nil
+}
+
+// Close 停止全部 watch
+func (w *Watcher) Close() { w.cancel() }
+
+// run 全量加载 + 增量监听；watch 中断（压缩/网络）后重新全量加载
+func (w *Watcher) run(prefix string, set *instanceSet) {
+	first := true
+	for w.ctx.Err() == nil {
+		resp, err := w.cli.Client.Get(w.ctx, prefix, clientv3.WithPrefix())
+		if err != nil {
+			set.mu.Lock()
+			set.err = err
+			set.mu.Unlock()
+			if first {
+				close(set.ready)
+				first = false
+			}
+			// 首次失败由调用方降级；随后每秒重试
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-w.cli.Client.Ctx().Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		items := map[string]Instance{}
+		for _, kv := range resp.Kvs {
+			if inst, ok := parseInstance(string(kv.Key), kv.Value); ok {
+				items[inst.Key] = inst
+			}
+		}
+		set.replace(items, nil)
+		if first {
+			close(set.ready)
+			first = false
+		}
+		wch := w.cli.Client.Watch(w.ctx, prefix, clientv3.WithPrefix(), clientv3.WithRev(resp.Header.Revision+1))
+		for wr := range wch {
+			if wr.Err() != nil {
+				break
+			}
+			set.apply(wr.Events)
+		}
+		if w.cli.Client.Ctx().Err() != nil {
+			return
+		}
+	}
+}
+
+func (s *instanceSet) replace(items map[string]Instance, err error) {
+	s.mu.Lock()
+	s.items, s.err = items, err
+	s.rebuild()
+	s.mu.Unlock()
+}
+
+func (s *instanceSet) apply(events []*clientv3.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ev := range events {
+		key := string(ev.Kv.Key)
+		if ev.Type == clientv3.EventTypeDelete {
+			delete(s.items, key)
+			continue
+		}
+		if inst, ok := parseInstance(key, ev.Kv.Value); ok {
+			s.items[key] = inst
+		}
+	}
+	s.rebuild()
+}
+
+func (s *instanceSet) rebuild() {
+	list := make([]Instance, 0, len(s.items))
+	for _, inst := range s.items {
+		list = append(list, inst)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	s.list = list
+}
+
+// parseInstance 兼容 NewApp 注册的 JSON 元数据（ip/port），可选 scheme/url/weight；
+// 非 JSON 值视为地址，均缺失时取 key 末段 ip:port。
+func parseInstance(key string, val []byte) (Instance, bool) {
+	inst := Instance{Key: key, Weight: 1}
+	var meta map[string]interface{}
+	if json.Unmarshal(val, &meta) == nil {
+		if w, ok := meta["weight"].(float64); ok && w > 0 {
+			inst.Weight = int(w)
+		}
+		if u, ok := meta["url"].(string); ok && u != "" {
+			inst.Addr = u
+		} else if ip, _ := meta["ip"].(string); ip != "" {
+			scheme, _ := meta["scheme"].(string)
+			if scheme == "" {
+				scheme = "http"
+			}
+			inst.Addr = fmt.Sprintf("%s://%s:%v", scheme, ip, meta["port"])
+		}
+	} else if v := strings.TrimSpace(string(val)); v != "" {
+		inst.Addr = v
+	}
+	if inst.Addr == "" {
+		if i := strings.LastIndex(key, "/"); i >= 0 && i < len(key)-1 {
+			inst.Addr = "http://" + key[i+1:]
+		}
+	}
+	if inst.Addr == "" {
+		return inst, false
+	}
+	if !strings.Contains(inst.Addr, "://") {
+		inst.Addr = "http://" + inst.Addr
+	}
+	return inst, true
+}
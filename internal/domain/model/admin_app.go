@@ -14,6 +14,10 @@ type AdminApp struct {
 	AppGroup   string `gorm:"column:app_group" json:"app_group"`
 	AppAddTime int64  `gorm:"column:app_add_time" json:"app_add_time"`
 	AppAPIShow string `gorm:"column:app_api_show" json:"app_api_show"`
+	ShowMode   int8   `gorm:"column:show_mode;default:0" json:"show_mode"`       // 文档可见范围：0 仅 app_api_show；1 继承应用分组可见范围，app_api_show 追加、app_api_hide 排除
+	AppAPIHide string `gorm:"column:app_api_hide;type:text" json:"app_api_hide"` // 继承时排除的接口 {group_hash: [hash|"*"]}
+	SignEnable int8   `gorm:"column:sign_enable;default:0" json:"sign_enable"`   // 1 网关请求需 HMAC 签名
+	SignWindow int    `gorm:"column:sign_window;default:0" json:"sign_window"`   // 时间戳容忍窗口(秒)，0 使用全局配置
 }
 
 func (AdminApp) TableName() string { return "admin_app" }
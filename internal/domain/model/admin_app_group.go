@@ -8,6 +8,7 @@ type AdminAppGroup struct {
 	Description string `gorm:"column:description" json:"description"`
 	Status      int8   `gorm:"column:status" json:"status"`
 	Hash        string `gorm:"column:hash" json:"hash"`
+	APIShow     string `gorm:"column:api_show;type:text" json:"api_show"` // 可见范围模板 {group_hash: [hash|"*"]}，show_mode=1 的应用继承
 }
 
 func (AdminAppGroup) TableName() string { return "admin_app_group" }
@@ -0,0 +1,17 @@
+package model
+
+// AdminContractEvent 接口契约变更事件：每次接口 / 字段编辑按当前契约比对后记录，供应用拉取与消息通知
+
+type AdminContractEvent struct {
+	ID       int64  `gorm:"primaryKey" json:"id"`
+	Hash     string `gorm:"column:hash;size:50;index" json:"hash"`
+	APIClass string `gorm:"column:api_class;size:50" json:"api_class"`
+	Source   string `gorm:"column:source;size:20" json:"source"`   // edit / field_add / field_edit / field_delete / batch_upload
+	Breaking int8   `gorm:"column:breaking;index" json:"breaking"` // 1 含破坏性变更（已确认）
+	Changes  string `gorm:"column:changes;type:text" json:"-"`     // JSON([]ContractChange)
+	Apps     string `gorm:"column:apps;type:text" json:"apps"`     // 受影响应用 app_id（app_api 已授权），逗号分隔
+	UserID   int64  `gorm:"column:user_id" json:"user_id"`         // 操作人
+	AddTime  int64  `gorm:"column:add_time;index" json:"add_time"`
+}
+
+func (AdminContractEvent) TableName() string { return "admin_contract_event" }
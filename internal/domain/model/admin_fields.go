@@ -6,6 +6,7 @@ package model
 type AdminField struct {
 	ID        int64  `gorm:"primaryKey" json:"id"`
 	FieldName string `gorm:"column:field_name;size:50" json:"field_name"`
+	Path      string `gorm:"column:path;size:255" json:"path"` // 完整 JSON 路径（如 data.list[].id），为空的旧数据按 field_name 视为顶层字段
 	Hash      string `gorm:"column:hash;size:50;index" json:"hash"`
 	DataType  int8   `gorm:"column:data_type" json:"data_type"`
 	Default   string `gorm:"column:default;size:500" json:"default"`
@@ -14,7 +14,16 @@ type AdminInterfaceList struct {
 	IsTest      int8   `gorm:"column:is_test" json:"is_test"`
 	ReturnStr   string `gorm:"column:return_str" json:"return_str"`
 	GroupHash   string `gorm:"column:group_hash;size:64" json:"group_hash"`
-	HashType    int8   `gorm:"column:hash_type" json:"hash_type"` // 1 普通 2 加密
+	HashType    int8   `gorm:"column:hash_type" json:"hash_type"`             // 1 普通 2 加密
+	TimeoutMS   int    `gorm:"column:timeout_ms;default:0" json:"timeout_ms"` // 网关调用超时(毫秒)，0 使用全局配置
+	CacheTTL    int    `gorm:"column:cache_ttl;default:0" json:"cache_ttl"`   // 网关响应缓存秒数，0 不缓存
+	CacheKeys   string `gorm:"column:cache_keys;size:255" json:"cache_keys"`  // 缓存 key 参与的 query 参数(逗号分隔, 空或 * 全部；调用应用始终参与)
+	CacheVary   string `gorm:"column:cache_vary;size:255" json:"cache_vary"`  // 缓存 key 参与的请求头(逗号分隔)
+	// 生命周期 draft / active / deprecated / retired，空视为 active
+	Lifecycle    string `gorm:"column:lifecycle;size:16;default:'active'" json:"lifecycle"`
+	DeprecatedAt int64  `gorm:"column:deprecated_at;default:0" json:"deprecated_at"` // 标记废弃时间(unix 秒)
+	SunsetAt     int64  `gorm:"column:sunset_at;default:0" json:"sunset_at"`         // 计划下线时间(unix 秒)，0 未定
+	Replacement  string `gorm:"column:replacement;size:50" json:"replacement"`       // 替代接口 hash
 }
 
 func (AdminInterfaceList) TableName() string { return "admin_list" }
@@ -0,0 +1,17 @@
+package model
+
+// AdminInterfaceRevision 接口定义修订快照：接口属性 + 请求 / 响应字段，每次变更递增 version
+
+type AdminInterfaceRevision struct {
+	ID       int64  `gorm:"primaryKey" json:"id"`
+	Hash     string `gorm:"column:hash;size:50;uniqueIndex:uk_revision_hash_version" json:"hash"`
+	Version  int    `gorm:"column:version;uniqueIndex:uk_revision_hash_version" json:"version"`
+	Action   string `gorm:"column:action;size:20" json:"action"`    // baseline / add / edit / status / delete / fields / import / rollback
+	Summary  string `gorm:"column:summary;size:500" json:"summary"` // 相对上一版本的变更摘要
+	Snapshot string `gorm:"column:snapshot;type:text" json:"-"`     // JSON(InterfaceSnapshot)
+	Digest   string `gorm:"column:digest;size:40" json:"-"`         // 快照摘要，内容未变时不生成新版本
+	UserID   int64  `gorm:"column:user_id" json:"user_id"`          // 操作人，0 为系统
+	AddTime  int64  `gorm:"column:add_time;index" json:"add_time"`
+}
+
+func (AdminInterfaceRevision) TableName() string { return "admin_interface_revision" }
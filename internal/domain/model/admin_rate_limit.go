@@ -0,0 +1,18 @@
+package model
+
+// AdminRateLimit 网关限流/配额规则
+// app_id 与 hash 组合决定作用域：仅 app_id 为应用级，仅 hash 为接口级，两者皆有为应用+接口级
+
+type AdminRateLimit struct {
+	ID           int64  `gorm:"primaryKey" json:"id"`
+	AppID        string `gorm:"column:app_id;size:64;index" json:"app_id"`
+	Hash         string `gorm:"column:hash;size:50;index" json:"hash"`
+	Rate         int    `gorm:"column:rate" json:"rate"`                   // 每秒令牌数，0 不限速
+	Burst        int    `gorm:"column:burst" json:"burst"`                 // 令牌桶容量，0 取 rate
+	DailyQuota   int64  `gorm:"column:daily_quota" json:"daily_quota"`     // 每日调用上限，0 不限
+	MonthlyQuota int64  `gorm:"column:monthly_quota" json:"monthly_quota"` // 每月调用上限，0 不限
+	Status       int8   `gorm:"column:status" json:"status"`               // 1 启用 0 停用，新增时缺省为 1
+	AddTime      int64  `gorm:"column:add_time" json:"add_time"`
+}
+
+func (AdminRateLimit) TableName() string { return "admin_rate_limit" }
@@ -0,0 +1,22 @@
+package model
+
+// AdminTryItLog wiki 在线调试审计记录：谁以哪个应用身份调用了哪个接口、请求内容与结果
+
+type AdminTryItLog struct {
+	ID         int64  `gorm:"primaryKey" json:"id"`
+	AppID      string `gorm:"column:app_id;size:50;index" json:"app_id"` // 调用身份，后台用户未指定应用时为空
+	UserID     int64  `gorm:"column:user_id" json:"user_id"`             // wiki 登录用户（应用为 admin_app.id，后台为 admin_user.id）
+	Admin      int8   `gorm:"column:admin" json:"admin"`                 // 1 后台登录
+	Hash       string `gorm:"column:hash;size:50;index" json:"hash"`
+	APIClass   string `gorm:"column:api_class;size:50" json:"api_class"`
+	Method     string `gorm:"column:method;size:10" json:"method"`
+	URL        string `gorm:"column:url;size:1000" json:"url"`
+	Params     string `gorm:"column:params;type:text" json:"params"` // 实际发送的参数 JSON（截断 4KB）
+	Status     int    `gorm:"column:status" json:"status"`           // HTTP 状态码，请求失败为 0
+	DurationMS int64  `gorm:"column:duration_ms" json:"duration_ms"`
+	Error      string `gorm:"column:error;size:500" json:"error"`
+	ClientIP   string `gorm:"column:client_ip;size:64" json:"client_ip"`
+	AddTime    int64  `gorm:"column:add_time;index" json:"add_time"`
+}
+
+func (AdminTryItLog) TableName() string { return "admin_tryit_log" }
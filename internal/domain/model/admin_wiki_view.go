@@ -0,0 +1,15 @@
+package model
+
+// AdminWikiView wiki 接口浏览量按小时 / 接口 / 浏览应用聚合：Redis 实时计数，定期整桶落库（同一小时重复落库覆盖）
+
+type AdminWikiView struct {
+	ID         int64  `gorm:"primaryKey" json:"id"`
+	Hour       int64  `gorm:"column:hour;uniqueIndex:uk_wiki_view,priority:1" json:"hour"` // 小时起点 unix 秒
+	Hash       string `gorm:"column:hash;size:50;uniqueIndex:uk_wiki_view,priority:2" json:"hash"`
+	AppID      string `gorm:"column:app_id;size:50;uniqueIndex:uk_wiki_view,priority:3" json:"app_id"` // 浏览应用，后台登录为 -1
+	GroupHash  string `gorm:"column:group_hash;size:50;index" json:"group_hash"`
+	Views      int64  `gorm:"column:views" json:"views"`
+	UpdateTime int64  `gorm:"column:update_time" json:"update_time"`
+}
+
+func (AdminWikiView) TableName() string { return "admin_wiki_view" }
@@ -73,6 +73,30 @@ var (
 		Name: "cache_nil_sentinel_hit_total",
 		Help: "Hits of nil sentinel (empty protection)",
 	})
+	GatewayRejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_rejected_total",
+		Help: "Gateway calls rejected by rate limit or quota",
+	}, []string{"reason", "scope"}) // reason=rate|daily|monthly scope=app|api|app_api
+	GatewayBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_breaker_state",
+		Help: "Gateway upstream circuit breaker state (0=closed 1=half_open 2=open)",
+	}, []string{"upstream"})
+	GatewayBreakerRejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_breaker_rejected_total",
+		Help: "Gateway calls short-circuited by an open breaker",
+	}, []string{"upstream"})
+	GatewayCacheTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_cache_total",
+		Help: "Gateway response cache operations",
+	}, []string{"result"}) // result=hit|miss|store|purge
+	GatewayRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_retries_total",
+		Help: "Gateway upstream retry attempts",
+	}, []string{"service"})
+	WikiViewFlushTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "wiki_view_flush_total",
+		Help: "Wiki view counter flushes from Redis to Postgres",
+	}, []string{"result"}) // result=ok|error
 	PermissionInvalidateTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "permission_invalidate_total",
 		Help: "Permission cache invalidation count by mode (single/group/all)",
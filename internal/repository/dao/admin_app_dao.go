@@ -87,6 +87,16 @@ func (d *AdminAppDAO) UpdateSecret(ctx context.Context, id int64, secret string)
 	return d.DB.WithContext(ctx).Model(&model.AdminApp{}).Where("id=?", id).Update("app_secret", secret).Error
 }
 
+// UpdateSign 更新签名配置（显式写入零值）
+func (d *AdminAppDAO) UpdateSign(ctx context.Context, id int64, enable int8, window int) error {
+	return d.DB.WithContext(ctx).Model(&model.AdminApp{}).Where("id=?", id).Updates(map[string]interface{}{"sign_enable": enable, "sign_window": window}).Error
+}
+
+// UpdateVisibility 更新文档可见范围（显式写入零值 / 空串）
+func (d *AdminAppDAO) UpdateVisibility(ctx context.Context, id int64, mode int8, show, hide string) error {
+	return d.DB.WithContext(ctx).Model(&model.AdminApp{}).Where("id=?", id).Updates(map[string]interface{}{"show_mode": mode, "app_api_show": show, "app_api_hide": hide}).Error
+}
+
 // BulkByIDs 批量载入
 func (d *AdminAppDAO) BulkByIDs(ctx context.Context, ids []int64) (map[int64]model.AdminApp, error) {
 	res := make(map[int64]model.AdminApp)
@@ -103,6 +113,22 @@ func (d *AdminAppDAO) BulkByIDs(ctx context.Context, ids []int64) (map[int64]mod
 	return res, nil
 }
 
+// BulkByAppIDs 按 app_id 批量查询
+func (d *AdminAppDAO) BulkByAppIDs(ctx context.Context, appIDs []string) (map[string]model.AdminApp, error) {
+	res := make(map[string]model.AdminApp)
+	if len(appIDs) == 0 {
+		return res, nil
+	}
+	var list []model.AdminApp
+	if err := d.DB.WithContext(ctx).Where("app_id IN ?", appIDs).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, m := range list {
+		res[m.AppID] = m
+	}
+	return res, nil
+}
+
 // SearchAppIDs 用于校验 app_id 是否冲突
 func (d *AdminAppDAO) SearchAppIDs(ctx context.Context, prefix string) ([]string, error) {
 	var list []string
@@ -111,3 +137,12 @@ func (d *AdminAppDAO) SearchAppIDs(ctx context.Context, prefix string) ([]string
 	}
 	return list, nil
 }
+
+// ListByAPIHash app_api 中包含接口 hash 的应用（LIKE 粗筛，调用方按逗号精确匹配）
+func (d *AdminAppDAO) ListByAPIHash(ctx context.Context, hash string) ([]model.AdminApp, error) {
+	var list []model.AdminApp
+	if err := d.DB.WithContext(ctx).Where("app_api LIKE ?", "%"+hash+"%").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -23,6 +23,16 @@ func (d *AdminAppGroupDAO) FindByID(ctx context.Context, id int64) (*model.Admin
 	}
 	return &g, nil
 }
+func (d *AdminAppGroupDAO) FindByHash(ctx context.Context, hash string) (*model.AdminAppGroup, error) {
+	var g model.AdminAppGroup
+	if err := d.DB.WithContext(ctx).Where("hash=?", hash).First(&g).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &g, nil
+}
 func (d *AdminAppGroupDAO) List(ctx context.Context) ([]model.AdminAppGroup, error) {
 	var list []model.AdminAppGroup
 	if err := d.DB.WithContext(ctx).Order("id DESC").Find(&list).Error; err != nil {
@@ -42,3 +52,8 @@ func (d *AdminAppGroupDAO) Delete(ctx context.Context, id int64) error {
 func (d *AdminAppGroupDAO) UpdateStatus(ctx context.Context, id int64, st int8) error {
 	return d.DB.WithContext(ctx).Model(&model.AdminAppGroup{}).Where("id=?", id).Update("status", st).Error
 }
+
+// UpdateAPIShow 更新可见范围模板（显式写入空串）
+func (d *AdminAppGroupDAO) UpdateAPIShow(ctx context.Context, id int64, show string) error {
+	return d.DB.WithContext(ctx).Model(&model.AdminAppGroup{}).Where("id=?", id).Update("api_show", show).Error
+}
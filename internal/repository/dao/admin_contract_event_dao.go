@@ -0,0 +1,61 @@
+package dao
+
+import (
+	"context"
+
+	"go-apiadmin/internal/domain/model"
+
+	"gorm.io/gorm"
+)
+
+type AdminContractEventDAO struct{ DB *gorm.DB }
+
+func NewAdminContractEventDAO(db *gorm.DB) *AdminContractEventDAO {
+	return &AdminContractEventDAO{DB: db}
+}
+
+func (d *AdminContractEventDAO) Create(ctx context.Context, m *model.AdminContractEvent) error {
+	return d.DB.WithContext(ctx).Create(m).Error
+}
+
+// ContractEventQuery 事件查询条件；Hashes 为 nil 时不限接口，SinceID 用于增量拉取
+type ContractEventQuery struct {
+	Hashes       []string
+	BreakingOnly bool
+	SinceID      int64
+	Page, Limit  int
+}
+
+// List 按 id 倒序分页（SinceID>0 时按 id 升序返回其后的事件）
+func (d *AdminContractEventDAO) List(ctx context.Context, q ContractEventQuery) ([]model.AdminContractEvent, int64, error) {
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.Limit <= 0 || q.Limit > 200 {
+		q.Limit = 20
+	}
+	var list []model.AdminContractEvent
+	if q.Hashes != nil && len(q.Hashes) == 0 {
+		return list, 0, nil
+	}
+	tx := d.DB.WithContext(ctx).Model(&model.AdminContractEvent{})
+	if q.Hashes != nil {
+		tx = tx.Where("hash IN ?", q.Hashes)
+	}
+	if q.BreakingOnly {
+		tx = tx.Where("breaking=1")
+	}
+	order := "id DESC"
+	if q.SinceID > 0 {
+		tx = tx.Where("id>?", q.SinceID)
+		order = "id ASC"
+	}
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := tx.Order(order).Limit(q.Limit).Offset((q.Page - 1) * q.Limit).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
@@ -12,6 +12,14 @@ type AdminFieldsDAO struct{ DB *gorm.DB }
 
 func NewAdminFieldsDAO(db *gorm.DB) *AdminFieldsDAO { return &AdminFieldsDAO{DB: db} }
 
+// WithTx 绑定到给定事务（tx 为 nil 时返回自身）
+func (d *AdminFieldsDAO) WithTx(tx *gorm.DB) *AdminFieldsDAO {
+	if tx == nil {
+		return d
+	}
+	return &AdminFieldsDAO{DB: tx}
+}
+
 func (d *AdminFieldsDAO) List(ctx context.Context, hash string, typ int8, page, limit int) ([]model.AdminField, int64, error) {
 	if page <= 0 {
 		page = 1
@@ -40,6 +48,14 @@ func (d *AdminFieldsDAO) Update(ctx context.Context, m *model.AdminField) error
 func (d *AdminFieldsDAO) Delete(ctx context.Context, id int64) error {
 	return d.DB.WithContext(ctx).Delete(&model.AdminField{}, id).Error
 }
+
+// DeleteByIDs 批量删除字段（删除父字段时连带子字段）
+func (d *AdminFieldsDAO) DeleteByIDs(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return d.DB.WithContext(ctx).Where("id IN ?", ids).Delete(&model.AdminField{}).Error
+}
 func (d *AdminFieldsDAO) FindByID(ctx context.Context, id int64) (*model.AdminField, error) {
 	var m model.AdminField
 	if err := d.DB.WithContext(ctx).First(&m, id).Error; err != nil {
@@ -61,3 +77,20 @@ func (d *AdminFieldsDAO) ListByHashAndType(ctx context.Context, hash string, typ
 	}
 	return list, nil
 }
+
+// ListByHashes 批量获取多个接口的字段（请求+响应），按 id 升序
+func (d *AdminFieldsDAO) ListByHashes(ctx context.Context, hashes []string) ([]model.AdminField, error) {
+	var list []model.AdminField
+	if len(hashes) == 0 {
+		return list, nil
+	}
+	if err := d.DB.WithContext(ctx).Where("hash IN ?", hashes).Order("id ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// DeleteByHashAndType 删除接口某一类字段（0 请求 / 1 响应）
+func (d *AdminFieldsDAO) DeleteByHashAndType(ctx context.Context, hash string, typ int8) error {
+	return d.DB.WithContext(ctx).Where("hash=? AND type=?", hash, typ).Delete(&model.AdminField{}).Error
+}
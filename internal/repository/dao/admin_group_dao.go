@@ -38,3 +38,21 @@ func (d *AdminGroupDAO) FindByHash(ctx context.Context, hash string) (*model.Adm
 func (d *AdminGroupDAO) IncrHot(ctx context.Context, hash string) error {
 	return d.DB.WithContext(ctx).Model(&model.AdminGroup{}).Where("hash=?", hash).UpdateColumn("hot", gorm.Expr("hot+1")).Error
 }
+
+// IncrHotBy 按批量落库的浏览量累加分组热度
+func (d *AdminGroupDAO) IncrHotBy(ctx context.Context, hash string, n int64) error {
+	return d.DB.WithContext(ctx).Model(&model.AdminGroup{}).Where("hash=?", hash).UpdateColumn("hot", gorm.Expr("hot+?", n)).Error
+}
+func (d *AdminGroupDAO) FindByName(ctx context.Context, name string) (*model.AdminGroup, error) {
+	var m model.AdminGroup
+	if err := d.DB.WithContext(ctx).Where("name=?", name).Order("id ASC").First(&m).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+func (d *AdminGroupDAO) Create(ctx context.Context, m *model.AdminGroup) error {
+	return d.DB.WithContext(ctx).Create(m).Error
+}
@@ -16,6 +16,14 @@ func NewAdminInterfaceListDAO(db *gorm.DB) *AdminInterfaceListDAO {
 	return &AdminInterfaceListDAO{DB: db}
 }
 
+// WithTx 绑定到给定事务（tx 为 nil 时返回自身）
+func (d *AdminInterfaceListDAO) WithTx(tx *gorm.DB) *AdminInterfaceListDAO {
+	if tx == nil {
+		return d
+	}
+	return &AdminInterfaceListDAO{DB: tx}
+}
+
 func (d *AdminInterfaceListDAO) FindByID(ctx context.Context, id int64) (*model.AdminInterfaceList, error) {
 	var m model.AdminInterfaceList
 	if err := d.DB.WithContext(ctx).First(&m, id).Error; err != nil {
@@ -36,6 +44,18 @@ func (d *AdminInterfaceListDAO) FindByHash(ctx context.Context, hash string) (*m
 	}
 	return &m, nil
 }
+
+// FindByAPIClass 按 api_class 查找（忽略大小写），用于 hash_type=1 的接口路由
+func (d *AdminInterfaceListDAO) FindByAPIClass(ctx context.Context, apiClass string) (*model.AdminInterfaceList, error) {
+	var m model.AdminInterfaceList
+	if err := d.DB.WithContext(ctx).Where("LOWER(api_class)=?", strings.ToLower(apiClass)).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
 func (d *AdminInterfaceListDAO) ExistsAPIClass(ctx context.Context, apiClass string, excludeID int64) (bool, error) {
 	q := d.DB.WithContext(ctx).Model(&model.AdminInterfaceList{}).Where("LOWER(api_class)=?", strings.ToLower(apiClass))
 	if excludeID > 0 {
@@ -53,6 +73,11 @@ func (d *AdminInterfaceListDAO) Create(ctx context.Context, m *model.AdminInterf
 func (d *AdminInterfaceListDAO) Update(ctx context.Context, m *model.AdminInterfaceList) error {
 	return d.DB.WithContext(ctx).Model(&model.AdminInterfaceList{}).Where("id=?", m.ID).Updates(m).Error
 }
+
+// UpdateColumns 按列更新（允许置零/置空，Updates(struct) 会跳过零值）
+func (d *AdminInterfaceListDAO) UpdateColumns(ctx context.Context, id int64, cols map[string]interface{}) error {
+	return d.DB.WithContext(ctx).Model(&model.AdminInterfaceList{}).Where("id=?", id).Updates(cols).Error
+}
 func (d *AdminInterfaceListDAO) Delete(ctx context.Context, id int64) error {
 	return d.DB.WithContext(ctx).Delete(&model.AdminInterfaceList{}, id).Error
 }
@@ -89,6 +114,15 @@ func (d *AdminInterfaceListDAO) List(ctx context.Context, keywords, groupHash st
 	return list, total, nil
 }
 
+// ListByLifecycle 按生命周期查询接口
+func (d *AdminInterfaceListDAO) ListByLifecycle(ctx context.Context, lifecycles ...string) ([]model.AdminInterfaceList, error) {
+	var list []model.AdminInterfaceList
+	if err := d.DB.WithContext(ctx).Where("lifecycle IN ?", lifecycles).Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
 func (d *AdminInterfaceListDAO) ListAllActive(ctx context.Context) ([]model.AdminInterfaceList, error) {
 	var list []model.AdminInterfaceList
 	if err := d.DB.WithContext(ctx).Where("status=1").Find(&list).Error; err != nil {
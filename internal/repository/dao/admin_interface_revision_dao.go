@@ -0,0 +1,88 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"go-apiadmin/internal/domain/model"
+
+	"gorm.io/gorm"
+)
+
+type AdminInterfaceRevisionDAO struct{ DB *gorm.DB }
+
+func NewAdminInterfaceRevisionDAO(db *gorm.DB) *AdminInterfaceRevisionDAO {
+	return &AdminInterfaceRevisionDAO{DB: db}
+}
+
+// revisionListColumns 列表不加载快照正文
+var revisionListColumns = []string{"id", "hash", "version", "action", "summary", "user_id", "add_time"}
+
+// Latest 接口最新修订（含快照）
+func (d *AdminInterfaceRevisionDAO) Latest(ctx context.Context, hash string) (*model.AdminInterfaceRevision, error) {
+	var m model.AdminInterfaceRevision
+	if err := d.DB.WithContext(ctx).Where("hash=?", hash).Order("version DESC").First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// FindVersion 指定版本（含快照）
+func (d *AdminInterfaceRevisionDAO) FindVersion(ctx context.Context, hash string, version int) (*model.AdminInterfaceRevision, error) {
+	var m model.AdminInterfaceRevision
+	if err := d.DB.WithContext(ctx).Where("hash=? AND version=?", hash, version).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List 接口修订列表，按版本倒序分页
+func (d *AdminInterfaceRevisionDAO) List(ctx context.Context, hash string, page, limit int) ([]model.AdminInterfaceRevision, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 20
+	}
+	q := d.DB.WithContext(ctx).Model(&model.AdminInterfaceRevision{}).Where("hash=?", hash)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []model.AdminInterfaceRevision
+	if err := q.Select(revisionListColumns).Order("version DESC").Limit(limit).Offset((page - 1) * limit).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// Recent 最近变更（不含 baseline），hashes 为 nil 时不限接口
+func (d *AdminInterfaceRevisionDAO) Recent(ctx context.Context, hashes []string, limit int) ([]model.AdminInterfaceRevision, error) {
+	var list []model.AdminInterfaceRevision
+	if hashes != nil && len(hashes) == 0 {
+		return list, nil
+	}
+	q := d.DB.WithContext(ctx).Select(revisionListColumns).Where("action<>?", "baseline")
+	if hashes != nil {
+		q = q.Where("hash IN ?", hashes)
+	}
+	if err := q.Order("id DESC").Limit(limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (d *AdminInterfaceRevisionDAO) Create(ctx context.Context, m *model.AdminInterfaceRevision) error {
+	return d.DB.WithContext(ctx).Create(m).Error
+}
+
+// Prune 删除接口 version 之前的修订
+func (d *AdminInterfaceRevisionDAO) Prune(ctx context.Context, hash string, before int) error {
+	return d.DB.WithContext(ctx).Where("hash=? AND version<?", hash, before).Delete(&model.AdminInterfaceRevision{}).Error
+}
@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"go-apiadmin/internal/domain/model"
+
+	"gorm.io/gorm"
+)
+
+type AdminRateLimitDAO struct{ DB *gorm.DB }
+
+func NewAdminRateLimitDAO(db *gorm.DB) *AdminRateLimitDAO { return &AdminRateLimitDAO{DB: db} }
+
+func (d *AdminRateLimitDAO) FindByID(ctx context.Context, id int64) (*model.AdminRateLimit, error) {
+	var m model.AdminRateLimit
+	if err := d.DB.WithContext(ctx).First(&m, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// FindByScope 按 app_id + hash 精确查找（用于唯一性校验）
+func (d *AdminRateLimitDAO) FindByScope(ctx context.Context, appID, hash string) (*model.AdminRateLimit, error) {
+	var m model.AdminRateLimit
+	if err := d.DB.WithContext(ctx).Where("app_id=? AND hash=?", appID, hash).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List 支持按 app_id / hash 过滤，分页
+func (d *AdminRateLimitDAO) List(ctx context.Context, appID, hash string, page, limit int) ([]model.AdminRateLimit, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 20
+	}
+	q := d.DB.WithContext(ctx).Model(&model.AdminRateLimit{})
+	if appID != "" {
+		q = q.Where("app_id=?", appID)
+	}
+	if hash != "" {
+		q = q.Where("hash=?", hash)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []model.AdminRateLimit
+	if err := q.Order("id DESC").Limit(limit).Offset((page - 1) * limit).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// ListActive 全部启用规则（规则量小，供网关整体缓存）
+func (d *AdminRateLimitDAO) ListActive(ctx context.Context) ([]model.AdminRateLimit, error) {
+	var list []model.AdminRateLimit
+	if err := d.DB.WithContext(ctx).Where("status=1").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (d *AdminRateLimitDAO) Create(ctx context.Context, m *model.AdminRateLimit) error {
+	return d.DB.WithContext(ctx).Create(m).Error
+}
+
+// Update 显式写入全部可编辑列（限额允许置 0）
+func (d *AdminRateLimitDAO) Update(ctx context.Context, m *model.AdminRateLimit) error {
+	return d.DB.WithContext(ctx).Model(&model.AdminRateLimit{}).Where("id=?", m.ID).Updates(map[string]interface{}{
+		"rate": m.Rate, "burst": m.Burst, "daily_quota": m.DailyQuota, "monthly_quota": m.MonthlyQuota, "status": m.Status,
+	}).Error
+}
+func (d *AdminRateLimitDAO) Delete(ctx context.Context, id int64) error {
+	return d.DB.WithContext(ctx).Delete(&model.AdminRateLimit{}, id).Error
+}
@@ -0,0 +1,43 @@
+package dao
+
+import (
+	"context"
+
+	"go-apiadmin/internal/domain/model"
+
+	"gorm.io/gorm"
+)
+
+type AdminTryItLogDAO struct{ DB *gorm.DB }
+
+func NewAdminTryItLogDAO(db *gorm.DB) *AdminTryItLogDAO { return &AdminTryItLogDAO{DB: db} }
+
+func (d *AdminTryItLogDAO) Create(ctx context.Context, m *model.AdminTryItLog) error {
+	return d.DB.WithContext(ctx).Create(m).Error
+}
+
+// List 按 id 倒序分页，hash / appID 为空时不限
+func (d *AdminTryItLogDAO) List(ctx context.Context, hash, appID string, page, limit int) ([]model.AdminTryItLog, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 20
+	}
+	tx := d.DB.WithContext(ctx).Model(&model.AdminTryItLog{})
+	if hash != "" {
+		tx = tx.Where("hash = ?", hash)
+	}
+	if appID != "" {
+		tx = tx.Where("app_id = ?", appID)
+	}
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []model.AdminTryItLog
+	if err := tx.Order("id DESC").Limit(limit).Offset((page - 1) * limit).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"context"
+
+	"go-apiadmin/internal/domain/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AdminWikiViewDAO struct{ DB *gorm.DB }
+
+func NewAdminWikiViewDAO(db *gorm.DB) *AdminWikiViewDAO { return &AdminWikiViewDAO{DB: db} }
+
+// ViewCount 浏览量排行项，Key 为接口 hash 或分组 hash
+type ViewCount struct {
+	Key   string `json:"key"`
+	Views int64  `json:"views"`
+}
+
+var wikiViewConflict = []clause.Column{{Name: "hour"}, {Name: "hash"}, {Name: "app_id"}}
+
+// SaveHourly 写入整小时计数（覆盖已有值，重复落库幂等）
+func (d *AdminWikiViewDAO) SaveHourly(ctx context.Context, rows []model.AdminWikiView) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   wikiViewConflict,
+		DoUpdates: clause.AssignmentColumns([]string{"views", "group_hash", "update_time"}),
+	}).CreateInBatches(rows, 200).Error
+}
+
+// Incr 累加计数（未启用 Redis 时逐次写库）
+func (d *AdminWikiViewDAO) Incr(ctx context.Context, m *model.AdminWikiView) error {
+	return d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: wikiViewConflict,
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"views":       gorm.Expr("admin_wiki_view.views + EXCLUDED.views"),
+			"group_hash":  gorm.Expr("EXCLUDED.group_hash"),
+			"update_time": gorm.Expr("EXCLUDED.update_time"),
+		}),
+	}).Create(m).Error
+}
+
+// Top 自 since（小时起点）起按 column（hash / group_hash）汇总浏览量倒序；appID 非空时仅该应用的浏览
+func (d *AdminWikiViewDAO) Top(ctx context.Context, column string, since int64, appID string) ([]ViewCount, error) {
+	if column != "group_hash" {
+		column = "hash"
+	}
+	tx := d.DB.WithContext(ctx).Model(&model.AdminWikiView{}).Where("hour >= ?", since)
+	if appID != "" {
+		tx = tx.Where("app_id = ?", appID)
+	}
+	var out []ViewCount
+	err := tx.Select(column + " AS key, SUM(views) AS views").Group(column).Order("views DESC").Scan(&out).Error
+	return out, err
+}
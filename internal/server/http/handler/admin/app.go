@@ -1,6 +1,8 @@
 package admin
 
 import (
+	"errors"
+
 	"go-apiadmin/internal/service"
 	"go-apiadmin/internal/util/retcode"
 	"go-apiadmin/pkg/response"
@@ -24,32 +26,34 @@ func (h *AppHandler) Index(c *gin.Context) {
 }
 func (h *AppHandler) Add(c *gin.Context) {
 	var req struct {
-		AppName, AppInfo, AppGroup string
-		Status                     int8
+		AppName, AppInfo, AppGroup, AppAPI, AppAPIShow, AppAPIHide string
+		Status, SignEnable, ShowMode                               int8
+		SignWindow                                                 int
 	}
 	if err := c.ShouldBind(&req); err != nil {
 		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
 		return
 	}
-	id, err := h.d.App.Add(c.Request.Context(), service.AddAppParams{AppName: req.AppName, AppInfo: req.AppInfo, AppGroup: req.AppGroup, Status: req.Status})
+	id, err := h.d.App.Add(c.Request.Context(), service.AddAppParams{AppName: req.AppName, AppInfo: req.AppInfo, AppGroup: req.AppGroup, AppAPI: req.AppAPI, AppAPIShow: req.AppAPIShow, ShowMode: req.ShowMode, AppAPIHide: req.AppAPIHide, SignEnable: req.SignEnable, SignWindow: req.SignWindow, Status: req.Status})
 	if err != nil {
-		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+		response.Error(c, visibilityErrCode(err), err.Error())
 		return
 	}
 	response.Success(c, gin.H{"id": id})
 }
 func (h *AppHandler) Edit(c *gin.Context) {
 	var req struct {
-		ID                         int64
-		AppName, AppInfo, AppGroup *string
-		Status                     *int8
+		ID                                                         int64
+		AppName, AppInfo, AppGroup, AppAPI, AppAPIShow, AppAPIHide *string
+		Status, SignEnable, ShowMode                               *int8
+		SignWindow                                                 *int
 	}
 	if err := c.ShouldBind(&req); err != nil {
 		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
 		return
 	}
-	if err := h.d.App.Edit(c.Request.Context(), service.EditAppParams{ID: req.ID, AppName: req.AppName, AppInfo: req.AppInfo, AppGroup: req.AppGroup, Status: req.Status}); err != nil {
-		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+	if err := h.d.App.Edit(c.Request.Context(), service.EditAppParams{ID: req.ID, AppName: req.AppName, AppInfo: req.AppInfo, AppGroup: req.AppGroup, AppAPI: req.AppAPI, AppAPIShow: req.AppAPIShow, ShowMode: req.ShowMode, AppAPIHide: req.AppAPIHide, SignEnable: req.SignEnable, SignWindow: req.SignWindow, Status: req.Status}); err != nil {
+		response.Error(c, visibilityErrCode(err), err.Error())
 		return
 	}
 	response.Success(c, gin.H{"ok": true})
@@ -89,3 +93,35 @@ func (h *AppHandler) RefreshSecret(c *gin.Context) {
 	}
 	response.Success(c, gin.H{"secret": sec})
 }
+
+// Stats 应用网关调用统计（最近 minutes 分钟，默认 60，最大 1440）
+func (h *AppHandler) Stats(c *gin.Context) {
+	res, err := h.d.GwStats.Query(c.Request.Context(), service.StatScopeApp, c.Query("app_id"), qInt(c, "minutes", 60))
+	if err != nil {
+		response.Error(c, retcode.CACHE_READ_ERROR, err.Error())
+		return
+	}
+	response.Success(c, res)
+}
+
+// Visibility 预览应用在 wiki 中可见的接口（按 id 或 app_id），含来源（应用分组模板 / 应用追加）与被排除项
+func (h *AppHandler) Visibility(c *gin.Context) {
+	res, err := h.d.Wiki.PreviewApp(c.Request.Context(), qInt64(c, "id"), c.Query("app_id"))
+	if err != nil {
+		code := retcode.DB_READ_ERROR
+		if errors.Is(err, service.ErrVisibilityApp) {
+			code = retcode.NOT_EXISTS
+		}
+		response.Error(c, code, err.Error())
+		return
+	}
+	response.Success(c, res)
+}
+
+// visibilityErrCode 可见范围配置错误为参数错误，其余为保存失败
+func visibilityErrCode(err error) int {
+	if errors.Is(err, service.ErrAPIShow) || errors.Is(err, service.ErrShowMode) {
+		return retcode.PARAM_INVALID
+	}
+	return retcode.DB_SAVE_ERROR
+}
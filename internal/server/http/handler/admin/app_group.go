@@ -1,6 +1,8 @@
 package admin
 
 import (
+	"errors"
+
 	"go-apiadmin/internal/service"
 	"go-apiadmin/internal/util/retcode"
 	"go-apiadmin/pkg/response"
@@ -22,31 +24,31 @@ func (h *AppGroupHandler) Index(c *gin.Context) {
 }
 func (h *AppGroupHandler) Add(c *gin.Context) {
 	var req struct {
-		Name, Description, Hash string
-		Status                  int8
+		Name, Description, Hash, APIShow string
+		Status                           int8
 	}
 	if err := c.ShouldBind(&req); err != nil {
 		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
 		return
 	}
-	if err := h.d.AppGroup.Add(c.Request.Context(), service.AddAppGroupParams{Name: req.Name, Description: req.Description, Hash: req.Hash, Status: req.Status}); err != nil {
-		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+	if err := h.d.AppGroup.Add(c.Request.Context(), service.AddAppGroupParams{Name: req.Name, Description: req.Description, Hash: req.Hash, APIShow: req.APIShow, Status: req.Status}); err != nil {
+		response.Error(c, visibilityErrCode(err), err.Error())
 		return
 	}
 	response.Success(c, gin.H{"ok": true})
 }
 func (h *AppGroupHandler) Edit(c *gin.Context) {
 	var req struct {
-		ID                      int64
-		Name, Description, Hash *string
-		Status                  *int8
+		ID                               int64
+		Name, Description, Hash, APIShow *string
+		Status                           *int8
 	}
 	if err := c.ShouldBind(&req); err != nil {
 		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
 		return
 	}
-	if err := h.d.AppGroup.Edit(c.Request.Context(), service.EditAppGroupParams{ID: req.ID, Name: req.Name, Description: req.Description, Hash: req.Hash, Status: req.Status}); err != nil {
-		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+	if err := h.d.AppGroup.Edit(c.Request.Context(), service.EditAppGroupParams{ID: req.ID, Name: req.Name, Description: req.Description, Hash: req.Hash, APIShow: req.APIShow, Status: req.Status}); err != nil {
+		response.Error(c, visibilityErrCode(err), err.Error())
 		return
 	}
 	response.Success(c, gin.H{"ok": true})
@@ -68,3 +70,17 @@ func (h *AppGroupHandler) Delete(c *gin.Context) {
 	}
 	response.Success(c, gin.H{"ok": true})
 }
+
+// Visibility 预览应用分组可见范围模板展开后的接口
+func (h *AppGroupHandler) Visibility(c *gin.Context) {
+	res, err := h.d.Wiki.PreviewProfile(c.Request.Context(), qInt64(c, "id"))
+	if err != nil {
+		code := retcode.DB_READ_ERROR
+		if errors.Is(err, service.ErrVisibilityGroup) {
+			code = retcode.NOT_EXISTS
+		}
+		response.Error(c, code, err.Error())
+		return
+	}
+	response.Success(c, res)
+}
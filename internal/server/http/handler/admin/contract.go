@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"errors"
+
+	"go-apiadmin/internal/repository/dao"
+	"go-apiadmin/internal/service"
+	"go-apiadmin/internal/util/retcode"
+	"go-apiadmin/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContractEvents 接口契约变更事件（hash 可选；breaking=1 仅破坏性；since_id 增量拉取，按 id 升序）
+func (h *InterfaceListHandler) ContractEvents(c *gin.Context) {
+	if h.d.Contract == nil {
+		response.Error(c, retcode.INVALID, "contract check disabled")
+		return
+	}
+	page, limit := pageLimit(c)
+	q := dao.ContractEventQuery{BreakingOnly: qInt(c, "breaking", 0) == 1, SinceID: qInt64(c, "since_id"), Page: page, Limit: limit}
+	if hash := c.Query("hash"); hash != "" {
+		q.Hashes = []string{hash}
+	}
+	res, err := h.d.Contract.Events(c.Request.Context(), q)
+	if err != nil {
+		response.Error(c, retcode.DB_READ_ERROR, err.Error())
+		return
+	}
+	response.Success(c, res)
+}
+
+// contractError 未确认的破坏性变更返回 BREAKING_CHANGE 及变更明细，其余按 def 返回
+func contractError(c *gin.Context, err error, def int) {
+	var be *service.BreakingChangeError
+	if errors.As(err, &be) {
+		response.JSON(c, retcode.BREAKING_CHANGE, err.Error(), gin.H{"hash": be.Hash, "changes": be.Changes})
+		return
+	}
+	response.Error(c, def, err.Error())
+}
@@ -24,6 +24,14 @@ type Dependencies struct {
 	IfList    *service.InterfaceListService
 	Fields    *service.FieldsService
 	Log       *service.LogService
+	RateLimit *service.RateLimitService
+	RespCache *service.GatewayCache        // 网关响应缓存（清除用）
+	GwStats   *service.GatewayStatsService // 网关调用统计
+	Wiki      *service.WikiService         // 文档导出
+	Import    *service.ImportService       // 文档导入
+	Revision  *service.RevisionService     // 接口修订
+	Contract  *service.ContractService     // 接口契约变更事件
+	TryIt     *service.TryItService        // wiki 在线调试（审计查询），未开启时为 nil
 	JWT       *jwt.Manager
 	Config    *config.Config
 	Cache     cache.Cache
@@ -1,6 +1,10 @@
 package admin
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
+
 	"go-apiadmin/internal/service"
 	"go-apiadmin/internal/util/retcode"
 	"go-apiadmin/pkg/response"
@@ -21,20 +25,57 @@ func (h *FieldsHandler) Index(c *gin.Context) {
 	}
 	response.Success(c, res)
 }
+
+// Tree 按 JSON 路径还原的字段树（type 0 请求 / 1 响应）
+func (h *FieldsHandler) Tree(c *gin.Context) {
+	hash := c.Query("hash")
+	if strings.TrimSpace(hash) == "" {
+		response.Error(c, retcode.EMPTY_PARAMS, "hash required")
+		return
+	}
+	tree, err := h.d.Fields.Tree(c.Request.Context(), hash, int8(qInt(c, "type", 0)))
+	if err != nil {
+		response.Error(c, retcode.DB_READ_ERROR, err.Error())
+		return
+	}
+	response.Success(c, gin.H{"list": tree, "dataType": service.DataTypeMap()})
+}
+
+// Schema 接口请求 / 响应 JSON Schema（draft 2020-12）与示例报文
+func (h *FieldsHandler) Schema(c *gin.Context) {
+	hash := c.Query("hash")
+	if strings.TrimSpace(hash) == "" {
+		response.Error(c, retcode.EMPTY_PARAMS, "hash required")
+		return
+	}
+	res, err := h.d.Fields.Schema(c.Request.Context(), hash)
+	if errors.Is(err, service.ErrSchemaNotFound) {
+		response.Error(c, retcode.NOT_EXISTS, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, retcode.DB_READ_ERROR, err.Error())
+		return
+	}
+	response.Success(c, res)
+}
 func (h *FieldsHandler) Request(c *gin.Context)  { c.Set("_fields_type", int8(0)); h.Index(c) }
 func (h *FieldsHandler) Response(c *gin.Context) { c.Set("_fields_type", int8(1)); h.Index(c) }
 func (h *FieldsHandler) Add(c *gin.Context) {
 	var req struct {
 		FieldName, Hash, Default, Range, Info, ShowName string
+		Path                                            string `form:"path" json:"path"`
+		ParentID                                        int64  `form:"parent_id" json:"parent_id"`
 		DataType, IsMust, Type                          int8
+		ConfirmBreaking                                 bool `form:"confirm_breaking" json:"confirm_breaking"`
 	}
 	if err := c.ShouldBind(&req); err != nil {
 		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
 		return
 	}
-	id, err := h.d.Fields.Add(c.Request.Context(), service.AddFieldParams{FieldName: req.FieldName, Hash: req.Hash, Default: req.Default, Range: req.Range, Info: req.Info, Type: req.Type, ShowName: req.ShowName, DataType: req.DataType, IsMust: req.IsMust})
+	id, err := h.d.Fields.Add(c.Request.Context(), service.AddFieldParams{FieldName: req.FieldName, Hash: req.Hash, Default: req.Default, Range: req.Range, Info: req.Info, Type: req.Type, ShowName: req.ShowName, Path: req.Path, ParentID: req.ParentID, DataType: req.DataType, IsMust: req.IsMust, ConfirmBreaking: req.ConfirmBreaking})
 	if err != nil {
-		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+		contractError(c, err, retcode.DB_SAVE_ERROR)
 		return
 	}
 	response.Success(c, gin.H{"id": id})
@@ -43,22 +84,25 @@ func (h *FieldsHandler) Edit(c *gin.Context) {
 	var req struct {
 		ID                                              int64
 		FieldName, Hash, Default, Range, Info, ShowName *string
+		Path                                            *string `form:"path" json:"path"`
+		ParentID                                        *int64  `form:"parent_id" json:"parent_id"`
 		DataType, IsMust, Type                          *int8
+		ConfirmBreaking                                 bool `form:"confirm_breaking" json:"confirm_breaking"`
 	}
 	if err := c.ShouldBind(&req); err != nil {
 		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
 		return
 	}
-	if err := h.d.Fields.Edit(c.Request.Context(), service.EditFieldParams{ID: req.ID, FieldName: req.FieldName, Hash: req.Hash, Default: req.Default, Range: req.Range, Info: req.Info, ShowName: req.ShowName, DataType: req.DataType, IsMust: req.IsMust, Type: req.Type}); err != nil {
-		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+	if err := h.d.Fields.Edit(c.Request.Context(), service.EditFieldParams{ID: req.ID, FieldName: req.FieldName, Hash: req.Hash, Default: req.Default, Range: req.Range, Info: req.Info, ShowName: req.ShowName, Path: req.Path, ParentID: req.ParentID, DataType: req.DataType, IsMust: req.IsMust, Type: req.Type, ConfirmBreaking: req.ConfirmBreaking}); err != nil {
+		contractError(c, err, retcode.DB_SAVE_ERROR)
 		return
 	}
 	response.Success(c, gin.H{"ok": true})
 }
 func (h *FieldsHandler) Delete(c *gin.Context) {
 	id := qInt64(c, "id")
-	if err := h.d.Fields.Delete(c.Request.Context(), id); err != nil {
-		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+	if err := h.d.Fields.Delete(c.Request.Context(), id, qInt(c, "confirm_breaking", 0) == 1); err != nil {
+		contractError(c, err, retcode.DB_SAVE_ERROR)
 		return
 	}
 	response.Success(c, gin.H{"ok": true})
@@ -68,14 +112,43 @@ func (h *FieldsHandler) Upload(c *gin.Context) {
 		Hash string `form:"hash" json:"hash"`
 		Type int8   `form:"type" json:"type"`
 		JSON string `form:"json" json:"json"`
+		// 确认提交破坏性变更
+		ConfirmBreaking bool `form:"confirm_breaking" json:"confirm_breaking"`
 	}
 	if err := c.ShouldBind(&req); err != nil {
 		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
 		return
 	}
-	if err := h.d.Fields.BatchUpload(c.Request.Context(), service.BatchUploadParams{Hash: req.Hash, Type: req.Type, JSON: req.JSON}); err != nil {
-		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+	if err := h.d.Fields.BatchUpload(c.Request.Context(), service.BatchUploadParams{Hash: req.Hash, Type: req.Type, JSON: req.JSON, ConfirmBreaking: req.ConfirmBreaking}); err != nil {
+		contractError(c, err, retcode.DB_SAVE_ERROR)
 		return
 	}
 	response.Success(c, gin.H{"ok": true})
 }
+
+// Validate 使用接口请求字段定义校验样例参数（payload 为 JSON 对象字符串），返回逐字段错误及补齐默认值后的参数
+func (h *FieldsHandler) Validate(c *gin.Context) {
+	var req struct {
+		Hash    string `form:"hash" json:"hash"`
+		Payload string `form:"payload" json:"payload"`
+	}
+	if err := c.ShouldBind(&req); err != nil || strings.TrimSpace(req.Hash) == "" {
+		response.Error(c, retcode.EMPTY_PARAMS, "hash required")
+		return
+	}
+	payload := map[string]interface{}{}
+	if strings.TrimSpace(req.Payload) != "" {
+		dec := json.NewDecoder(strings.NewReader(req.Payload))
+		dec.UseNumber()
+		if err := dec.Decode(&payload); err != nil {
+			response.Error(c, retcode.JSON_PARSE_FAIL, "payload must be json object")
+			return
+		}
+	}
+	res, err := h.d.Fields.Validate(c.Request.Context(), req.Hash, payload)
+	if err != nil {
+		response.Error(c, retcode.DB_READ_ERROR, err.Error())
+		return
+	}
+	response.Success(c, res)
+}
@@ -1,10 +1,16 @@
 package admin
 
 import (
+	"bytes"
+	"errors"
 	"go-apiadmin/internal/service"
+	"go-apiadmin/internal/util/httputil"
 	"go-apiadmin/internal/util/retcode"
 	"go-apiadmin/pkg/response"
+	"io"
+	"net/http"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -30,12 +36,14 @@ func (h *InterfaceListHandler) Add(c *gin.Context) {
 	var req struct {
 		APIClass, Info, ReturnStr, GroupHash string
 		AccessToken, Status, Method, IsTest  int8
+		TimeoutMS, CacheTTL                  int
+		CacheKeys, CacheVary, Lifecycle      string
 	}
 	if err := c.ShouldBind(&req); err != nil {
 		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
 		return
 	}
-	id, err := h.d.IfList.Add(c.Request.Context(), service.AddInterfaceParams{APIClass: req.APIClass, AccessToken: req.AccessToken, Status: req.Status, Method: req.Method, Info: req.Info, IsTest: req.IsTest, ReturnStr: req.ReturnStr, GroupHash: req.GroupHash})
+	id, err := h.d.IfList.Add(c.Request.Context(), service.AddInterfaceParams{APIClass: req.APIClass, AccessToken: req.AccessToken, Status: req.Status, Method: req.Method, Info: req.Info, IsTest: req.IsTest, ReturnStr: req.ReturnStr, GroupHash: req.GroupHash, TimeoutMS: req.TimeoutMS, CacheTTL: req.CacheTTL, CacheKeys: req.CacheKeys, CacheVary: req.CacheVary, Lifecycle: req.Lifecycle})
 	if err != nil {
 		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
 		return
@@ -47,13 +55,16 @@ func (h *InterfaceListHandler) Edit(c *gin.Context) {
 		ID                                   int64
 		APIClass, Info, ReturnStr, GroupHash *string
 		AccessToken, Status, Method, IsTest  *int8
+		TimeoutMS, CacheTTL                  *int
+		CacheKeys, CacheVary                 *string
+		ConfirmBreaking                      bool `form:"confirm_breaking" json:"confirm_breaking"`
 	}
 	if err := c.ShouldBind(&req); err != nil {
 		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
 		return
 	}
-	if err := h.d.IfList.Edit(c.Request.Context(), service.EditInterfaceParams{ID: req.ID, APIClass: req.APIClass, AccessToken: req.AccessToken, Status: req.Status, Method: req.Method, Info: req.Info, IsTest: req.IsTest, ReturnStr: req.ReturnStr, GroupHash: req.GroupHash}); err != nil {
-		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+	if err := h.d.IfList.Edit(c.Request.Context(), service.EditInterfaceParams{ID: req.ID, APIClass: req.APIClass, AccessToken: req.AccessToken, Status: req.Status, Method: req.Method, Info: req.Info, IsTest: req.IsTest, ReturnStr: req.ReturnStr, GroupHash: req.GroupHash, TimeoutMS: req.TimeoutMS, CacheTTL: req.CacheTTL, CacheKeys: req.CacheKeys, CacheVary: req.CacheVary, ConfirmBreaking: req.ConfirmBreaking}); err != nil {
+		contractError(c, err, retcode.DB_SAVE_ERROR)
 		return
 	}
 	response.Success(c, gin.H{"ok": true})
@@ -67,6 +78,51 @@ func (h *InterfaceListHandler) ChangeStatus(c *gin.Context) {
 	}
 	response.Success(c, gin.H{"ok": true})
 }
+
+// Lifecycle 变更接口生命周期（draft / active / deprecated / retired），可同时设置 sunset_at 与替代接口 replacement；
+// 下线为破坏性变更，需 confirm_breaking=1
+func (h *InterfaceListHandler) Lifecycle(c *gin.Context) {
+	var req struct {
+		ID              int64   `form:"id" json:"id"`
+		Lifecycle       string  `form:"lifecycle" json:"lifecycle"`
+		SunsetAt        *int64  `form:"sunset_at" json:"sunset_at"`
+		Replacement     *string `form:"replacement" json:"replacement"`
+		ConfirmBreaking bool    `form:"confirm_breaking" json:"confirm_breaking"`
+	}
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
+		return
+	}
+	err := h.d.IfList.ChangeLifecycle(c.Request.Context(), service.LifecycleParams{ID: req.ID, Lifecycle: req.Lifecycle, SunsetAt: req.SunsetAt, Replacement: req.Replacement, ConfirmBreaking: req.ConfirmBreaking})
+	if err != nil {
+		contractError(c, err, lifecycleErrCode(err))
+		return
+	}
+	response.Success(c, gin.H{"ok": true})
+}
+
+// DeprecationUsage 废弃 / 下线接口按应用的调用统计（传 hash 查单个接口），用于通知调用方迁移
+func (h *InterfaceListHandler) DeprecationUsage(c *gin.Context) {
+	res, err := h.d.IfList.DeprecationUsage(c.Request.Context(), c.Query("hash"))
+	if err != nil {
+		response.Error(c, lifecycleErrCode(err), err.Error())
+		return
+	}
+	response.Success(c, gin.H{"list": res})
+}
+
+// lifecycleErrCode 生命周期校验错误 -> 业务码
+func lifecycleErrCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrLifecycleNotFound):
+		return retcode.NOT_EXISTS
+	case errors.Is(err, service.ErrLifecycle), errors.Is(err, service.ErrLifecycleTransition),
+		errors.Is(err, service.ErrLifecycleSunset), errors.Is(err, service.ErrLifecycleReplacement):
+		return retcode.PARAM_INVALID
+	}
+	return retcode.DB_SAVE_ERROR
+}
+
 func (h *InterfaceListHandler) Delete(c *gin.Context) {
 	id := qInt64(c, "id")
 	if err := h.d.IfList.Delete(c.Request.Context(), id); err != nil {
@@ -75,6 +131,174 @@ func (h *InterfaceListHandler) Delete(c *gin.Context) {
 	}
 	response.Success(c, gin.H{"ok": true})
 }
+
+// PurgeCache 清除接口网关响应缓存
+func (h *InterfaceListHandler) PurgeCache(c *gin.Context) {
+	if h.d.RespCache == nil {
+		response.Error(c, retcode.INVALID, "response cache disabled")
+		return
+	}
+	ver, err := h.d.RespCache.Purge(c.Request.Context(), c.Query("hash"))
+	if err != nil {
+		response.Error(c, retcode.CACHE_SAVE_ERROR, err.Error())
+		return
+	}
+	response.Success(c, gin.H{"ok": true, "version": ver})
+}
+
+// Stats 接口网关调用统计（最近 minutes 分钟，默认 60，最大 1440）
+func (h *InterfaceListHandler) Stats(c *gin.Context) {
+	res, err := h.d.GwStats.Query(c.Request.Context(), service.StatScopeAPI, c.Query("hash"), qInt(c, "minutes", 60))
+	if err != nil {
+		response.Error(c, retcode.CACHE_READ_ERROR, err.Error())
+		return
+	}
+	response.Success(c, res)
+}
+
+// OpenAPI 导出 OpenAPI 3.1 文档：传 app_id 时按应用 app_api_show 过滤，否则为全部启用接口
+func (h *InterfaceListHandler) OpenAPI(c *gin.Context) {
+	user := service.WikiUserInfo{AppID: "-1"}
+	if appID := strings.TrimSpace(c.Query("app_id")); appID != "" {
+		user.AppID = appID
+	}
+	doc, err := h.d.Wiki.OpenAPI(c.Request.Context(), service.OpenAPIOptions{
+		Title:     h.d.Config.AppMeta.Name,
+		Version:   h.d.Config.AppMeta.Version,
+		ServerURL: httputil.BaseURL(c.Request),
+		User:      user,
+	})
+	if err != nil {
+		response.Error(c, retcode.DB_READ_ERROR, err.Error())
+		return
+	}
+	if c.Query("download") == "1" {
+		c.Header("Content-Disposition", `attachment; filename="openapi.json"`)
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// StaticDocs 导出离线静态文档 zip（format=html|markdown，传 app_id 时按该应用可见范围过滤）
+func (h *InterfaceListHandler) StaticDocs(c *gin.Context) {
+	user := service.WikiUserInfo{AppID: "-1"}
+	if appID := strings.TrimSpace(c.Query("app_id")); appID != "" {
+		user.AppID = appID
+	}
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", service.StaticHTML)))
+	var buf bytes.Buffer
+	err := h.d.Wiki.StaticDocs(c.Request.Context(), service.StaticDocOptions{
+		Format:  format,
+		Title:   h.d.Config.AppMeta.Name,
+		Version: h.d.Config.AppMeta.Version,
+		BaseURL: httputil.BaseURL(c.Request),
+		User:    user,
+	}, &buf)
+	if errors.Is(err, service.ErrStaticFormat) {
+		response.Error(c, retcode.PARAM_INVALID, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(c, retcode.DB_READ_ERROR, err.Error())
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="api-docs-`+format+`.zip"`)
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// SDK 生成 Go 客户端 SDK zip（传 app_id 时按该应用可见范围与签名配置生成，group 为分组 hash，package / module 可选）
+func (h *InterfaceListHandler) SDK(c *gin.Context) {
+	user := service.WikiUserInfo{AppID: "-1"}
+	if appID := strings.TrimSpace(c.Query("app_id")); appID != "" {
+		user.AppID = appID
+	}
+	if lang := strings.ToLower(c.DefaultQuery("lang", "go")); lang != "go" {
+		response.Error(c, retcode.PARAM_INVALID, "lang 目前仅支持 go")
+		return
+	}
+	opt := service.GoSDKOptions{
+		Package: strings.TrimSpace(c.Query("package")),
+		Module:  c.Query("module"),
+		Group:   strings.TrimSpace(c.Query("group")),
+		BaseURL: httputil.BaseURL(c.Request),
+		Title:   h.d.Config.AppMeta.Name,
+		Version: h.d.Config.AppMeta.Version,
+		User:    user,
+	}
+	var buf bytes.Buffer
+	err := h.d.Wiki.GoSDK(c.Request.Context(), opt, &buf)
+	switch {
+	case errors.Is(err, service.ErrSDKPackage), errors.Is(err, service.ErrSDKModule):
+		response.Error(c, retcode.PARAM_INVALID, err.Error())
+		return
+	case errors.Is(err, service.ErrSDKEmpty):
+		response.Error(c, retcode.NOT_EXISTS, err.Error())
+		return
+	case err != nil:
+		response.Error(c, retcode.DB_READ_ERROR, err.Error())
+		return
+	}
+	name := opt.Package
+	if name == "" {
+		name = "apiclient"
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+name+`-go-sdk.zip"`)
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// Import 导入 OpenAPI 3.x / Swagger 2.0 文档（multipart 文件 file 或表单字段 spec）
+func (h *InterfaceListHandler) Import(c *gin.Context) {
+	maxBytes := int64(h.d.Config.Upload.MaxSizeMB) * 1024 * 1024
+	if maxBytes <= 0 {
+		maxBytes = 10 * 1024 * 1024
+	}
+	var raw []byte
+	if f, err := c.FormFile("file"); err == nil {
+		if f.Size > maxBytes {
+			response.Error(c, retcode.FILE_SAVE_ERROR, "文件过大")
+			return
+		}
+		fd, err := f.Open()
+		if err != nil {
+			response.Error(c, retcode.FILE_SAVE_ERROR, err.Error())
+			return
+		}
+		defer fd.Close()
+		if raw, err = io.ReadAll(io.LimitReader(fd, maxBytes)); err != nil {
+			response.Error(c, retcode.FILE_SAVE_ERROR, err.Error())
+			return
+		}
+	} else {
+		raw = []byte(c.PostForm("spec"))
+	}
+	if len(strings.TrimSpace(string(raw))) == 0 {
+		response.Error(c, retcode.EMPTY_PARAMS, "file or spec required")
+		return
+	}
+	if int64(len(raw)) > maxBytes {
+		response.Error(c, retcode.FILE_SAVE_ERROR, "文件过大")
+		return
+	}
+	opt := service.ImportOptions{
+		DryRun:       c.PostForm("dry_run") == "1" || c.PostForm("dry_run") == "true",
+		OnConflict:   c.PostForm("on_conflict"),
+		DefaultGroup: strings.TrimSpace(c.PostForm("group")),
+		Status:       1,
+	}
+	if st := c.PostForm("status"); st == "0" {
+		opt.Status = 0
+	}
+	rep, err := h.d.Import.Import(c.Request.Context(), raw, opt)
+	switch {
+	case errors.Is(err, service.ErrSpecFormat):
+		response.Error(c, retcode.JSON_PARSE_FAIL, err.Error())
+	case errors.Is(err, service.ErrImportOnConflict), errors.Is(err, service.ErrImportGroup):
+		response.Error(c, retcode.PARAM_INVALID, err.Error())
+	case err != nil:
+		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+	default:
+		response.Success(c, rep)
+	}
+}
 func (h *InterfaceListHandler) GetHash(c *gin.Context) {
 	response.Success(c, gin.H{"hash": generateUniqID()})
 }
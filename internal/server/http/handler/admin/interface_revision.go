@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"errors"
+	"strings"
+
+	"go-apiadmin/internal/service"
+	"go-apiadmin/internal/util/retcode"
+	"go-apiadmin/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Revisions 接口修订列表（hash 必填，page / limit 分页）
+func (h *InterfaceListHandler) Revisions(c *gin.Context) {
+	hash := strings.TrimSpace(c.Query("hash"))
+	if hash == "" {
+		response.Error(c, retcode.EMPTY_PARAMS, "hash required")
+		return
+	}
+	page, limit := pageLimit(c)
+	res, err := h.d.Revision.List(c.Request.Context(), hash, page, limit)
+	if err != nil {
+		response.Error(c, retcode.DB_READ_ERROR, err.Error())
+		return
+	}
+	response.Success(c, res)
+}
+
+// Revision 指定版本的完整快照
+func (h *InterfaceListHandler) Revision(c *gin.Context) {
+	hash := strings.TrimSpace(c.Query("hash"))
+	version := qInt(c, "version", 0)
+	if hash == "" || version <= 0 {
+		response.Error(c, retcode.EMPTY_PARAMS, "hash & version required")
+		return
+	}
+	res, err := h.d.Revision.Get(c.Request.Context(), hash, version)
+	if err != nil {
+		revisionError(c, err, retcode.DB_READ_ERROR)
+		return
+	}
+	response.Success(c, res)
+}
+
+// RevisionDiff 比对两个版本（to 默认最新版本，from 默认 to 的上一版本）
+func (h *InterfaceListHandler) RevisionDiff(c *gin.Context) {
+	hash := strings.TrimSpace(c.Query("hash"))
+	if hash == "" {
+		response.Error(c, retcode.EMPTY_PARAMS, "hash required")
+		return
+	}
+	res, err := h.d.Revision.Diff(c.Request.Context(), hash, qInt(c, "from", 0), qInt(c, "to", 0))
+	if err != nil {
+		revisionError(c, err, retcode.DB_READ_ERROR)
+		return
+	}
+	response.Success(c, res)
+}
+
+// Rollback 回滚接口及字段到指定版本
+func (h *InterfaceListHandler) Rollback(c *gin.Context) {
+	var req struct {
+		Hash    string `form:"hash" json:"hash"`
+		Version int    `form:"version" json:"version"`
+	}
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
+		return
+	}
+	if strings.TrimSpace(req.Hash) == "" || req.Version <= 0 {
+		response.Error(c, retcode.EMPTY_PARAMS, "hash & version required")
+		return
+	}
+	rev, err := h.d.Revision.Rollback(c.Request.Context(), strings.TrimSpace(req.Hash), req.Version)
+	if err != nil {
+		revisionError(c, err, retcode.UPDATE_FAILED)
+		return
+	}
+	response.Success(c, rev)
+}
+
+func revisionError(c *gin.Context, err error, def int) {
+	switch {
+	case errors.Is(err, service.ErrRevisionNotFound):
+		response.Error(c, retcode.RECORD_NOT_FOUND, err.Error())
+	case errors.Is(err, service.ErrRevisionDeleted), errors.Is(err, service.ErrRevisionSame):
+		response.Error(c, retcode.PARAM_INVALID, err.Error())
+	default:
+		response.Error(c, def, err.Error())
+	}
+}
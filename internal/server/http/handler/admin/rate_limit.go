@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"go-apiadmin/internal/service"
+	"go-apiadmin/internal/util/retcode"
+	"go-apiadmin/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RateLimitHandler struct{ d Dependencies }
+
+func NewRateLimitHandler(d Dependencies) *RateLimitHandler { return &RateLimitHandler{d: d} }
+
+type rateLimitReq struct {
+	ID           int64  `form:"id" json:"id"`
+	AppID        string `form:"app_id" json:"app_id"`
+	Hash         string `form:"hash" json:"hash"`
+	Rate         int    `form:"rate" json:"rate"`
+	Burst        int    `form:"burst" json:"burst"`
+	DailyQuota   int64  `form:"daily_quota" json:"daily_quota"`
+	MonthlyQuota int64  `form:"monthly_quota" json:"monthly_quota"`
+	Status       *int8  `form:"status" json:"status"`
+}
+
+func (r rateLimitReq) params() service.SaveRateLimitParams {
+	return service.SaveRateLimitParams{ID: r.ID, AppID: r.AppID, Hash: r.Hash, Rate: r.Rate, Burst: r.Burst, DailyQuota: r.DailyQuota, MonthlyQuota: r.MonthlyQuota, Status: r.Status}
+}
+
+func (h *RateLimitHandler) Index(c *gin.Context) {
+	page, limit := pageLimit(c)
+	res, err := h.d.RateLimit.List(c.Request.Context(), c.Query("app_id"), c.Query("hash"), page, limit)
+	if err != nil {
+		response.Error(c, retcode.DB_READ_ERROR, err.Error())
+		return
+	}
+	response.Success(c, res)
+}
+func (h *RateLimitHandler) Add(c *gin.Context) {
+	var req rateLimitReq
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
+		return
+	}
+	id, err := h.d.RateLimit.Add(c.Request.Context(), req.params())
+	if err != nil {
+		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+		return
+	}
+	response.Success(c, gin.H{"id": id})
+}
+func (h *RateLimitHandler) Edit(c *gin.Context) {
+	var req rateLimitReq
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
+		return
+	}
+	if err := h.d.RateLimit.Edit(c.Request.Context(), req.params()); err != nil {
+		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+		return
+	}
+	response.Success(c, gin.H{"ok": true})
+}
+func (h *RateLimitHandler) Delete(c *gin.Context) {
+	if err := h.d.RateLimit.Delete(c.Request.Context(), qInt64(c, "id")); err != nil {
+		response.Error(c, retcode.DB_SAVE_ERROR, err.Error())
+		return
+	}
+	response.Success(c, gin.H{"ok": true})
+}
+
+// Usage 查询作用域当日/当月调用量：app_id / hash / 两者组合
+func (h *RateLimitHandler) Usage(c *gin.Context) {
+	res, err := h.d.RateLimit.Usage(c.Request.Context(), c.Query("app_id"), c.Query("hash"))
+	if err != nil {
+		response.Error(c, retcode.CACHE_READ_ERROR, err.Error())
+		return
+	}
+	response.Success(c, res)
+}
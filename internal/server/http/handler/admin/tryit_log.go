@@ -0,0 +1,23 @@
+package admin
+
+import (
+	"go-apiadmin/internal/util/retcode"
+	"go-apiadmin/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TryItLogs wiki 在线调试审计记录（hash、app_id 可选），按时间倒序
+func (h *InterfaceListHandler) TryItLogs(c *gin.Context) {
+	if h.d.TryIt == nil {
+		response.Error(c, retcode.INVALID, "try it disabled")
+		return
+	}
+	page, limit := pageLimit(c)
+	res, err := h.d.TryIt.Logs(c.Request.Context(), c.Query("hash"), c.Query("app_id"), page, limit)
+	if err != nil {
+		response.Error(c, retcode.DB_READ_ERROR, err.Error())
+		return
+	}
+	response.Success(c, res)
+}
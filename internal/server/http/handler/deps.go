@@ -3,10 +3,11 @@ package handler
 import (
 	adminh "go-apiadmin/internal/server/http/handler/admin"
 	debugh "go-apiadmin/internal/server/http/handler/debug"
+	gatewayh "go-apiadmin/internal/server/http/handler/gateway"
 	wikih "go-apiadmin/internal/server/http/handler/wiki"
 )
 
-// HandlerSet 聚合 admin 与 wiki 子包的 handler，供 router 使用
+// HandlerSet 聚合 admin / wiki / gateway 子包的 handler，供 router 使用
 // 只暴露业务 handler，不再直接暴露依赖。
 type HandlerSet struct {
 	Auth           *adminh.AuthHandler
@@ -22,12 +23,14 @@ type HandlerSet struct {
 	Log            *adminh.LogHandler
 	Cache          *adminh.CacheHandler
 	Index          *adminh.IndexHandler
+	RateLimit      *adminh.RateLimitHandler
 	Wiki           *wikih.WikiHandler
 	Debug          *debugh.Handler
+	Gateway        *gatewayh.GatewayHandler
 }
 
 // NewHandlerSet 创建聚合。参数为子包依赖（各自最小依赖集）。
-func NewHandlerSet(ad adminh.Dependencies, wd wikih.Dependencies, dbg debugh.Dependencies) *HandlerSet {
+func NewHandlerSet(ad adminh.Dependencies, wd wikih.Dependencies, dbg debugh.Dependencies, gwd gatewayh.Dependencies) *HandlerSet {
 	return &HandlerSet{
 		Auth:           adminh.NewAuthHandler(ad),
 		User:           adminh.NewUserHandler(ad),
@@ -42,7 +45,9 @@ func NewHandlerSet(ad adminh.Dependencies, wd wikih.Dependencies, dbg debugh.Dep
 		Log:            adminh.NewLogHandler(ad),
 		Cache:          adminh.NewCacheHandler(ad),
 		Index:          adminh.NewIndexHandler(ad),
+		RateLimit:      adminh.NewRateLimitHandler(ad),
 		Wiki:           wikih.NewWikiHandler(wd),
 		Debug:          debugh.New(dbg),
+		Gateway:        gatewayh.NewGatewayHandler(gwd),
 	}
 }
@@ -0,0 +1,16 @@
+package gateway
+
+import (
+	"go-apiadmin/internal/config"
+	"go-apiadmin/internal/logging"
+	"go-apiadmin/internal/service"
+)
+
+// Dependencies gateway 子包最小依赖集合
+type Dependencies struct {
+	Gateway *service.GatewayService
+	Fields  *service.FieldsService
+	Tokens  *service.AppTokenService
+	Config  *config.Config
+	Logger  *logging.Logger
+}
@@ -0,0 +1,186 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/service"
+	"go-apiadmin/internal/util/retcode"
+	"go-apiadmin/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type GatewayHandler struct{ d Dependencies }
+
+func NewGatewayHandler(d Dependencies) *GatewayHandler { return &GatewayHandler{d: d} }
+
+// Resolve 解析 /api/*path 对应接口（状态 + 方法校验），写入上下文 gw_api 供后续中间件使用
+func (h *GatewayHandler) Resolve(c *gin.Context) {
+	api, err := h.d.Gateway.Resolve(c.Request.Context(), c.Param("path"))
+	if err != nil {
+		response.Error(c, errCode(err, retcode.DB_READ_ERROR), err.Error())
+		c.Abort()
+		return
+	}
+	if !h.d.Gateway.MethodAllowed(api, c.Request.Method) {
+		response.Error(c, retcode.INVALID, service.ErrGatewayMethod.Error())
+		c.Abort()
+		return
+	}
+	c.Set("gw_api", api)
+	c.Next()
+}
+
+// Lifecycle 废弃 / 下线接口：写入 Deprecation / Sunset / Link 响应头并异步记录调用方，
+// retired 或（开启 reject_after_sunset 时）已过下线时间的接口返回 API_SUNSET。需挂在令牌/签名校验之后，以便识别应用。
+func (h *GatewayHandler) Lifecycle(c *gin.Context) {
+	api := c.MustGet("gw_api").(*model.AdminInterfaceList)
+	if !service.LifecycleNotice(api) {
+		c.Next()
+		return
+	}
+	now := time.Now()
+	for k, vs := range service.LifecycleHeaders(api, h.d.Gateway.IfList.Successor(c.Request.Context(), api)) {
+		for _, v := range vs {
+			c.Header(k, v)
+		}
+	}
+	appID := ""
+	if v, ok := c.Get("gw_app"); ok {
+		if app, ok := v.(*model.AdminApp); ok && app != nil {
+			appID = app.AppID
+		}
+	}
+	// 调用方统计在请求内一次 pipeline 写入（单次 Redis 往返），失败只记日志
+	if err := h.d.Gateway.Stats.RecordDeprecated(c.Request.Context(), api.Hash, appID, now); err != nil {
+		h.d.Logger.WithContext(c.Request.Context()).Error("gateway_deprecated_record_failed", zap.String("hash", api.Hash), zap.String("app_id", appID), zap.Error(err))
+	}
+	if err := h.d.Gateway.CheckLifecycle(api, now); err != nil {
+		response.Error(c, errCode(err, retcode.API_SUNSET), err.Error())
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// Serve 转发至后端并原样回写状态码/响应头/响应体；测试接口或携带 mock 头时返回模拟数据，
+// 接口开启响应缓存时 GET/HEAD 优先读缓存（X-Gateway-Cache: HIT/MISS）
+func (h *GatewayHandler) Serve(c *gin.Context) {
+	api := c.MustGet("gw_api").(*model.AdminInterfaceList)
+	mockHeader := ""
+	if name := h.d.Config.Gateway.MockHeader; name != "" {
+		mockHeader = c.GetHeader(name)
+	}
+	if service.MockEnabled(api, mockHeader) {
+		resp, err := h.d.Gateway.Mock(c.Request.Context(), api, h.d.Fields)
+		if err != nil {
+			response.Error(c, retcode.DB_READ_ERROR, err.Error())
+			return
+		}
+		writeResponse(c, resp)
+		return
+	}
+	cacheKey := ""
+	if rc := h.d.Gateway.Cache; rc.Enabled(api, c.Request.Method) {
+		var err error
+		if cacheKey, err = rc.Key(c.Request.Context(), api, cacheRequest(c)); err != nil {
+			// 版本号读取失败时绕过缓存直接转发
+			h.d.Logger.WithContext(c.Request.Context()).Warn("gateway_cache_key_failed", zap.String("hash", api.Hash), zap.Error(err))
+		} else if resp, ok := rc.Get(c.Request.Context(), cacheKey); ok {
+			c.Header("X-Gateway-Cache", "HIT")
+			writeResponse(c, resp)
+			return
+		}
+	}
+	body, err := service.ReadRequestBody(c.Request, h.d.Gateway.MaxBody)
+	if err != nil {
+		response.Error(c, errCode(err, retcode.PARAM_INVALID), err.Error())
+		return
+	}
+	resp, err := h.d.Gateway.Forward(c.Request.Context(), api, service.GatewayRequest{
+		Method:   c.Request.Method,
+		RawQuery: c.Request.URL.RawQuery,
+		Header:   c.Request.Header,
+		Body:     body,
+		ClientIP: c.ClientIP(),
+	})
+	if err != nil {
+		h.d.Logger.WithContext(c.Request.Context()).Error("gateway_forward_failed", zap.String("hash", api.Hash), zap.String("api_class", api.APIClass), zap.Error(err))
+		response.Error(c, errCode(err, retcode.CURL_ERROR), err.Error())
+		return
+	}
+	if cacheKey != "" {
+		h.d.Gateway.Cache.Set(c.Request.Context(), cacheKey, api, resp)
+		c.Header("X-Gateway-Cache", "MISS")
+	}
+	writeResponse(c, resp)
+}
+
+// cacheRequest 组装缓存 key 要素，应用取自令牌/签名中间件写入的 gw_app
+func cacheRequest(c *gin.Context) service.CacheRequest {
+	r := service.CacheRequest{Method: c.Request.Method, Query: c.Request.URL.Query(), Header: c.Request.Header}
+	if v, ok := c.Get("gw_app"); ok {
+		if app, ok := v.(*model.AdminApp); ok && app != nil {
+			r.AppID = app.AppID
+		}
+	}
+	return r
+}
+
+// AccessToken 应用凭据换取访问令牌（app_id + app_secret）
+func (h *GatewayHandler) AccessToken(c *gin.Context) {
+	var req struct {
+		AppID     string `form:"app_id" json:"app_id"`
+		AppSecret string `form:"app_secret" json:"app_secret"`
+	}
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, retcode.JSON_PARSE_FAIL, "invalid body")
+		return
+	}
+	tok, err := h.d.Tokens.Issue(c.Request.Context(), req.AppID, req.AppSecret)
+	if err != nil {
+		code := retcode.CACHE_SAVE_ERROR
+		if errors.Is(err, service.ErrAppCredential) || errors.Is(err, service.ErrAppDisabled) {
+			code = retcode.AUTH_ERROR
+		}
+		response.Error(c, code, err.Error())
+		return
+	}
+	response.Success(c, tok)
+}
+
+func writeResponse(c *gin.Context, resp *service.GatewayResponse) {
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			c.Writer.Header().Add(k, v)
+		}
+	}
+	status := resp.Status
+	if status <= 0 {
+		status = http.StatusOK
+	}
+	c.Data(status, resp.Header.Get("Content-Type"), resp.Body)
+}
+
+// errCode 网关错误 -> 业务码，非预定义错误返回 def
+func errCode(err error, def int) int {
+	switch {
+	case errors.Is(err, service.ErrGatewayNotFound):
+		return retcode.NOT_EXISTS
+	case errors.Is(err, service.ErrGatewayDisabled), errors.Is(err, service.ErrGatewayMethod), errors.Is(err, service.ErrGatewayDraft):
+		return retcode.INVALID
+	case errors.Is(err, service.ErrGatewayRetired), errors.Is(err, service.ErrGatewaySunset):
+		return retcode.API_SUNSET
+	case errors.Is(err, service.ErrGatewayTooLarge):
+		return retcode.PARAM_INVALID
+	case errors.Is(err, service.ErrGatewayNoUpstream), errors.Is(err, service.ErrGatewayRespTooLarge):
+		return retcode.CURL_ERROR
+	case errors.Is(err, service.ErrGatewayBreakerOpen):
+		return retcode.UPSTREAM_UNAVAILABLE
+	}
+	return def
+}
@@ -0,0 +1,142 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/service"
+	"go-apiadmin/internal/util/retcode"
+	"go-apiadmin/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求体格式：决定默认值回写位置
+const (
+	bodyNone = iota
+	bodyJSON
+	bodyForm
+)
+
+// Validate 按接口请求字段（admin_fields type=0）校验 query + body，失败返回 PARAM_INVALID 及逐字段错误；
+// 通过后将缺省字段的 default 回写至请求（JSON/表单写入 body，其余写入 query）。
+func (h *GatewayHandler) Validate(c *gin.Context) {
+	if h.d.Fields == nil {
+		c.Next()
+		return
+	}
+	api := c.MustGet("gw_api").(*model.AdminInterfaceList)
+	body, err := service.ReadRequestBody(c.Request, h.d.Gateway.MaxBody)
+	if err != nil {
+		response.Error(c, errCode(err, retcode.PARAM_INVALID), err.Error())
+		c.Abort()
+		return
+	}
+	payload, kind := collectPayload(c, body)
+	res, err := h.d.Fields.Validate(c.Request.Context(), api.Hash, payload)
+	if err != nil {
+		response.Error(c, retcode.DB_READ_ERROR, err.Error())
+		c.Abort()
+		return
+	}
+	if !res.Valid {
+		response.JSON(c, retcode.PARAM_INVALID, service.ErrFieldsInvalid.Error(), gin.H{"errors": res.Errors})
+		c.Abort()
+		return
+	}
+	defaults := map[string]interface{}{}
+	for k, v := range res.Payload {
+		if _, ok := payload[k]; !ok {
+			defaults[k] = v
+		}
+	}
+	if len(defaults) > 0 {
+		applyDefaults(c, body, kind, defaults)
+	}
+	c.Next()
+}
+
+// collectPayload 合并 query 与 body 参数（body 优先），单值取字符串，多值保留切片
+func collectPayload(c *gin.Context, body []byte) (map[string]interface{}, int) {
+	payload := map[string]interface{}{}
+	mergeValues(payload, c.Request.URL.Query())
+	ct := c.ContentType()
+	switch {
+	case strings.Contains(ct, "json") && len(bytes.TrimSpace(body)) > 0:
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var obj map[string]interface{}
+		if dec.Decode(&obj) == nil {
+			for k, v := range obj {
+				payload[k] = v
+			}
+			return payload, bodyJSON
+		}
+	case ct == "application/x-www-form-urlencoded":
+		if vals, err := url.ParseQuery(string(body)); err == nil {
+			mergeValues(payload, vals)
+			return payload, bodyForm
+		}
+	case ct == "multipart/form-data":
+		if err := c.Request.ParseMultipartForm(int64(len(body)) + 1); err == nil && c.Request.MultipartForm != nil {
+			mergeValues(payload, c.Request.MultipartForm.Value)
+			for k := range c.Request.MultipartForm.File {
+				payload[k] = "file"
+			}
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return payload, bodyNone
+}
+
+func mergeValues(dst map[string]interface{}, vals map[string][]string) {
+	for k, vs := range vals {
+		switch len(vs) {
+		case 0:
+		case 1:
+			dst[k] = vs[0]
+		default:
+			dst[k] = vs
+		}
+	}
+}
+
+// applyDefaults 将默认值写回请求，供 Serve 转发
+func applyDefaults(c *gin.Context, body []byte, kind int, defaults map[string]interface{}) {
+	switch kind {
+	case bodyJSON:
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var obj map[string]interface{}
+		if dec.Decode(&obj) == nil {
+			for k, v := range defaults {
+				obj[k] = v
+			}
+			if b, err := json.Marshal(obj); err == nil {
+				c.Request.Body = io.NopCloser(bytes.NewReader(b))
+				c.Request.ContentLength = int64(len(b))
+				return
+			}
+		}
+	case bodyForm:
+		if vals, err := url.ParseQuery(string(body)); err == nil {
+			for k, v := range defaults {
+				vals.Set(k, fmt.Sprint(v))
+			}
+			b := []byte(vals.Encode())
+			c.Request.Body = io.NopCloser(bytes.NewReader(b))
+			c.Request.ContentLength = int64(len(b))
+			return
+		}
+	}
+	q := c.Request.URL.Query()
+	for k, v := range defaults {
+		q.Set(k, fmt.Sprint(v))
+	}
+	c.Request.URL.RawQuery = q.Encode()
+}
@@ -10,6 +10,8 @@ import (
 // Dependencies wiki 子包最小依赖集合
 type Dependencies struct {
 	Wiki   *service.WikiService
+	Auth   *service.AuthService  // 后台登录态换取 wiki 会话
+	TryIt  *service.TryItService // 在线调试，未开启时为 nil
 	Config *config.Config
 	Logger *logging.Logger
 	Cache  cache.Cache
@@ -1,8 +1,11 @@
 package wiki
 
 import (
+	"errors"
 	"fmt"
+	"go-apiadmin/internal/repository/dao"
 	"go-apiadmin/internal/service"
+	"go-apiadmin/internal/util/httputil"
 	"go-apiadmin/internal/util/retcode"
 	"net/http"
 	"sort"
@@ -90,7 +93,8 @@ func (h *WikiHandler) Detail(c *gin.Context) {
 		c.Status(http.StatusOK)
 		return
 	}
-	m, err := h.d.Wiki.Detail(c.Request.Context(), hash, c.Request.Host)
+	ui, _ := c.Get("wiki_user")
+	m, err := h.d.Wiki.Detail(c.Request.Context(), toWikiUserInfo(ui), hash, c.Request.Host)
 	if err != nil {
 		c.Set("resp", gin.H{"code": retcode.NOT_EXISTS, "msg": err.Error(), "data": gin.H{}})
 		c.Status(http.StatusOK)
@@ -100,6 +104,47 @@ func (h *WikiHandler) Detail(c *gin.Context) {
 	c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "success", "data": m})
 	c.Status(http.StatusOK)
 }
+
+// SSO 后台用户以 admin access token（Authorization: Bearer 或参数 token）换取 wiki 会话，可见全部接口
+func (h *WikiHandler) SSO(c *gin.Context) {
+	token := ""
+	if auth := c.GetHeader("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		token = strings.TrimSpace(auth[7:])
+	}
+	if token == "" {
+		var req struct {
+			Token string `json:"token" form:"token"`
+		}
+		_ = c.ShouldBind(&req)
+		token = strings.TrimSpace(req.Token)
+	}
+	if token == "" {
+		c.Set("resp", gin.H{"code": retcode.AUTH_ERROR, "msg": "missing token", "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	ttl := time.Duration(h.d.Config.Wiki.OnlineTimeSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = 86400 * time.Second
+	}
+	res, err := h.d.Auth.WikiSSO(c.Request.Context(), token, ttl)
+	if err != nil {
+		code := retcode.CACHE_SAVE_ERROR
+		switch {
+		case errors.Is(err, service.ErrSSOToken):
+			code = retcode.AUTH_ERROR
+		case errors.Is(err, service.ErrSSOExpired):
+			code = retcode.ACCESS_TOKEN_TIMEOUT
+		case errors.Is(err, service.ErrSSOUser):
+			code = retcode.LOGIN_ERROR
+		}
+		c.Set("resp", gin.H{"code": code, "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "登录成功", "data": res})
+	c.Status(http.StatusOK)
+}
 func (h *WikiHandler) Logout(c *gin.Context) {
 	apiAuth := c.GetHeader("ApiAuth")
 	if apiAuth == "" {
@@ -108,6 +153,12 @@ func (h *WikiHandler) Logout(c *gin.Context) {
 	apiAuth = strings.TrimSpace(apiAuth)
 	ui, _ := c.Get("wiki_user")
 	user := toWikiUserInfo(ui)
+	if user.AppID == "-1" { // 后台单点登录会话
+		_ = h.d.Auth.WikiSSOLogout(c.Request.Context(), apiAuth)
+		c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "登出成功", "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
 	h.d.Wiki.Logout(c.Request.Context(), apiAuth, user.ID)
 	c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "登出成功", "data": gin.H{}})
 	c.Status(http.StatusOK)
@@ -125,10 +176,18 @@ func (h *WikiHandler) Search(c *gin.Context) {
 			limit = n
 		}
 	}
-	list := h.d.Wiki.Search(c.Request.Context(), kw, limit)
+	ui, _ := c.Get("wiki_user")
+	list, err := h.d.Wiki.Search(c.Request.Context(), toWikiUserInfo(ui), kw, limit)
+	if err != nil {
+		c.Set("resp", gin.H{"code": retcode.DB_READ_ERROR, "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
 	c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "success", "data": gin.H{"list": list, "count": len(list)}})
 	c.Status(http.StatusOK)
 }
+
+// GroupHot 热门分组；range 为 all（默认，全部时间热度）或 24h / 7d 等最近区间浏览量
 func (h *WikiHandler) GroupHot(c *gin.Context) {
 	limit := 10
 	if v := c.Query("limit"); v != "" {
@@ -136,10 +195,42 @@ func (h *WikiHandler) GroupHot(c *gin.Context) {
 			limit = n
 		}
 	}
-	groups := h.d.Wiki.HotGroups(c.Request.Context(), limit)
+	hours, err := service.ParseViewRange(c.Query("range"))
+	if err != nil {
+		c.Set("resp", gin.H{"code": retcode.PARAM_INVALID, "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	groups := h.d.Wiki.HotGroups(c.Request.Context(), limit, hours)
 	c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "success", "data": gin.H{"list": groups}})
 	c.Status(http.StatusOK)
 }
+
+// HotApis 可见接口浏览量排行；range 默认 7d，mine=1 仅统计当前应用的浏览
+func (h *WikiHandler) HotApis(c *gin.Context) {
+	limit := 10
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+	rng := c.DefaultQuery("range", "7d")
+	hours, err := service.ParseViewRange(rng)
+	if err != nil {
+		c.Set("resp", gin.H{"code": retcode.PARAM_INVALID, "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	ui, _ := c.Get("wiki_user")
+	list, err := h.d.Wiki.HotAPIs(c.Request.Context(), toWikiUserInfo(ui), hours, limit, c.Query("mine") == "1")
+	if err != nil {
+		c.Set("resp", gin.H{"code": retcode.DB_READ_ERROR, "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "success", "data": gin.H{"list": list, "count": len(list), "range": rng}})
+	c.Status(http.StatusOK)
+}
 func (h *WikiHandler) Fields(c *gin.Context) {
 	hash := strings.TrimSpace(c.Query("hash"))
 	if hash == "" {
@@ -147,7 +238,8 @@ func (h *WikiHandler) Fields(c *gin.Context) {
 		c.Status(http.StatusOK)
 		return
 	}
-	info, err := h.d.Wiki.Fields(c.Request.Context(), hash)
+	ui, _ := c.Get("wiki_user")
+	info, err := h.d.Wiki.Fields(c.Request.Context(), toWikiUserInfo(ui), hash)
 	if err != nil {
 		c.Set("resp", gin.H{"code": retcode.NOT_EXISTS, "msg": err.Error(), "data": gin.H{}})
 		c.Status(http.StatusOK)
@@ -197,3 +289,160 @@ func toWikiUserInfo(v interface{}) service.WikiUserInfo {
 		return service.WikiUserInfo{}
 	}
 }
+
+// OpenAPI 导出当前登录应用可见接口的 OpenAPI 3.1 文档（后台登录为全部接口）；download=1 时作为附件下载
+func (h *WikiHandler) OpenAPI(c *gin.Context) {
+	ui, _ := c.Get("wiki_user")
+	doc, err := h.d.Wiki.OpenAPI(c.Request.Context(), service.OpenAPIOptions{
+		Title:     h.d.Config.AppMeta.Name,
+		Version:   h.d.Config.AppMeta.Version,
+		ServerURL: httputil.BaseURL(c.Request),
+		User:      toWikiUserInfo(ui),
+	})
+	if err != nil {
+		c.Set("resp", gin.H{"code": retcode.DB_READ_ERROR, "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	if c.Query("download") == "1" {
+		c.Header("Content-Disposition", `attachment; filename="openapi.json"`)
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// Export 导出 Postman / Insomnia 集合（format=postman|insomnia），范围为当前应用可见接口
+func (h *WikiHandler) Export(c *gin.Context) {
+	ui, _ := c.Get("wiki_user")
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", service.ExportPostman)))
+	doc, err := h.d.Wiki.Export(c.Request.Context(), service.ExportOptions{
+		Format:  format,
+		Name:    h.d.Config.AppMeta.Name,
+		BaseURL: httputil.BaseURL(c.Request),
+		User:    toWikiUserInfo(ui),
+	})
+	if err != nil {
+		code := retcode.DB_READ_ERROR
+		if errors.Is(err, service.ErrExportFormat) {
+			code = retcode.PARAM_INVALID
+		}
+		c.Set("resp", gin.H{"code": code, "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	if c.Query("download") == "1" {
+		name := "postman_collection.json"
+		if format == service.ExportInsomnia {
+			name = "insomnia.json"
+		}
+		c.Header("Content-Disposition", `attachment; filename="`+name+`"`)
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// Changelog 可见接口的变更记录（hash 可选，limit 默认 20，最大 100）
+func (h *WikiHandler) Changelog(c *gin.Context) {
+	limit := 20
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+	ui, _ := c.Get("wiki_user")
+	list, err := h.d.Wiki.Changelog(c.Request.Context(), toWikiUserInfo(ui), strings.TrimSpace(c.Query("hash")), limit)
+	if err != nil {
+		c.Set("resp", gin.H{"code": retcode.DB_READ_ERROR, "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "success", "data": gin.H{"list": list, "count": len(list)}})
+	c.Status(http.StatusOK)
+}
+
+// Schema 可见接口的请求 / 响应 JSON Schema（draft 2020-12）与示例报文（hash 必填）
+func (h *WikiHandler) Schema(c *gin.Context) {
+	hash := strings.TrimSpace(c.Query("hash"))
+	if hash == "" {
+		c.Set("resp", gin.H{"code": retcode.EMPTY_PARAMS, "msg": "hash required", "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	ui, _ := c.Get("wiki_user")
+	res, err := h.d.Wiki.Schema(c.Request.Context(), toWikiUserInfo(ui), hash)
+	if err != nil {
+		code := retcode.DB_READ_ERROR
+		if errors.Is(err, service.ErrSchemaNotFound) {
+			code = retcode.NOT_EXISTS
+		}
+		c.Set("resp", gin.H{"code": code, "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "success", "data": res})
+	c.Status(http.StatusOK)
+}
+
+// ContractEvents 已授权接口的契约变更事件（hash、breaking=1 可选；since_id 增量拉取，按 id 升序）
+func (h *WikiHandler) ContractEvents(c *gin.Context) {
+	q := dao.ContractEventQuery{BreakingOnly: c.Query("breaking") == "1"}
+	q.SinceID, _ = strconv.ParseInt(c.Query("since_id"), 10, 64)
+	q.Page, _ = strconv.Atoi(c.Query("page"))
+	q.Limit, _ = strconv.Atoi(c.Query("limit"))
+	if hash := strings.TrimSpace(c.Query("hash")); hash != "" {
+		q.Hashes = []string{hash}
+	}
+	ui, _ := c.Get("wiki_user")
+	res, err := h.d.Wiki.ContractEvents(c.Request.Context(), toWikiUserInfo(ui), q)
+	if err != nil {
+		c.Set("resp", gin.H{"code": retcode.DB_READ_ERROR, "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "success", "data": res})
+	c.Status(http.StatusOK)
+}
+
+// TryItTemplate 在线调试表单：接口信息与按字段默认值预填的参数（hash 必填）
+func (h *WikiHandler) TryItTemplate(c *gin.Context) {
+	ui, _ := c.Get("wiki_user")
+	res, err := h.d.TryIt.Template(c.Request.Context(), toWikiUserInfo(ui), c.Query("hash"))
+	if err != nil {
+		c.Set("resp", gin.H{"code": tryItCode(err), "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "success", "data": res})
+	c.Status(http.StatusOK)
+}
+
+// TryIt 在线调试：以当前应用身份经网关调用接口（JSON：hash, method, params, headers；后台登录可传 app_id）
+func (h *WikiHandler) TryIt(c *gin.Context) {
+	var req service.TryItParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Set("resp", gin.H{"code": retcode.JSON_PARSE_FAIL, "msg": "invalid body", "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	ui, _ := c.Get("wiki_user")
+	res, err := h.d.TryIt.TryIt(c.Request.Context(), toWikiUserInfo(ui), req, c.ClientIP())
+	if err != nil {
+		c.Set("resp", gin.H{"code": tryItCode(err), "msg": err.Error(), "data": gin.H{}})
+		c.Status(http.StatusOK)
+		return
+	}
+	c.Set("resp", gin.H{"code": retcode.SUCCESS, "msg": "success", "data": res})
+	c.Status(http.StatusOK)
+}
+
+func tryItCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTryItDisabled), errors.Is(err, service.ErrGatewayMethod):
+		return retcode.INVALID
+	case errors.Is(err, service.ErrTryItDenied):
+		return retcode.NOT_EXISTS
+	case errors.Is(err, service.ErrTryItApp), errors.Is(err, service.ErrAppDisabled):
+		return retcode.AUTH_ERROR
+	case errors.Is(err, service.ErrTryItLimited):
+		return retcode.RATE_LIMITED
+	}
+	return retcode.DB_READ_ERROR
+}
@@ -9,6 +9,7 @@ import (
 	"go-apiadmin/internal/metrics"
 	"go-apiadmin/internal/mq/kafka"
 	redisrepo "go-apiadmin/internal/repository/redis"
+	"go-apiadmin/internal/service"
 
 	"gorm.io/gorm"
 )
@@ -19,6 +20,7 @@ type HealthChecker struct {
 	redis    *redisrepo.Client
 	producer *kafka.Producer
 	etcdCli  *etcd.Client
+	breakers *service.BreakerGroup // 网关后端熔断状态，仅展示，不影响就绪判定
 
 	cacheMu     sync.Mutex
 	cacheResult map[string]interface{}
@@ -201,6 +203,16 @@ func (h *HealthChecker) Readiness(ctx context.Context) (map[string]interface{},
 	if upTotal < len(deps) {
 		res["status"] = "degraded"
 	}
+	if h.breakers != nil {
+		open := 0
+		for _, b := range h.breakers.Snapshot() {
+			if b.State != service.BreakerClosed {
+				open++
+			}
+			res["detail"] = append(res["detail"].([]map[string]interface{}), map[string]interface{}{"dep": "upstream", "upstream": b.Upstream, "up": b.State == service.BreakerClosed, "breaker": b.State, "failures": b.Failures, "opened_at": b.OpenedAt})
+		}
+		res["gateway_breakers_open"] = open
+	}
 
 	// 写缓存
 	h.cacheMu.Lock()
@@ -0,0 +1,88 @@
+package observability
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/logging"
+	"go-apiadmin/internal/mq/kafka"
+	"go-apiadmin/internal/service"
+	"go-apiadmin/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GatewayCallLog 网关调用日志与统计：挂在 /api 链最前，调用结束后发送 gateway_call 消息到 Kafka
+// （sender 非 nil 时异步批量，否则同步 producer），并异步累加应用/接口分钟统计。
+// 错误口径：HTTP 5xx，或网关返回负业务码（鉴权/签名/限流/熔断等拒绝）；统计仅覆盖已解析的接口。
+func GatewayCallLog(l *logging.Logger, p *kafka.Producer, sender *kafka.AccessAsyncSender, stats *service.GatewayStatsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		code := c.GetInt(response.CodeKey)
+		hash, apiClass, resolved := strings.Trim(c.Param("path"), "/"), "", false
+		if v, ok := c.Get("gw_api"); ok {
+			if api, ok := v.(*model.AdminInterfaceList); ok && api != nil {
+				hash, apiClass, resolved = api.Hash, api.APIClass, true
+			}
+		}
+		appID := ""
+		if v, ok := c.Get("gw_app"); ok {
+			if app, ok := v.(*model.AdminApp); ok && app != nil {
+				appID = app.AppID
+			}
+		}
+		isErr := status >= 500 || code < 0
+		reqSize := c.Request.ContentLength
+		if reqSize < 0 {
+			reqSize = 0
+		}
+		entry := map[string]interface{}{
+			"type":       "gateway_call",
+			"app_id":     appID,
+			"hash":       hash,
+			"api_class":  apiClass,
+			"method":     c.Request.Method,
+			"status":     status,
+			"code":       code,
+			"error":      isErr,
+			"latency_ms": latency.Milliseconds(),
+			"req_size":   reqSize,
+			"resp_size":  c.Writer.Size(),
+			"cache":      c.Writer.Header().Get("X-Gateway-Cache"),
+			"ip":         c.ClientIP(),
+			"ts":         start.Unix(),
+		}
+		var headers map[string]string
+		if v, ok := c.Get("trace_id"); ok {
+			if traceID, ok := v.(string); ok && traceID != "" {
+				entry["trace_id"] = traceID
+				headers = map[string]string{"trace_id": traceID}
+			}
+		}
+		b, _ := json.Marshal(entry)
+		switch {
+		case sender != nil:
+			sender.Enqueue(kafka.AsyncMessage{Ctx: c.Request.Context(), Key: []byte(hash), Value: b, Headers: headers, EnqueueAt: time.Now()})
+		case p != nil:
+			if headers != nil {
+				_ = p.SendWithHeaders(c.Request.Context(), []byte(hash), b, headers)
+			} else {
+				_ = p.Send(c.Request.Context(), []byte(hash), b)
+			}
+		}
+		if stats == nil || !resolved { // 未解析到接口的路径不计统计，避免任意路径产生 key
+			return
+		}
+		rec := service.CallRecord{AppID: appID, Hash: hash, Latency: latency, Error: isErr, At: start}
+		// 单次 pipeline 写入，请求内同步完成，失败只记日志
+		if err := stats.Record(c.Request.Context(), rec); err != nil {
+			l.WithContext(c.Request.Context()).Error("gateway_stats_record_failed", zap.String("hash", rec.Hash), zap.Error(err))
+		}
+	}
+}
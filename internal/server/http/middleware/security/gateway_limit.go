@@ -0,0 +1,42 @@
+package security
+
+import (
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/logging"
+	"go-apiadmin/internal/metrics"
+	"go-apiadmin/internal/service"
+	"go-apiadmin/internal/util/retcode"
+	"go-apiadmin/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// NewGatewayLimit 网关限流与配额：按 gw_app（可无）与 gw_api 命中规则判定，
+// 超速返回 RATE_LIMITED，超配额返回 QUOTA_EXCEEDED；Redis 异常时放行并记录日志。需挂在令牌/签名校验之后。
+func NewGatewayLimit(s *service.RateLimitService, lg *logging.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		api := c.MustGet("gw_api").(*model.AdminInterfaceList)
+		appID := ""
+		if v, ok := c.Get("gw_app"); ok {
+			appID = v.(*model.AdminApp).AppID
+		}
+		res, err := s.Allow(c.Request.Context(), appID, api.Hash)
+		if err != nil {
+			lg.WithContext(c.Request.Context()).Error("gateway_limit_failed", zap.String("hash", api.Hash), zap.String("app_id", appID), zap.Error(err))
+			c.Next()
+			return
+		}
+		if !res.Allowed {
+			metrics.GatewayRejectedTotal.WithLabelValues(res.Reason, res.Scope).Inc()
+			if res.Reason == service.LimitReasonRate {
+				response.Error(c, retcode.RATE_LIMITED, "请求过于频繁")
+			} else {
+				response.Error(c, retcode.QUOTA_EXCEEDED, "调用次数已超出配额")
+			}
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
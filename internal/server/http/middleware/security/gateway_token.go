@@ -0,0 +1,56 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/service"
+	"go-apiadmin/internal/util/retcode"
+	"go-apiadmin/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewGatewayToken 网关令牌校验：接口 access_token=1 时要求 Access-Token 头（兼容 Authorization: Bearer），
+// 校验通过后按 app_api 校验接口授权，并写入上下文 gw_app。需挂在网关 Resolve 之后。
+func NewGatewayToken(s *service.AppTokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		api := c.MustGet("gw_api").(*model.AdminInterfaceList)
+		if api.AccessToken != 1 {
+			c.Next()
+			return
+		}
+		token := c.GetHeader("Access-Token")
+		if token == "" {
+			if auth := c.GetHeader("Authorization"); strings.HasPrefix(strings.ToLower(auth), "bearer ") {
+				token = auth[7:]
+			}
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 300*time.Millisecond)
+		defer cancel()
+		app, err := s.Verify(ctx, token)
+		if err != nil {
+			code := retcode.AUTH_ERROR
+			switch {
+			case errors.Is(err, service.ErrTokenInvalid):
+				code = retcode.ACCESS_TOKEN_TIMEOUT
+			case errors.Is(err, service.ErrTokenMissing), errors.Is(err, service.ErrAppDisabled):
+			default:
+				code = retcode.DB_READ_ERROR
+			}
+			response.Error(c, code, err.Error())
+			c.Abort()
+			return
+		}
+		if !service.AppAllowed(app, api.Hash) {
+			response.Error(c, retcode.AUTH_ERROR, service.ErrAppNoPermission.Error())
+			c.Abort()
+			return
+		}
+		c.Set("gw_app", app)
+		c.Next()
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"go-apiadmin/internal/config"
 	redisrepo "go-apiadmin/internal/repository/redis"
 	"go-apiadmin/internal/util/retcode"
 	"go-apiadmin/pkg/response"
@@ -34,6 +35,21 @@ func NewWikiAuth(r *redisrepo.Client) gin.HandlerFunc {
 			if m == nil {
 				m = map[string]interface{}{}
 			}
+			// 后台单点登录会话：对应的后台 JTI 已登出 / 被挤下线时同步失效
+			if jti, _ := m["jti"].(string); jti != "" {
+				prefix := "jwt:jti:"
+				if v, ok := c.Get("app_config"); ok {
+					if cfg, ok := v.(*config.Config); ok && cfg.Redis.JTIPrefix != "" {
+						prefix = cfg.Redis.JTIPrefix
+					}
+				}
+				if r.Get(ctx, prefix+jti) == "" {
+					r.Del(ctx, "Login:"+apiAuth)
+					response.Error(c, retcode.ACCESS_TOKEN_TIMEOUT, "后台登录已失效")
+					c.Abort()
+					return
+				}
+			}
 			m["app_id"] = "-1"
 			c.Set("wiki_user", m)
 			c.Next()
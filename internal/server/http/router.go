@@ -13,6 +13,7 @@ import (
 	handlerset "go-apiadmin/internal/server/http/handler"
 	adm "go-apiadmin/internal/server/http/handler/admin"
 	debugh "go-apiadmin/internal/server/http/handler/debug"
+	gatewayh "go-apiadmin/internal/server/http/handler/gateway"
 	wikih "go-apiadmin/internal/server/http/handler/wiki"
 	"go-apiadmin/internal/server/http/middleware" // keep for ResponseWrapper, CORS
 	obs "go-apiadmin/internal/server/http/middleware/observability"
@@ -25,7 +26,7 @@ import (
 )
 
 // NewRouter 仅负责分组与中间件装配，具体业务放在 handler 层
-func NewRouter(jwtm *jwt.Manager, logger *logging.Logger, producer *kafka.Producer, asyncSender *kafka.AccessAsyncSender, db *gorm.DB, redis *redisrepo.Client, authSvc *service.AuthService, userSvc *service.UserService, permSvc *service.PermissionService, menuSvc *service.MenuService, authGroupSvc *service.AuthGroupService, authRuleSvc *service.AuthRuleService, appSvc *service.AppService, appGroupSvc *service.AppGroupService, ifgSvc *service.InterfaceGroupService, iflSvc *service.InterfaceListService, fieldsSvc *service.FieldsService, logSvc *service.LogService, etcdCli *etcd.Client, cfg *config.Config, wikiSvc *service.WikiService) *gin.Engine {
+func NewRouter(jwtm *jwt.Manager, logger *logging.Logger, producer *kafka.Producer, asyncSender *kafka.AccessAsyncSender, db *gorm.DB, redis *redisrepo.Client, authSvc *service.AuthService, userSvc *service.UserService, permSvc *service.PermissionService, menuSvc *service.MenuService, authGroupSvc *service.AuthGroupService, authRuleSvc *service.AuthRuleService, appSvc *service.AppService, appGroupSvc *service.AppGroupService, ifgSvc *service.InterfaceGroupService, iflSvc *service.InterfaceListService, fieldsSvc *service.FieldsService, logSvc *service.LogService, etcdCli *etcd.Client, cfg *config.Config, wikiSvc *service.WikiService, gwSvc *service.GatewayService, tokSvc *service.AppTokenService, signSvc *service.AppSignService, rlSvc *service.RateLimitService, importSvc *service.ImportService, revSvc *service.RevisionService, contractSvc *service.ContractService, tryItSvc *service.TryItService) *gin.Engine {
 	r := gin.New()
 	// 基础中间件链
 	chain := []gin.HandlerFunc{middleware.ConfigInjector(cfg), gin.Recovery(), middleware.CORS(), obs.TraceMiddleware(), obs.LoggerContextMiddleware(logger), middleware.ResponseWrapper(), obs.AccessLog(logger)}
@@ -41,6 +42,9 @@ func NewRouter(jwtm *jwt.Manager, logger *logging.Logger, producer *kafka.Produc
 
 	// 健康检查
 	hc := NewHealthChecker(db, redis, producer, etcdCli)
+	if cfg.Gateway.Enable && gwSvc != nil {
+		hc.breakers = gwSvc.Breakers
+	}
 	r.GET("/healthz", func(c *gin.Context) { c.JSON(200, hc.Liveness()) })
 	r.GET("/readyz", func(c *gin.Context) {
 		if c.Query("refresh") == "1" {
@@ -59,12 +63,13 @@ func NewRouter(jwtm *jwt.Manager, logger *logging.Logger, producer *kafka.Produc
 	// 依赖注入给 handler 构造器 (拆分 admin / wiki / debug 子包依赖)
 	ad := adm.Dependencies{
 		Auth: authSvc, User: userSvc, Perm: permSvc, Menu: menuSvc, AuthGroup: authGroupSvc, AuthRule: authRuleSvc,
-		App: appSvc, AppGroup: appGroupSvc, IfGroup: ifgSvc, IfList: iflSvc, Fields: fieldsSvc, Log: logSvc,
+		App: appSvc, AppGroup: appGroupSvc, IfGroup: ifgSvc, IfList: iflSvc, Fields: fieldsSvc, Log: logSvc, RateLimit: rlSvc, RespCache: gwSvc.Cache, GwStats: gwSvc.Stats, Wiki: wikiSvc, Import: importSvc, Revision: revSvc, Contract: contractSvc, TryIt: tryItSvc,
 		JWT: jwtm, Logger: logger, Producer: producer, Config: cfg, Cache: menuSvc.Cache,
 	}
-	wd := wikih.Dependencies{Wiki: wikiSvc, Config: cfg, Logger: logger, Cache: menuSvc.Cache}
+	wd := wikih.Dependencies{Wiki: wikiSvc, Auth: authSvc, TryIt: tryItSvc, Config: cfg, Logger: logger, Cache: menuSvc.Cache}
 	dbgd := debugh.Dependencies{Config: cfg, Logger: logger}
-	h := handlerset.NewHandlerSet(ad, wd, dbgd)
+	gwd := gatewayh.Dependencies{Gateway: gwSvc, Fields: fieldsSvc, Tokens: tokSvc, Config: cfg, Logger: logger}
+	h := handlerset.NewHandlerSet(ad, wd, dbgd, gwd)
 
 	// Debug routes (仅在 debug 日志级别时开放, 且非生产配置)
 	if cfg.Log.Level == "debug" {
@@ -150,6 +155,8 @@ func NewRouter(jwtm *jwt.Manager, logger *logging.Logger, producer *kafka.Produc
 			appGroup.POST("/edit", sec.Require(), h.App.Edit)
 			appGroup.GET("/del", sec.Require(), h.App.Delete)
 			appGroup.GET("/refreshAppSecret", sec.Require(), h.App.RefreshSecret)
+			appGroup.GET("/stats", sec.Require(), h.App.Stats)
+			appGroup.GET("/visibility", sec.Require(), h.App.Visibility)
 		}
 		// AppGroup
 		appgGroup := adminGrp.Group("/AppGroup")
@@ -160,6 +167,7 @@ func NewRouter(jwtm *jwt.Manager, logger *logging.Logger, producer *kafka.Produc
 			appgGroup.POST("/edit", sec.Require(), h.AppGroup.Edit)
 			appgGroup.GET("/changeStatus", sec.Require(), h.AppGroup.ChangeStatus)
 			appgGroup.GET("/del", sec.Require(), h.AppGroup.Delete)
+			appgGroup.GET("/visibility", sec.Require(), h.AppGroup.Visibility)
 		}
 		// InterfaceGroup
 		ifgGroup := adminGrp.Group("/InterfaceGroup")
@@ -180,7 +188,30 @@ func NewRouter(jwtm *jwt.Manager, logger *logging.Logger, producer *kafka.Produc
 			iflGroup.POST("/add", sec.Require(), h.InterfaceList.Add)
 			iflGroup.POST("/edit", sec.Require(), h.InterfaceList.Edit)
 			iflGroup.GET("/changeStatus", sec.Require(), h.InterfaceList.ChangeStatus)
+			iflGroup.POST("/lifecycle", sec.Require(), h.InterfaceList.Lifecycle)
+			iflGroup.GET("/deprecationUsage", sec.Require(), h.InterfaceList.DeprecationUsage)
 			iflGroup.GET("/del", sec.Require(), h.InterfaceList.Delete)
+			iflGroup.GET("/purgeCache", sec.Require(), h.InterfaceList.PurgeCache)
+			iflGroup.GET("/stats", sec.Require(), h.InterfaceList.Stats)
+			iflGroup.GET("/openapi", sec.Require(), h.InterfaceList.OpenAPI)
+			iflGroup.POST("/import", sec.Require(), h.InterfaceList.Import)
+			iflGroup.GET("/staticDocs", sec.Require(), h.InterfaceList.StaticDocs)
+			iflGroup.GET("/sdk", sec.Require(), h.InterfaceList.SDK)
+			iflGroup.GET("/revisions", sec.Require(), h.InterfaceList.Revisions)
+			iflGroup.GET("/revision", sec.Require(), h.InterfaceList.Revision)
+			iflGroup.GET("/revisionDiff", sec.Require(), h.InterfaceList.RevisionDiff)
+			iflGroup.POST("/rollback", sec.Require(), h.InterfaceList.Rollback)
+			iflGroup.GET("/contractEvents", sec.Require(), h.InterfaceList.ContractEvents)
+			iflGroup.GET("/tryItLogs", sec.Require(), h.InterfaceList.TryItLogs)
+		}
+		// RateLimit 网关限流/配额
+		rlGroup := adminGrp.Group("/RateLimit")
+		{
+			rlGroup.GET("/index", sec.Require(), h.RateLimit.Index)
+			rlGroup.POST("/add", sec.Require(), h.RateLimit.Add)
+			rlGroup.POST("/edit", sec.Require(), h.RateLimit.Edit)
+			rlGroup.GET("/del", sec.Require(), h.RateLimit.Delete)
+			rlGroup.GET("/usage", sec.Require(), h.RateLimit.Usage)
 		}
 		// Fields
 		fieldsGroup := adminGrp.Group("/Fields")
@@ -188,10 +219,13 @@ func NewRouter(jwtm *jwt.Manager, logger *logging.Logger, producer *kafka.Produc
 			fieldsGroup.GET("/index", sec.Require(), h.Fields.Index)
 			fieldsGroup.GET("/request", sec.Require(), h.Fields.Request)
 			fieldsGroup.GET("/response", sec.Require(), h.Fields.Response)
+			fieldsGroup.GET("/tree", sec.Require(), h.Fields.Tree)
+			fieldsGroup.GET("/schema", sec.Require(), h.Fields.Schema)
 			fieldsGroup.POST("/add", sec.Require(), h.Fields.Add)
 			fieldsGroup.POST("/edit", sec.Require(), h.Fields.Edit)
 			fieldsGroup.GET("/del", sec.Require(), h.Fields.Delete)
 			fieldsGroup.POST("/upload", sec.Require(), h.Fields.Upload)
+			fieldsGroup.POST("/validate", sec.Require(), h.Fields.Validate)
 		}
 		// Log
 		logGroup := adminGrp.Group("/Log")
@@ -217,29 +251,64 @@ func NewRouter(jwtm *jwt.Manager, logger *logging.Logger, producer *kafka.Produc
 	{
 		wikiGrp.GET("/errorCode", h.Wiki.ErrorCode)
 		wikiGrp.POST("/login", h.Wiki.Login)
+		wikiGrp.POST("/sso", h.Wiki.SSO)
 		wikiGrp.GET("/groupList", sec.NewWikiAuth(redis), h.Wiki.GroupList)
 		wikiGrp.GET("/detail", sec.NewWikiAuth(redis), h.Wiki.Detail)
 		wikiGrp.POST("/logout", sec.NewWikiAuth(redis), h.Wiki.Logout)
 		// 新增接口
 		wikiGrp.GET("/search", sec.NewWikiAuth(redis), h.Wiki.Search)
 		wikiGrp.GET("/groupHot", sec.NewWikiAuth(redis), h.Wiki.GroupHot)
+		wikiGrp.GET("/hotApis", sec.NewWikiAuth(redis), h.Wiki.HotApis)
 		wikiGrp.GET("/fields", sec.NewWikiAuth(redis), h.Wiki.Fields)
+		wikiGrp.GET("/schema", sec.NewWikiAuth(redis), h.Wiki.Schema)
 		wikiGrp.GET("/appInfo", sec.NewWikiAuth(redis), h.Wiki.AppInfo)
 		wikiGrp.GET("/dataType", h.Wiki.DataType)
+		wikiGrp.GET("/openapi", sec.NewWikiAuth(redis), h.Wiki.OpenAPI)
+		wikiGrp.GET("/export", sec.NewWikiAuth(redis), h.Wiki.Export)
+		wikiGrp.GET("/changelog", sec.NewWikiAuth(redis), h.Wiki.Changelog)
+		wikiGrp.GET("/contractEvents", sec.NewWikiAuth(redis), h.Wiki.ContractEvents)
+		wikiGrp.GET("/tryIt", sec.NewWikiAuth(redis), h.Wiki.TryItTemplate)
+		wikiGrp.POST("/tryIt", sec.NewWikiAuth(redis), h.Wiki.TryIt)
 
 		api := wikiGrp.Group("/Api")
 		{
 			api.GET("/errorCode", h.Wiki.ErrorCode)
 			api.POST("/login", h.Wiki.Login)
+			api.POST("/sso", h.Wiki.SSO)
 			api.GET("/groupList", sec.NewWikiAuth(redis), h.Wiki.GroupList)
 			api.GET("/detail", sec.NewWikiAuth(redis), h.Wiki.Detail)
 			api.POST("/logout", sec.NewWikiAuth(redis), h.Wiki.Logout)
 			// 新增接口（兼容路径）
 			api.GET("/search", sec.NewWikiAuth(redis), h.Wiki.Search)
 			api.GET("/groupHot", sec.NewWikiAuth(redis), h.Wiki.GroupHot)
+			api.GET("/hotApis", sec.NewWikiAuth(redis), h.Wiki.HotApis)
 			api.GET("/fields", sec.NewWikiAuth(redis), h.Wiki.Fields)
+			api.GET("/schema", sec.NewWikiAuth(redis), h.Wiki.Schema)
 			api.GET("/appInfo", sec.NewWikiAuth(redis), h.Wiki.AppInfo)
 			api.GET("/dataType", h.Wiki.DataType)
+			api.GET("/openapi", sec.NewWikiAuth(redis), h.Wiki.OpenAPI)
+			api.GET("/export", sec.NewWikiAuth(redis), h.Wiki.Export)
+			api.GET("/changelog", sec.NewWikiAuth(redis), h.Wiki.Changelog)
+			api.GET("/contractEvents", sec.NewWikiAuth(redis), h.Wiki.ContractEvents)
+			api.GET("/tryIt", sec.NewWikiAuth(redis), h.Wiki.TryItTemplate)
+			api.POST("/tryIt", sec.NewWikiAuth(redis), h.Wiki.TryIt)
+		}
+	}
+	// 接口网关 /api/{hash|api_class}（配置开启时注册，未开启保持 404 兼容）
+	if cfg.Gateway.Enable {
+		r.POST("/gateway/accessToken", h.Gateway.AccessToken)
+		gw := r.Group("/api")
+		{
+			var gwChain []gin.HandlerFunc
+			if cfg.Gateway.CallLog { // 调用日志与 access log 共用发送方式（异步批量 / 同步）
+				var sender *kafka.AccessAsyncSender
+				if cfg.Log.AccessKafkaAsync.Enable {
+					sender = asyncSender
+				}
+				gwChain = append(gwChain, obs.GatewayCallLog(logger, producer, sender, gwSvc.Stats))
+			}
+			gwChain = append(gwChain, h.Gateway.Resolve, sec.NewGatewayToken(tokSvc), sec.NewGatewaySign(signSvc, gwSvc.MaxBody), h.Gateway.Lifecycle, sec.NewGatewayLimit(rlSvc, logger), h.Gateway.Validate, h.Gateway.Serve)
+			gw.Any("/*path", gwChain...)
 		}
 	}
 	// 统一 404
@@ -34,6 +34,7 @@ type AppGroupDTO struct {
 	Description string `json:"description"`
 	Status      int8   `json:"status"`
 	Hash        string `json:"hash"`
+	APIShow     string `json:"api_show,omitempty"` // 可见范围模板
 }
 
 type ListAppGroupResult struct {
@@ -67,7 +68,7 @@ func (s *AppGroupService) List(ctx context.Context) (*ListAppGroupResult, error)
 	}
 	res := make([]AppGroupDTO, 0, len(list))
 	for _, g := range list {
-		res = append(res, AppGroupDTO{ID: g.ID, Name: g.Name, Description: g.Description, Status: g.Status, Hash: g.Hash})
+		res = append(res, AppGroupDTO{ID: g.ID, Name: g.Name, Description: g.Description, Status: g.Status, Hash: g.Hash, APIShow: g.APIShow})
 	}
 	result := &ListAppGroupResult{List: res}
 	if s.Cache != nil {
@@ -78,21 +79,24 @@ func (s *AppGroupService) List(ctx context.Context) (*ListAppGroupResult, error)
 }
 
 type AddAppGroupParams struct {
-	Name, Description, Hash string
-	Status                  int8
+	Name, Description, Hash, APIShow string
+	Status                           int8
 }
 
 type EditAppGroupParams struct {
-	ID                      int64
-	Name, Description, Hash *string
-	Status                  *int8
+	ID                               int64
+	Name, Description, Hash, APIShow *string
+	Status                           *int8
 }
 
 func (s *AppGroupService) Add(ctx context.Context, p AddAppGroupParams) error {
 	if strings.TrimSpace(p.Name) == "" {
 		return errors.New("name required")
 	}
-	m := &model.AdminAppGroup{Name: p.Name, Description: p.Description, Status: p.Status, Hash: p.Hash}
+	if err := ValidateAPIShow(p.APIShow); err != nil {
+		return err
+	}
+	m := &model.AdminAppGroup{Name: p.Name, Description: p.Description, Status: p.Status, Hash: p.Hash, APIShow: p.APIShow}
 	if err := s.DAO.Create(ctx, m); err != nil {
 		return err
 	}
@@ -122,9 +126,20 @@ func (s *AppGroupService) Edit(ctx context.Context, p EditAppGroupParams) error
 	if p.Status != nil {
 		m.Status = *p.Status
 	}
+	if p.APIShow != nil {
+		if err := ValidateAPIShow(*p.APIShow); err != nil {
+			return err
+		}
+		m.APIShow = *p.APIShow
+	}
 	if err := s.DAO.Update(ctx, m); err != nil {
 		return err
 	}
+	if p.APIShow != nil && m.APIShow == "" { // Updates(struct) 忽略空串，清空模板需显式写入
+		if err := s.DAO.UpdateAPIShow(ctx, m.ID, ""); err != nil {
+			return err
+		}
+	}
 	s.invalidate()
 	return nil
 }
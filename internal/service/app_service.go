@@ -75,13 +75,19 @@ type ListAppParams struct {
 }
 
 type AppDTO struct {
-	ID        int64  `json:"id"`
-	AppID     string `json:"app_id"`
-	AppSecret string `json:"app_secret"`
-	AppName   string `json:"app_name"`
-	AppStatus int8   `json:"app_status"`
-	AppInfo   string `json:"app_info"`
-	AppGroup  string `json:"app_group"`
+	ID         int64  `json:"id"`
+	AppID      string `json:"app_id"`
+	AppSecret  string `json:"app_secret"`
+	AppName    string `json:"app_name"`
+	AppStatus  int8   `json:"app_status"`
+	AppInfo    string `json:"app_info"`
+	AppGroup   string `json:"app_group"`
+	AppAPI     string `json:"app_api"`      // 授权接口 hash，逗号分隔
+	AppAPIShow string `json:"app_api_show"` // 文档可见接口 {group_hash: [hash]}，继承时为追加项
+	ShowMode   int8   `json:"show_mode"`    // 0 仅 app_api_show；1 继承应用分组可见范围
+	AppAPIHide string `json:"app_api_hide"` // 继承时排除的接口
+	SignEnable int8   `json:"sign_enable"`
+	SignWindow int    `json:"sign_window"`
 }
 
 type ListAppResult struct {
@@ -117,16 +123,25 @@ func (s *AppService) List(ctx context.Context, p ListAppParams) (*ListAppResult,
 }
 
 type AddAppParams struct {
-	AppName  string
-	AppInfo  string
-	AppGroup string
-	Status   int8
+	AppName    string
+	AppInfo    string
+	AppGroup   string
+	AppAPI     string
+	AppAPIShow string
+	ShowMode   int8
+	AppAPIHide string
+	SignEnable int8
+	SignWindow int
+	Status     int8
 }
 
 func (s *AppService) Add(ctx context.Context, p AddAppParams) (int64, error) {
 	if strings.TrimSpace(p.AppName) == "" {
 		return 0, errors.New("app_name required")
 	}
+	if err := validateVisibility(p.ShowMode, p.AppAPIShow, p.AppAPIHide); err != nil {
+		return 0, err
+	}
 	appID, err := s.generateAppID(ctx)
 	if err != nil {
 		return 0, err
@@ -135,7 +150,7 @@ func (s *AppService) Add(ctx context.Context, p AddAppParams) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	m := &model.AdminApp{AppID: appID, AppSecret: secret, AppName: p.AppName, AppStatus: p.Status, AppInfo: p.AppInfo, AppGroup: p.AppGroup, AppAddTime: time.Now().Unix()}
+	m := &model.AdminApp{AppID: appID, AppSecret: secret, AppName: p.AppName, AppStatus: p.Status, AppInfo: p.AppInfo, AppGroup: p.AppGroup, AppAPI: p.AppAPI, AppAPIShow: p.AppAPIShow, ShowMode: p.ShowMode, AppAPIHide: p.AppAPIHide, SignEnable: p.SignEnable, SignWindow: p.SignWindow, AppAddTime: time.Now().Unix()}
 	if err := s.DAO.Create(ctx, m); err != nil {
 		return 0, err
 	}
@@ -144,11 +159,17 @@ func (s *AppService) Add(ctx context.Context, p AddAppParams) (int64, error) {
 }
 
 type EditAppParams struct {
-	ID       int64
-	AppName  *string
-	AppInfo  *string
-	AppGroup *string
-	Status   *int8
+	ID         int64
+	AppName    *string
+	AppInfo    *string
+	AppGroup   *string
+	AppAPI     *string
+	AppAPIShow *string
+	ShowMode   *int8
+	AppAPIHide *string
+	SignEnable *int8
+	SignWindow *int
+	Status     *int8
 }
 
 func (s *AppService) Edit(ctx context.Context, p EditAppParams) error {
@@ -171,12 +192,46 @@ func (s *AppService) Edit(ctx context.Context, p EditAppParams) error {
 	if p.AppGroup != nil {
 		m.AppGroup = *p.AppGroup
 	}
+	if p.AppAPI != nil {
+		m.AppAPI = *p.AppAPI
+	}
+	visibility := p.AppAPIShow != nil || p.ShowMode != nil || p.AppAPIHide != nil
+	if p.AppAPIShow != nil {
+		m.AppAPIShow = *p.AppAPIShow
+	}
+	if p.ShowMode != nil {
+		m.ShowMode = *p.ShowMode
+	}
+	if p.AppAPIHide != nil {
+		m.AppAPIHide = *p.AppAPIHide
+	}
+	if visibility {
+		if err := validateVisibility(m.ShowMode, m.AppAPIShow, m.AppAPIHide); err != nil {
+			return err
+		}
+	}
 	if p.Status != nil {
 		m.AppStatus = *p.Status
 	}
 	if err := s.DAO.Update(ctx, m); err != nil {
 		return err
 	}
+	if visibility { // 可见范围允许清空、切回自定义，需显式写入零值
+		if err := s.DAO.UpdateVisibility(ctx, m.ID, m.ShowMode, m.AppAPIShow, m.AppAPIHide); err != nil {
+			return err
+		}
+	}
+	if p.SignEnable != nil || p.SignWindow != nil { // Updates(struct) 忽略零值，签名开关需显式写入
+		if p.SignEnable != nil {
+			m.SignEnable = *p.SignEnable
+		}
+		if p.SignWindow != nil {
+			m.SignWindow = *p.SignWindow
+		}
+		if err := s.DAO.UpdateSign(ctx, m.ID, m.SignEnable, m.SignWindow); err != nil {
+			return err
+		}
+	}
 	s.invalidateOne(m.ID)
 	return nil
 }
@@ -218,7 +273,7 @@ func (s *AppService) GetInfo(ctx context.Context, id int64) (*AppDTO, error) {
 	if m == nil {
 		return nil, errors.New("not found")
 	}
-	dto := &AppDTO{ID: m.ID, AppID: m.AppID, AppSecret: m.AppSecret, AppName: m.AppName, AppStatus: m.AppStatus, AppInfo: m.AppInfo, AppGroup: m.AppGroup}
+	dto := &AppDTO{ID: m.ID, AppID: m.AppID, AppSecret: m.AppSecret, AppName: m.AppName, AppStatus: m.AppStatus, AppInfo: m.AppInfo, AppGroup: m.AppGroup, AppAPI: m.AppAPI, AppAPIShow: m.AppAPIShow, ShowMode: m.ShowMode, AppAPIHide: m.AppAPIHide, SignEnable: m.SignEnable, SignWindow: m.SignWindow}
 	if s.Cache != nil {
 		b, _ := json.Marshal(dto)
 		_ = s.Cache.SetEX(ctx, ck, string(b), 120*time.Second)
@@ -256,3 +311,14 @@ func (s *AppService) invalidateOne(id int64) {
 	// 列表全部失效困难; 采用 TTL 自然过期 + 可选: 标记版本号
 }
 func (s *AppService) invalidateList() { /* no-op; rely on TTL */ }
+
+// validateVisibility 校验文档可见范围配置
+func validateVisibility(mode int8, show, hide string) error {
+	if mode != ShowModeCustom && mode != ShowModeInherit {
+		return ErrShowMode
+	}
+	if err := ValidateAPIShow(show); err != nil {
+		return err
+	}
+	return ValidateAPIShow(hide)
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/pkg/cache"
+	"go-apiadmin/internal/repository/dao"
+	redisrepo "go-apiadmin/internal/repository/redis"
+)
+
+// AppTokenService 应用访问令牌：app_id/app_secret 换取短期 access_token（Redis 存储），
+// 网关按接口 access_token 标记校验令牌，并按 admin_app.app_api 校验接口授权。
+type AppTokenService struct {
+	AppDAO *dao.AdminAppDAO
+	Redis  *redisrepo.Client
+	Cache  cache.Cache   // app_id -> 应用信息（短 TTL，权限/状态变更最多延迟 30s 生效）
+	TTL    time.Duration // 令牌有效期
+}
+
+// AccessToken 令牌换取结果
+type AccessToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// appTokenInfo 令牌存储内容；Sum 为签发时 app_secret 摘要，刷新密钥后旧令牌自动失效
+type appTokenInfo struct {
+	ID    int64  `json:"id"`
+	AppID string `json:"app_id"`
+	Sum   string `json:"sum"`
+}
+
+var (
+	ErrAppCredential   = errors.New("AppId或AppSecret错误")
+	ErrAppDisabled     = errors.New("当前应用已被封禁，请联系管理员")
+	ErrTokenMissing    = errors.New("缺少AccessToken")
+	ErrTokenInvalid    = errors.New("AccessToken已失效")
+	ErrAppNoPermission = errors.New("当前应用无权访问该接口")
+)
+
+const (
+	accessTokenPrefix = "AccessToken:"
+	appTokenAppPrefix = "apptoken:app:"
+)
+
+func NewAppTokenService(app *dao.AdminAppDAO, r *redisrepo.Client, c cache.Cache, ttl time.Duration) *AppTokenService {
+	if ttl <= 0 {
+		ttl = 2 * time.Hour
+	}
+	return &AppTokenService{AppDAO: app, Redis: r, Cache: c, TTL: ttl}
+}
+
+// Issue 校验 app 凭据并签发令牌；同一应用可同时持有多个未过期令牌
+func (s *AppTokenService) Issue(ctx context.Context, appID, appSecret string) (*AccessToken, error) {
+	if strings.TrimSpace(appID) == "" || strings.TrimSpace(appSecret) == "" {
+		return nil, ErrAppCredential
+	}
+	app, err := s.AppDAO.FindByAppID(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil || subtle.ConstantTimeCompare([]byte(app.AppSecret), []byte(appSecret)) != 1 {
+		return nil, ErrAppCredential
+	}
+	if app.AppStatus == 0 {
+		return nil, ErrAppDisabled
+	}
+	return s.IssueFor(ctx, app, s.TTL)
+}
+
+// IssueFor 为已校验身份的应用签发指定有效期的令牌（wiki 在线调试等服务端代发场景）
+func (s *AppTokenService) IssueFor(ctx context.Context, app *model.AdminApp, ttl time.Duration) (*AccessToken, error) {
+	if app.AppStatus == 0 {
+		return nil, ErrAppDisabled
+	}
+	if ttl <= 0 {
+		ttl = s.TTL
+	}
+	token := generateToken()
+	b, _ := json.Marshal(appTokenInfo{ID: app.ID, AppID: app.AppID, Sum: secretSum(app.AppSecret)})
+	if err := s.Redis.SetTTL(ctx, accessTokenPrefix+token, string(b), ttl); err != nil {
+		return nil, err
+	}
+	return &AccessToken{AccessToken: token, ExpiresIn: int64(ttl / time.Second)}, nil
+}
+
+// Verify 校验令牌并返回所属应用（应用被禁用/删除、密钥已刷新均视为失效）
+func (s *AppTokenService) Verify(ctx context.Context, token string) (*model.AdminApp, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, ErrTokenMissing
+	}
+	raw := s.Redis.Get(ctx, accessTokenPrefix+token)
+	if raw == "" {
+		return nil, ErrTokenInvalid
+	}
+	var info appTokenInfo
+	if json.Unmarshal([]byte(raw), &info) != nil {
+		return nil, ErrTokenInvalid
+	}
+	app, err := s.App(ctx, info.AppID)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil || secretSum(app.AppSecret) != info.Sum {
+		return nil, ErrTokenInvalid
+	}
+	if app.AppStatus == 0 {
+		return nil, ErrAppDisabled
+	}
+	return app, nil
+}
+
+// Revoke 主动作废令牌
+func (s *AppTokenService) Revoke(ctx context.Context, token string) {
+	s.Redis.Del(ctx, accessTokenPrefix+strings.TrimSpace(token))
+}
+
+// App 按 app_id 读取应用（带缓存，不存在返回 nil,nil）
+func (s *AppTokenService) App(ctx context.Context, appID string) (*model.AdminApp, error) {
+	ck := appTokenAppPrefix + appID
+	if s.Cache != nil {
+		if v, _ := s.Cache.Get(ctx, ck); v != "" {
+			var m model.AdminApp
+			if json.Unmarshal([]byte(v), &m) == nil {
+				return &m, nil
+			}
+		}
+	}
+	app, err := s.AppDAO.FindByAppID(ctx, appID)
+	if err != nil || app == nil {
+		return nil, err
+	}
+	if s.Cache != nil {
+		b, _ := json.Marshal(app)
+		_ = s.Cache.SetEX(ctx, ck, string(b), 30*time.Second)
+	}
+	return app, nil
+}
+
+// AppAllowed 接口是否在应用授权列表 app_api（逗号分隔 hash）内
+func AppAllowed(app *model.AdminApp, hash string) bool {
+	for _, h := range strings.Split(app.AppAPI, ",") {
+		if strings.TrimSpace(h) == hash && hash != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func secretSum(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:8])
+}
@@ -173,12 +173,15 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (string,
 	return token, newRefresh, uid, nil
 }
 
-// Logout 删除当前 JTI 使 token 立即失效（需在上层解析出 jti）
+// Logout 删除当前 JTI 使 token 立即失效（需在上层解析出 jti），并吊销由其换取的 wiki 会话
 func (s *AuthService) Logout(ctx context.Context, jti string) error {
 	if jti == "" || s.Redis == nil {
 		return nil
 	}
-	return s.Redis.Client.Del(ctx, s.redisJTIPrefix()+jti).Err()
+	if err := s.Redis.Client.Del(ctx, s.redisJTIPrefix()+jti).Err(); err != nil {
+		return err
+	}
+	return s.revokeWikiSSO(ctx, jti)
 }
 
 func (s *AuthService) redisJTIPrefix() string       { return s.JTIPrefix }
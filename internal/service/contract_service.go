@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/repository/dao"
+)
+
+// 接口契约检查：编辑前将变更后的定义与当前定义比对，逐项标记是否破坏调用方；
+// 含破坏性变更时须显式确认（confirm_breaking），写入成功后记录事件并通知已授权该接口的应用。
+const (
+	ContractSourceEdit        = "edit"
+	ContractSourceFieldAdd    = "field_add"
+	ContractSourceFieldEdit   = "field_edit"
+	ContractSourceFieldDelete = "field_delete"
+	ContractSourceBatchUpload = "batch_upload"
+
+	contractEventType = "contract_change" // 消息 type，与 gateway_call 等共用 topic
+)
+
+var ErrBreakingChange = errors.New("存在破坏性变更，需确认后提交")
+
+// BreakingChangeError 未确认的破坏性变更，Changes 为全部变更（含非破坏性）
+type BreakingChangeError struct {
+	Hash    string
+	Changes []ContractChange
+}
+
+func (e *BreakingChangeError) Error() string {
+	var names []string
+	for _, c := range e.Changes {
+		if c.Breaking {
+			names = append(names, c.Scope+"."+c.Name+"("+c.Reason+")")
+		}
+	}
+	return ErrBreakingChange.Error() + "：" + strings.Join(names, "，")
+}
+
+func (e *BreakingChangeError) Is(target error) bool { return target == ErrBreakingChange }
+
+// ContractChange 单项契约变更
+type ContractChange struct {
+	DefinitionChange
+	Breaking bool   `json:"breaking"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ContractCheck 一次编辑的比对结果，写入成功后交给 Emit
+type ContractCheck struct {
+	Hash     string
+	APIClass string
+	Source   string
+	Changes  []ContractChange
+}
+
+// Breaking 是否含破坏性变更
+func (c *ContractCheck) Breaking() bool {
+	for _, ch := range c.Changes {
+		if ch.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// EventPublisher 事件消息发送（*kafka.Producer 满足）
+type EventPublisher interface {
+	Send(ctx context.Context, key, value []byte) error
+}
+
+type ContractService struct {
+	DAO       *dao.AdminContractEventDAO
+	ListDAO   *dao.AdminInterfaceListDAO
+	FieldsDAO *dao.AdminFieldsDAO
+	AppDAO    *dao.AdminAppDAO
+	Publisher EventPublisher // 可选
+}
+
+func NewContractService(d *dao.AdminContractEventDAO, list *dao.AdminInterfaceListDAO, fields *dao.AdminFieldsDAO, app *dao.AdminAppDAO) *ContractService {
+	return &ContractService{DAO: d, ListDAO: list, FieldsDAO: fields, AppDAO: app}
+}
+
+// Check 读取 hash 当前定义，经 mutate 得到变更后的定义并比对；含破坏性变更且未确认时返回 *BreakingChangeError。
+// s 为 nil 时不检查。
+func (s *ContractService) Check(ctx context.Context, hash, source string, confirm bool, mutate func(*InterfaceSnapshot)) (*ContractCheck, error) {
+	if s == nil || hash == "" {
+		return nil, nil
+	}
+	cur, err := loadSnapshot(ctx, s.ListDAO, s.FieldsDAO, hash)
+	if err != nil {
+		return nil, err
+	}
+	next := cur.clone()
+	mutate(next)
+	check := &ContractCheck{Hash: hash, Source: source, Changes: ClassifyChanges(cur, next)}
+	if api := firstInterface(next, cur); api != nil {
+		check.APIClass = api.APIClass
+	}
+	if check.Breaking() && !confirm {
+		return nil, &BreakingChangeError{Hash: hash, Changes: check.Changes}
+	}
+	return check, nil
+}
+
+// Emit 写入成功后记录事件并通知；无变更时忽略，失败不影响主流程
+func (s *ContractService) Emit(ctx context.Context, check *ContractCheck) {
+	if s == nil || check == nil || len(check.Changes) == 0 {
+		return
+	}
+	var apps []string
+	if list, err := s.AppDAO.ListByAPIHash(ctx, check.Hash); err == nil {
+		for i := range list {
+			if AppAllowed(&list[i], check.Hash) {
+				apps = append(apps, list[i].AppID)
+			}
+		}
+	}
+	changes, _ := json.Marshal(check.Changes)
+	ev := &model.AdminContractEvent{Hash: check.Hash, APIClass: check.APIClass, Source: check.Source, Changes: string(changes),
+		Apps: strings.Join(apps, ","), UserID: operatorID(ctx), AddTime: time.Now().Unix()}
+	if check.Breaking() {
+		ev.Breaking = 1
+	}
+	if err := s.DAO.Create(ctx, ev); err != nil {
+		return
+	}
+	if s.Publisher == nil {
+		return
+	}
+	msg, _ := json.Marshal(map[string]interface{}{
+		"type": contractEventType, "id": ev.ID, "hash": ev.Hash, "api_class": ev.APIClass, "source": ev.Source,
+		"breaking": check.Breaking(), "changes": check.Changes, "apps": apps, "user_id": ev.UserID, "ts": ev.AddTime,
+	})
+	go func() { // 异步发送，避免 Kafka 不可用时阻塞后台编辑
+		sctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = s.Publisher.Send(sctx, []byte(ev.Hash), msg)
+	}()
+}
+
+// ContractEvent 事件输出
+type ContractEvent struct {
+	model.AdminContractEvent
+	Changes []ContractChange `json:"changes"`
+}
+
+// ListContractEventsResult 事件列表
+type ListContractEventsResult struct {
+	List  []ContractEvent `json:"list"`
+	Total int64           `json:"total"`
+}
+
+// Events 查询事件
+func (s *ContractService) Events(ctx context.Context, q dao.ContractEventQuery) (*ListContractEventsResult, error) {
+	list, total, err := s.DAO.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	out := &ListContractEventsResult{List: make([]ContractEvent, 0, len(list)), Total: total}
+	for _, m := range list {
+		ev := ContractEvent{AdminContractEvent: m, Changes: []ContractChange{}}
+		_ = json.Unmarshal([]byte(m.Changes), &ev.Changes)
+		out.List = append(out.List, ev)
+	}
+	return out, nil
+}
+
+// clone 深拷贝快照（mutate 可直接修改）
+func (s *InterfaceSnapshot) clone() *InterfaceSnapshot {
+	out := &InterfaceSnapshot{
+		Request:  append([]model.AdminField{}, s.Request...),
+		Response: append([]model.AdminField{}, s.Response...),
+	}
+	if s.Interface != nil {
+		api := *s.Interface
+		out.Interface = &api
+	}
+	return out
+}
+
+// Fields 按类型返回字段列表指针（0 请求 / 1 响应）
+func (s *InterfaceSnapshot) Fields(typ int8) *[]model.AdminField {
+	if typ == 1 {
+		return &s.Response
+	}
+	return &s.Request
+}
+
+// removeField 按 id 移除字段
+func (s *InterfaceSnapshot) removeField(ids ...int64) {
+	drop := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		drop[id] = true
+	}
+	for _, list := range []*[]model.AdminField{&s.Request, &s.Response} {
+		out := (*list)[:0]
+		for _, f := range *list {
+			if !drop[f.ID] {
+				out = append(out, f)
+			}
+		}
+		*list = out
+	}
+}
+
+func firstInterface(list ...*InterfaceSnapshot) *model.AdminInterfaceList {
+	for _, s := range list {
+		if s.Interface != nil {
+			return s.Interface
+		}
+	}
+	return nil
+}
+
+// ClassifyChanges 比对两个定义并逐项判断是否破坏现有调用方：
+//   - 接口：按 api_class 路由时改名、路由方式变化、收窄请求方法、新增 Access-Token 校验、停用、删除；
+//   - 请求字段：新增必填字段、类型变化、改为必填、新增或修改取值范围；
+//   - 响应字段：删除字段、类型变化、改为非必返、枚举取值变化。
+//
+// 其余变更（说明、示例、分组、缓存 / 超时配置、新增可选请求字段、新增响应字段等）为非破坏性。
+func ClassifyChanges(a, b *InterfaceSnapshot) []ContractChange {
+	diff := DiffSnapshots(a, b)
+	out := make([]ContractChange, 0, len(diff))
+	for _, d := range diff {
+		c := ContractChange{DefinitionChange: d}
+		switch d.Scope {
+		case "interface":
+			c.Reason = interfaceBreaking(a.Interface, b.Interface, d)
+		case "request":
+			c.Reason = requestFieldBreaking(fieldByName(a.Request, d.Name), fieldByName(b.Request, d.Name), d.Op)
+		case "response":
+			c.Reason = responseFieldBreaking(fieldByName(a.Response, d.Name), fieldByName(b.Response, d.Name), d.Op)
+		}
+		c.Breaking = c.Reason != ""
+		out = append(out, c)
+	}
+	return out
+}
+
+func fieldByName(list []model.AdminField, name string) *model.AdminField {
+	for i := len(list) - 1; i >= 0; i-- {
+		if FieldPath(list[i]) == name {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+// interfaceBreaking 返回破坏原因，非破坏性返回空
+func interfaceBreaking(a, b *model.AdminInterfaceList, d DefinitionChange) string {
+	switch {
+	case d.Op == "remove":
+		return "接口删除"
+	case d.Op == "add" || a == nil || b == nil:
+		return ""
+	}
+	switch d.Name {
+	case "api_class":
+		if a.HashType == 1 || b.HashType == 1 {
+			return "接口地址变化"
+		}
+	case "hash_type":
+		return "路由方式变化，接口地址改变"
+	case "method":
+		if a.Method == 0 || b.Method != 0 {
+			return "请求方法改为 " + InterfaceMethod(b.Method)
+		}
+	case "access_token":
+		if a.AccessToken == 0 && b.AccessToken == 1 {
+			return "新增 Access-Token 校验"
+		}
+	case "status":
+		if a.Status == 1 && b.Status != 1 {
+			return "接口停用"
+		}
+	case "lifecycle":
+		if InterfaceLifecycle(b) == LifecycleRetired {
+			return "接口下线"
+		}
+	}
+	return ""
+}
+
+func requestFieldBreaking(a, b *model.AdminField, op string) string {
+	switch op {
+	case "add":
+		if b != nil && b.IsMust == 1 {
+			return "新增必填请求字段"
+		}
+	case "change":
+		if a == nil || b == nil {
+			return ""
+		}
+		if a.DataType != b.DataType {
+			return fmt.Sprintf("类型由 %s 改为 %s", dataTypeMap[int(a.DataType)], dataTypeMap[int(b.DataType)])
+		}
+		if a.IsMust != 1 && b.IsMust == 1 {
+			return "改为必填"
+		}
+		if a.Range != b.Range && b.Range != "" {
+			return "取值范围变化"
+		}
+	}
+	return ""
+}
+
+func responseFieldBreaking(a, b *model.AdminField, op string) string {
+	switch op {
+	case "remove":
+		return "删除响应字段"
+	case "change":
+		if a == nil || b == nil {
+			return ""
+		}
+		if a.DataType != b.DataType {
+			return fmt.Sprintf("类型由 %s 改为 %s", dataTypeMap[int(a.DataType)], dataTypeMap[int(b.DataType)])
+		}
+		if a.IsMust == 1 && b.IsMust != 1 {
+			return "改为非必返"
+		}
+		if b.DataType == DataTypeEnum && a.Range != b.Range {
+			return "枚举取值变化"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"math"
+	"strings"
+
+	"go-apiadmin/internal/domain/model"
+)
+
+// JSONSchema JSON Schema 2020-12 子集（OpenAPI 3.1 schema 与之同源）
+type JSONSchema struct {
+	Schema           string                 `json:"$schema,omitempty"` // 仅根节点：方言声明
+	ID               string                 `json:"$id,omitempty"`
+	Type             string                 `json:"type,omitempty"`
+	Format           string                 `json:"format,omitempty"`
+	Title            string                 `json:"title,omitempty"`
+	Description      string                 `json:"description,omitempty"`
+	Default          interface{}            `json:"default,omitempty"`
+	Enum             []interface{}          `json:"enum,omitempty"`
+	Pattern          string                 `json:"pattern,omitempty"`
+	Minimum          *float64               `json:"minimum,omitempty"`
+	Maximum          *float64               `json:"maximum,omitempty"`
+	MinLength        *int                   `json:"minLength,omitempty"`
+	MaxLength        *int                   `json:"maxLength,omitempty"`
+	MinItems         *int                   `json:"minItems,omitempty"`
+	MaxItems         *int                   `json:"maxItems,omitempty"`
+	ContentMediaType string                 `json:"contentMediaType,omitempty"`
+	Properties       map[string]*JSONSchema `json:"properties,omitempty"`
+	Required         []string               `json:"required,omitempty"`
+	Items            *JSONSchema            `json:"items,omitempty"`
+}
+
+func intPtr(f *float64) *int {
+	if f == nil {
+		return nil
+	}
+	n := int(*f)
+	return &n
+}
+
+// FieldSchema 单字段 schema：类型映射 + range 约束（数值取值范围 / 字符串与数组长度 / 枚举候选）
+func FieldSchema(f model.AdminField) *JSONSchema {
+	sc := &JSONSchema{Description: strings.TrimSpace(f.Info)}
+	if sc.Description == "" && f.ShowName != "" && f.ShowName != f.FieldName {
+		sc.Description = f.ShowName
+	}
+	rg := parseRange(f.Range, f.DataType)
+	switch f.DataType {
+	case DataTypeInteger:
+		sc.Type, sc.Format = "integer", "int64"
+		sc.Minimum, sc.Maximum = rg.Min, rg.Max
+	case DataTypeFloat:
+		sc.Type, sc.Format = "number", "double"
+		sc.Minimum, sc.Maximum = rg.Min, rg.Max
+	case DataTypeBoolean:
+		sc.Type = "boolean"
+	case DataTypeArray:
+		sc.Type = "array"
+		sc.MinItems, sc.MaxItems = intPtr(rg.Min), intPtr(rg.Max)
+	case DataTypeObject:
+		sc.Type = "object"
+	case DataTypeFile:
+		sc.Type, sc.ContentMediaType = "string", "application/octet-stream"
+	case DataTypeEnum:
+		sc.Type = "string"
+		for _, v := range rg.Enum {
+			sc.Enum = append(sc.Enum, v)
+		}
+	case DataTypeMobile:
+		sc.Type, sc.Pattern = "string", mobileRe.String()
+	default:
+		sc.Type = "string"
+		sc.MinLength, sc.MaxLength = intPtr(rg.Min), intPtr(rg.Max)
+	}
+	if f.Default != "" {
+		sc.Default = defaultValue(f)
+	}
+	return sc
+}
+
+// FieldsSchema 将字段列表按路径（见 BuildFieldTree）组装为 object schema：
+// 数组字段的元素取 "[]" 节点，缺失的中间层按 object / array 补齐。
+func FieldsSchema(defs []model.AdminField) *JSONSchema {
+	root := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+	for _, n := range BuildFieldTree(defs) {
+		root.Properties[n.FieldName] = nodeSchema(n)
+		if n.IsMust == 1 {
+			root.Required = appendUnique(root.Required, n.FieldName)
+		}
+	}
+	return root
+}
+
+// nodeSchema 单节点 schema：数组取元素节点为 items，对象取属性节点为 properties
+func nodeSchema(n *FieldNode) *JSONSchema {
+	sc := &JSONSchema{Type: "object"}
+	if !n.Implicit {
+		sc = FieldSchema(n.AdminField)
+	} else if n.DataType == DataTypeArray {
+		sc.Type = "array"
+	}
+	item, props := n.split()
+	if sc.Type == "array" && item != nil {
+		sc.Items = nodeSchema(item)
+	}
+	if sc.Type == "object" && len(props) > 0 {
+		sc.Properties = make(map[string]*JSONSchema, len(props))
+		for _, c := range props {
+			sc.Properties[c.FieldName] = nodeSchema(c)
+			if c.IsMust == 1 && !c.Implicit {
+				sc.Required = appendUnique(sc.Required, c.FieldName)
+			}
+		}
+	}
+	return sc
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, x := range list {
+		if x == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
+
+// ResponseDataFields 批量上传生成的响应字段以根字段 data(Object) 开头、其余字段位于 data 之下；
+// 此时去掉根字段（及 data. 路径前缀），其余字段即为 data 的内容（与 MockData 口径一致）
+func ResponseDataFields(defs []model.AdminField) []model.AdminField {
+	out := make([]model.AdminField, 0, len(defs))
+	for _, f := range defs {
+		if FieldPath(f) == "data" && f.DataType == DataTypeObject {
+			continue
+		}
+		f.Path = strings.TrimPrefix(FieldPath(f), "data.")
+		out = append(out, f)
+	}
+	return out
+}
+
+// FieldsExample 按字段定义生成嵌套示例对象（层级规则同 FieldsSchema，数组字段生成单元素数组）
+func FieldsExample(defs []model.AdminField) map[string]interface{} {
+	root := map[string]interface{}{}
+	for _, n := range BuildFieldTree(defs) {
+		root[n.FieldName] = nodeExample(n)
+	}
+	return root
+}
+
+// nodeExample 单节点示例值：有子结构时按结构生成，否则取 MockValue
+func nodeExample(n *FieldNode) interface{} {
+	item, props := n.split()
+	if item != nil { // 元素个数满足最小约束（至多 10 个）
+		count := 1
+		if rg := parseRange(n.Range, n.DataType); !n.Implicit && rg.Min != nil && *rg.Min > 1 {
+			count = int(math.Min(*rg.Min, 10))
+		}
+		arr := make([]interface{}, count)
+		for i := range arr {
+			arr[i] = nodeExample(item)
+		}
+		return arr
+	}
+	if len(props) > 0 {
+		obj := make(map[string]interface{}, len(props))
+		for _, c := range props {
+			obj[c.FieldName] = nodeExample(c)
+		}
+		return obj
+	}
+	if n.Implicit {
+		if n.DataType == DataTypeArray {
+			return []interface{}{}
+		}
+		return map[string]interface{}{}
+	}
+	return MockValue(n.AdminField)
+}
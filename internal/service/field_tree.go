@@ -0,0 +1,193 @@
+package service
+
+import (
+	"strings"
+
+	"go-apiadmin/internal/domain/model"
+)
+
+// 字段层级以 path 表示完整 JSON 路径：对象属性以 "." 连接，数组元素以 "[]" 标记，
+// 如 data.user.id、data.list[].id；标量数组的元素类型记为 data.tags[]。
+// 旧数据 path 为空时取 field_name（旧版 OpenAPI 导入的 a.b 形式同样按层级解析，数组父字段下的子字段视为元素属性）。
+const (
+	fieldItemToken = "[]"
+	fieldPathMax   = 255
+)
+
+// FieldPath 字段完整路径
+func FieldPath(f model.AdminField) string {
+	if f.Path != "" {
+		return f.Path
+	}
+	return f.FieldName
+}
+
+// pathTokens "data.list[].id" -> [data list [] id]
+func pathTokens(p string) []string {
+	var out []string
+	for _, seg := range strings.Split(p, ".") {
+		seg = strings.TrimSpace(seg)
+		n := 0
+		for strings.HasSuffix(seg, fieldItemToken) {
+			seg, n = strings.TrimSpace(seg[:len(seg)-len(fieldItemToken)]), n+1
+		}
+		if seg != "" {
+			out = append(out, seg)
+		}
+		for ; n > 0; n-- {
+			out = append(out, fieldItemToken)
+		}
+	}
+	return out
+}
+
+// joinPath pathTokens 的逆过程
+func joinPath(tokens []string) string {
+	var b strings.Builder
+	for i, t := range tokens {
+		if i > 0 && t != fieldItemToken {
+			b.WriteByte('.')
+		}
+		b.WriteString(t)
+	}
+	return b.String()
+}
+
+// NormalizeFieldPath 规范化路径写法（去除空段与空白），非法返回空
+func NormalizeFieldPath(p string) string {
+	tokens := pathTokens(p)
+	if len(tokens) == 0 || tokens[0] == fieldItemToken {
+		return ""
+	}
+	return joinPath(tokens)
+}
+
+// ChildFieldPath 父字段下子字段的路径：父字段为数组时子字段为元素属性
+func ChildFieldPath(parent model.AdminField, name string) string {
+	if parent.DataType == DataTypeArray {
+		return FieldPath(parent) + fieldItemToken + "." + name
+	}
+	return FieldPath(parent) + "." + name
+}
+
+// pathLeaf 路径末段属性名（跳过 "[]"），如 data.tags[] -> tags
+func pathLeaf(p string) string {
+	tokens := pathTokens(p)
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if tokens[i] != fieldItemToken {
+			return tokens[i]
+		}
+	}
+	return ""
+}
+
+// renameLeaf 替换路径末段属性名；数组元素路径（以 "[]" 结尾）随父字段命名，保持不变
+func renameLeaf(p, name string) string {
+	tokens := pathTokens(p)
+	if len(tokens) == 0 || tokens[len(tokens)-1] == fieldItemToken {
+		return p
+	}
+	tokens[len(tokens)-1] = name
+	return NormalizeFieldPath(joinPath(tokens))
+}
+
+// underPath 路径 p 是否位于 prefix 之下（不含自身）
+func underPath(p, prefix string) bool {
+	return strings.HasPrefix(p, prefix+".") || strings.HasPrefix(p, prefix+fieldItemToken)
+}
+
+// FieldNode 字段树节点；Implicit 为路径中间层缺少定义时按 object / array 补齐的节点
+type FieldNode struct {
+	model.AdminField
+	Implicit bool         `json:"implicit,omitempty"`
+	Children []*FieldNode `json:"children,omitempty"`
+	key      string       // 路径末段（属性名或 "[]"）
+}
+
+// isItem 是否为数组元素节点
+func (n *FieldNode) isItem() bool { return n.key == fieldItemToken }
+
+// split 拆分子节点：元素节点与属性节点
+func (n *FieldNode) split() (item *FieldNode, props []*FieldNode) {
+	for _, c := range n.Children {
+		if c.isItem() {
+			item = c
+		} else {
+			props = append(props, c)
+		}
+	}
+	return item, props
+}
+
+// BuildFieldTree 按路径还原字段层级，保持字段原有顺序；同一路径重复定义时取第一条
+func BuildFieldTree(defs []model.AdminField) []*FieldNode {
+	root := &FieldNode{}
+	index := map[string]*FieldNode{}
+	for _, f := range defs {
+		tokens := pathTokens(FieldPath(f))
+		if len(tokens) == 0 || tokens[0] == fieldItemToken {
+			continue
+		}
+		parent := root
+		for i, t := range tokens {
+			key := joinPath(tokens[:i+1])
+			n := index[key]
+			if n == nil {
+				dt := DataTypeObject
+				if i+1 < len(tokens) && tokens[i+1] == fieldItemToken {
+					dt = DataTypeArray
+				}
+				name := t
+				if t == fieldItemToken {
+					name = parent.FieldName
+				}
+				n = &FieldNode{AdminField: model.AdminField{FieldName: name, Path: key, Hash: f.Hash, Type: f.Type, DataType: dt},
+					Implicit: true, key: t}
+				parent.Children = append(parent.Children, n)
+				index[key] = n
+			}
+			if i == len(tokens)-1 && n.Implicit { // 节点名取路径末段（旧数据 field_name 可能为 a.b 形式）
+				name := n.FieldName
+				n.AdminField, n.Implicit = f, false
+				n.FieldName, n.Path = name, key
+			}
+			parent = n
+		}
+	}
+	if root.Children == nil {
+		return []*FieldNode{}
+	}
+	for _, n := range root.Children {
+		normalizeFieldNode(n)
+	}
+	return root.Children
+}
+
+// normalizeFieldNode 数组节点下的属性节点（旧写法 list.id）移入元素节点
+func normalizeFieldNode(n *FieldNode) {
+	if n.DataType == DataTypeArray {
+		item, props := n.split()
+		if len(props) > 0 {
+			if item == nil {
+				item = &FieldNode{AdminField: model.AdminField{FieldName: n.FieldName, Path: n.Path + fieldItemToken, Hash: n.Hash, Type: n.Type, DataType: DataTypeObject},
+					Implicit: true, key: fieldItemToken}
+			}
+			item.Children = append(item.Children, props...)
+			n.Children = []*FieldNode{item}
+		}
+	}
+	for _, c := range n.Children {
+		normalizeFieldNode(c)
+	}
+}
+
+// fieldSubtree 列表中路径位于 path 之下的字段（不含自身）
+func fieldSubtree(list []model.AdminField, path string) []model.AdminField {
+	var out []model.AdminField
+	for _, f := range list {
+		if underPath(FieldPath(f), path) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+)
+
+// 字段数据类型（与 dataTypeMap / 旧版 DataType.php 保持一致）
+const (
+	DataTypeInteger int8 = 1
+	DataTypeString  int8 = 2
+	DataTypeArray   int8 = 3
+	DataTypeFloat   int8 = 4
+	DataTypeBoolean int8 = 5
+	DataTypeFile    int8 = 6
+	DataTypeEnum    int8 = 7
+	DataTypeMobile  int8 = 8
+	DataTypeObject  int8 = 9
+)
+
+var mobileRe = regexp.MustCompile(`^1[3-9]\d{9}$`)
+
+// ErrFieldsInvalid 请求参数未通过字段定义校验
+var ErrFieldsInvalid = errors.New("参数校验失败")
+
+// FieldError 单个字段校验错误
+type FieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"` // require|type|min|max|enum
+	Msg   string `json:"msg"`
+}
+
+// ValidateResult 校验结果；Payload 为补齐默认值后的参数
+type ValidateResult struct {
+	Valid   bool                   `json:"valid"`
+	Errors  []FieldError           `json:"errors"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// fieldRange 解析后的 range 约束：数值为取值范围，字符串/数组为长度范围，枚举为候选值
+type fieldRange struct {
+	Min  *float64
+	Max  *float64
+	Enum []string
+}
+
+// parseRange 兼容旧版格式：{"min":1,"max":10} / 枚举 ["a","b"] 或 {"enum":[...]} / 逗号分隔
+func parseRange(raw string, dataType int8) fieldRange {
+	var r fieldRange
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return r
+	}
+	if dataType == DataTypeEnum {
+		var arr []interface{}
+		if json.Unmarshal([]byte(raw), &arr) != nil {
+			var obj map[string][]interface{}
+			if json.Unmarshal([]byte(raw), &obj) == nil {
+				arr = obj["enum"]
+			} else {
+				for _, v := range strings.Split(raw, ",") {
+					arr = append(arr, strings.TrimSpace(v))
+				}
+			}
+		}
+		for _, v := range arr {
+			r.Enum = append(r.Enum, fmt.Sprint(v))
+		}
+		return r
+	}
+	var obj map[string]interface{}
+	if json.Unmarshal([]byte(raw), &obj) != nil {
+		return r
+	}
+	if v, ok := toFloat(obj["min"]); ok {
+		r.Min = &v
+	}
+	if v, ok := toFloat(obj["max"]); ok {
+		r.Max = &v
+	}
+	return r
+}
+
+// ValidateFields 按请求字段定义校验 payload，缺省字段补齐 default。
+// 字段按路径还原层级后逐层校验：对象校验属性、数组逐个校验元素，错误字段以路径表示（如 user.name、list[0].id）；
+// 以字符串形式传入的对象 / 数组（query、form 参数）仅校验自身类型，不展开子字段。
+func ValidateFields(defs []model.AdminField, payload map[string]interface{}) *ValidateResult {
+	errs := []FieldError{}
+	out := validateObject(BuildFieldTree(defs), payload, "", &errs)
+	return &ValidateResult{Valid: len(errs) == 0, Errors: errs, Payload: out}
+}
+
+// validateObject 校验对象属性，返回补齐默认值后的副本（不修改入参）
+func validateObject(nodes []*FieldNode, obj map[string]interface{}, prefix string, errs *[]FieldError) map[string]interface{} {
+	out := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		out[k] = v
+	}
+	for _, n := range nodes {
+		name := prefix + n.FieldName
+		v, ok := out[n.FieldName]
+		if !ok || v == nil || v == "" {
+			if n.Implicit {
+				continue
+			}
+			if n.Default != "" {
+				out[n.FieldName] = defaultValue(n.AdminField)
+				continue
+			}
+			if n.IsMust == 1 {
+				*errs = append(*errs, FieldError{Field: name, Rule: "require", Msg: name + " 为必填参数"})
+			}
+			continue
+		}
+		out[n.FieldName] = validateNode(n, name, v, errs)
+	}
+	return out
+}
+
+// validateNode 校验单个取值，类型正确时继续校验子结构
+func validateNode(n *FieldNode, name string, v interface{}, errs *[]FieldError) interface{} {
+	if !n.Implicit {
+		if e := checkField(n.AdminField, name, v); e != nil {
+			*errs = append(*errs, *e)
+			return v
+		}
+	}
+	item, props := n.split()
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(props) > 0 {
+			return validateObject(props, t, name+".", errs)
+		}
+	case []interface{}:
+		if item != nil {
+			arr := make([]interface{}, len(t))
+			for i, e := range t {
+				arr[i] = e
+				if e != nil {
+					arr[i] = validateNode(item, fmt.Sprintf("%s[%d]", name, i), e, errs)
+				}
+			}
+			return arr
+		}
+	}
+	return v
+}
+
+// defaultValue 按数据类型转换 default，转换失败保留原字符串
+func defaultValue(f model.AdminField) interface{} {
+	switch f.DataType {
+	case DataTypeInteger, DataTypeFloat:
+		if n, ok := toFloat(f.Default); ok {
+			return n
+		}
+	case DataTypeBoolean:
+		if b, ok := toBool(f.Default); ok {
+			return b
+		}
+	case DataTypeArray:
+		if arr, ok := toSlice(f.Default); ok {
+			return arr
+		}
+	case DataTypeObject:
+		if m, ok := toObject(f.Default); ok {
+			return m
+		}
+	}
+	return f.Default
+}
+
+// checkField 校验单个取值的类型与 range 约束，name 为错误中展示的字段路径
+func checkField(f model.AdminField, name string, v interface{}) *FieldError {
+	typeErr := func() *FieldError {
+		return &FieldError{Field: name, Rule: "type", Msg: name + " 类型应为 " + dataTypeMap[int(f.DataType)]}
+	}
+	rg := parseRange(f.Range, f.DataType)
+	switch f.DataType {
+	case DataTypeInteger, DataTypeFloat:
+		n, ok := toFloat(v)
+		if !ok || (f.DataType == DataTypeInteger && n != float64(int64(n))) {
+			return typeErr()
+		}
+		return checkBounds(name, n, rg, "")
+	case DataTypeString:
+		s, ok := v.(string)
+		if !ok {
+			return typeErr()
+		}
+		return checkBounds(name, float64(len([]rune(s))), rg, "长度")
+	case DataTypeArray:
+		arr, ok := toSlice(v)
+		if !ok {
+			return typeErr()
+		}
+		return checkBounds(name, float64(len(arr)), rg, "元素个数")
+	case DataTypeBoolean:
+		if _, ok := toBool(v); !ok {
+			return typeErr()
+		}
+	case DataTypeEnum:
+		if len(rg.Enum) == 0 {
+			return nil
+		}
+		s := fmt.Sprint(v)
+		for _, e := range rg.Enum {
+			if e == s {
+				return nil
+			}
+		}
+		return &FieldError{Field: name, Rule: "enum", Msg: name + " 取值应为 " + strings.Join(rg.Enum, ",") + " 之一"}
+	case DataTypeMobile:
+		if s, ok := v.(string); !ok || !mobileRe.MatchString(s) {
+			return typeErr()
+		}
+	case DataTypeObject:
+		if _, ok := toObject(v); !ok {
+			return typeErr()
+		}
+	}
+	return nil
+}
+
+func checkBounds(field string, n float64, rg fieldRange, unit string) *FieldError {
+	if rg.Min != nil && n < *rg.Min {
+		return &FieldError{Field: field, Rule: "min", Msg: field + " " + unit + "不能小于 " + strconv.FormatFloat(*rg.Min, 'f', -1, 64)}
+	}
+	if rg.Max != nil && n > *rg.Max {
+		return &FieldError{Field: field, Rule: "max", Msg: field + " " + unit + "不能大于 " + strconv.FormatFloat(*rg.Max, 'f', -1, 64)}
+	}
+	return nil
+}
+
+// ===== 类型宽松转换：query/form 参数均为字符串，需兼容数字/布尔/JSON 字符串 =====
+func toFloat(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toBool(v interface{}) (bool, bool) {
+	switch t := v.(type) {
+	case bool:
+		return t, true
+	case float64:
+		return t != 0, t == 0 || t == 1
+	case json.Number: // 网关按 UseNumber 解码 JSON 请求体
+		f, err := t.Float64()
+		return f != 0, err == nil && (f == 0 || f == 1)
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(t))
+		return b, err == nil
+	}
+	return false, false
+}
+
+func toSlice(v interface{}) ([]interface{}, bool) {
+	switch t := v.(type) {
+	case []interface{}:
+		return t, true
+	case []string:
+		out := make([]interface{}, 0, len(t))
+		for _, s := range t {
+			out = append(out, s)
+		}
+		return out, true
+	case string:
+		var arr []interface{}
+		return arr, json.Unmarshal([]byte(t), &arr) == nil
+	}
+	return nil, false
+}
+
+func toObject(v interface{}) (map[string]interface{}, bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return t, true
+	case string:
+		var m map[string]interface{}
+		return m, json.Unmarshal([]byte(t), &m) == nil && m != nil
+	}
+	return nil, false
+}
+
+// RequestFields 读取接口请求字段定义（type=0，带缓存）
+func (s *FieldsService) RequestFields(ctx context.Context, hash string) ([]model.AdminField, error) {
+	return s.typedFields(ctx, hash, 0)
+}
+
+// ResponseFields 读取接口响应字段定义（type=1，带缓存）
+func (s *FieldsService) ResponseFields(ctx context.Context, hash string) ([]model.AdminField, error) {
+	return s.typedFields(ctx, hash, 1)
+}
+
+func (s *FieldsService) typedFields(ctx context.Context, hash string, typ int8) ([]model.AdminField, error) {
+	ck := s.typedFieldsKey(hash, typ)
+	if s.Cache != nil {
+		if str, _ := s.Cache.Get(ctx, ck); str != "" {
+			var defs []model.AdminField
+			if json.Unmarshal([]byte(str), &defs) == nil {
+				return defs, nil
+			}
+		}
+	}
+	defs, err := s.DAO.ListByHashAndType(ctx, hash, typ)
+	if err != nil {
+		return nil, err
+	}
+	if s.Cache != nil {
+		b, _ := json.Marshal(defs)
+		_ = s.Cache.SetEX(ctx, ck, string(b), 60*time.Second)
+	}
+	return defs, nil
+}
+
+// Validate 使用接口请求字段定义校验 payload，供网关与后台“样例校验”复用
+func (s *FieldsService) Validate(ctx context.Context, hash string, payload map[string]interface{}) (*ValidateResult, error) {
+	if strings.TrimSpace(hash) == "" {
+		return nil, errors.New("hash required")
+	}
+	defs, err := s.RequestFields(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateFields(defs, payload), nil
+}
+
+// typedFieldsKey type=0 请求字段 fields:req:{hash}，type=1 响应字段 fields:resp:{hash}
+func (s *FieldsService) typedFieldsKey(hash string, typ int8) string {
+	if typ == 1 {
+		return "fields:resp:" + hash
+	}
+	return "fields:req:" + hash
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -12,12 +13,17 @@ import (
 	"go-apiadmin/internal/metrics"
 	"go-apiadmin/internal/pkg/cache"
 	"go-apiadmin/internal/repository/dao"
+
+	"gorm.io/gorm"
 )
 
 type FieldsService struct {
 	DAO          *dao.AdminFieldsDAO
 	InterfaceDAO *dao.AdminInterfaceListDAO
-	Cache        cache.Cache // key: hash:type:page:limit -> json(ListFieldsResult)
+	Cache        cache.Cache      // key: hash:type:page:limit -> json(ListFieldsResult)
+	Search       *WikiSearchIndex // 可选：字段变更时同步 wiki 搜索索引
+	Revisions    *RevisionService // 可选：字段变更时保存接口修订快照
+	Contracts    *ContractService // 可选：字段变更时检查破坏性变更并记录契约事件
 }
 
 type ListFieldsParams struct {
@@ -29,6 +35,7 @@ type ListFieldsParams struct {
 type FieldDTO struct {
 	ID        int64  `json:"id"`
 	FieldName string `json:"field_name"`
+	Path      string `json:"path"`
 	Hash      string `json:"hash"`
 	DataType  int8   `json:"data_type"`
 	Default   string `json:"default"`
@@ -89,10 +96,10 @@ func (s *FieldsService) List(ctx context.Context, p ListFieldsParams) (*ListFiel
 	}
 	res := make([]FieldDTO, 0, len(list))
 	for _, m := range list {
-		res = append(res, FieldDTO{ID: m.ID, FieldName: m.FieldName, Hash: m.Hash, DataType: m.DataType, Default: m.Default, IsMust: m.IsMust, Range: m.Range, Info: m.Info, Type: m.Type, ShowName: m.ShowName})
+		res = append(res, FieldDTO{ID: m.ID, FieldName: m.FieldName, Path: FieldPath(m), Hash: m.Hash, DataType: m.DataType, Default: m.Default, IsMust: m.IsMust, Range: m.Range, Info: m.Info, Type: m.Type, ShowName: m.ShowName})
 	}
 	var apiInfo interface{}
-	if ifc, _ := s.InterfaceDAO.FindByHash(ctx, p.Hash); ifc != nil {
+	if ifc, _ := s.InterfaceDAO.FindByHash(ctx, p.Hash); ifc != nil && p.Type == 1 { // 仅响应样例写入 return_str（网关 mock 使用）
 		_ = json.Unmarshal([]byte(ifc.ReturnStr), &apiInfo)
 	}
 	result := &ListFieldsResult{List: res, Count: total, DataType: dataTypeMap, ApiInfo: apiInfo}
@@ -103,26 +110,55 @@ func (s *FieldsService) List(ctx context.Context, p ListFieldsParams) (*ListFiel
 	return result, nil
 }
 
+// AddFieldParams 新增字段；层级由 ParentID（父字段）或 Path（完整路径）指定，均为空时为顶层字段
 type AddFieldParams struct {
 	FieldName, Hash, Default, Range, Info, ShowName string
+	Path                                            string
+	ParentID                                        int64
 	DataType, IsMust, Type                          int8
+	ConfirmBreaking                                 bool // 确认提交破坏性变更
 }
 
+// EditFieldParams 编辑字段；ParentID 为 0 表示移到顶层。路径变化时子字段随之改名
 type EditFieldParams struct {
 	ID                                              int64
 	FieldName, Hash, Default, Range, Info, ShowName *string
+	Path                                            *string
+	ParentID                                        *int64
 	DataType, IsMust, Type                          *int8
+	ConfirmBreaking                                 bool
 }
 
 func (s *FieldsService) Add(ctx context.Context, p AddFieldParams) (int64, error) {
+	if p.FieldName == "" && p.ParentID <= 0 {
+		p.FieldName = pathLeaf(p.Path)
+	}
 	if p.FieldName == "" || p.Hash == "" {
 		return 0, errors.New("field_name & hash required")
 	}
-	m := &model.AdminField{FieldName: p.FieldName, Hash: p.Hash, DataType: p.DataType, Default: p.Default, IsMust: p.IsMust, Range: p.Range, Info: p.Info, Type: p.Type, ShowName: pickShowName(p.ShowName, p.FieldName)}
+	path, err := s.resolvePath(ctx, p.Hash, p.Type, p.FieldName, p.Path, p.ParentID)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.pathTaken(ctx, p.Hash, p.Type, []string{path}); err != nil {
+		return 0, err
+	}
+	m := &model.AdminField{FieldName: p.FieldName, Path: path, Hash: p.Hash, DataType: p.DataType, Default: p.Default, IsMust: p.IsMust, Range: p.Range, Info: p.Info, Type: p.Type, ShowName: pickShowName(p.ShowName, p.FieldName)}
+	check, err := s.Contracts.Check(ctx, p.Hash, ContractSourceFieldAdd, p.ConfirmBreaking, func(next *InterfaceSnapshot) {
+		list := next.Fields(m.Type)
+		*list = append(*list, *m)
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.Revisions.Baseline(ctx, p.Hash)
 	if err := s.DAO.Create(ctx, m); err != nil {
 		return 0, err
 	}
 	s.invalidateHash(p.Hash)
+	s.Search.Touch(ctx, p.Hash)
+	s.Revisions.Record(ctx, p.Hash, RevisionFields)
+	s.Contracts.Emit(ctx, check)
 	return m.ID, nil
 }
 
@@ -137,8 +173,11 @@ func (s *FieldsService) Edit(ctx context.Context, p EditFieldParams) error {
 	if m == nil {
 		return errors.New("not found")
 	}
+	oldHash, oldType, oldPath := m.Hash, m.Type, FieldPath(*m)
 	if p.FieldName != nil {
 		m.FieldName = *p.FieldName
+	} else if p.Path != nil && p.ParentID == nil {
+		m.FieldName = pathLeaf(*p.Path)
 	}
 	if p.Hash != nil {
 		m.Hash = *p.Hash
@@ -164,32 +203,225 @@ func (s *FieldsService) Edit(ctx context.Context, p EditFieldParams) error {
 	if p.Type != nil {
 		m.Type = *p.Type
 	}
-	if err := s.DAO.Update(ctx, m); err != nil {
+	if m.FieldName == "" {
+		return errors.New("field_name required")
+	}
+	switch {
+	case p.ParentID != nil:
+		m.Path, err = s.resolvePath(ctx, m.Hash, m.Type, m.FieldName, "", *p.ParentID)
+	case p.Path != nil:
+		m.Path, err = s.resolvePath(ctx, m.Hash, m.Type, m.FieldName, *p.Path, 0)
+	default:
+		m.Path = renameLeaf(oldPath, m.FieldName)
+	}
+	if err != nil {
 		return err
 	}
-	s.invalidateHash(m.Hash)
-	if p.Hash != nil {
-		s.invalidateHash(*p.Hash)
+	// 路径或归属变化时子字段随之移动
+	var moved []model.AdminField
+	if m.Path != oldPath || m.Hash != oldHash || m.Type != oldType {
+		if underPath(m.Path, oldPath) {
+			return errors.New("cannot move field under itself")
+		}
+		siblings, err := s.DAO.ListByHashAndType(ctx, oldHash, oldType)
+		if err != nil {
+			return err
+		}
+		for _, d := range fieldSubtree(siblings, oldPath) {
+			d.Path = m.Path + strings.TrimPrefix(FieldPath(d), oldPath)
+			if len(d.Path) > fieldPathMax {
+				return errors.New("field path too long")
+			}
+			d.Hash, d.Type = m.Hash, m.Type
+			moved = append(moved, d)
+		}
+	}
+	ids := []int64{m.ID}
+	for _, d := range moved {
+		ids = append(ids, d.ID)
+	}
+	// 根字段与每个子字段的新路径都须在目标接口 / 类型下唯一（整棵子树自身除外）
+	if m.Path != oldPath || m.Hash != oldHash || m.Type != oldType {
+		paths := []string{m.Path}
+		for _, d := range moved {
+			paths = append(paths, d.Path)
+		}
+		if err := s.pathTaken(ctx, m.Hash, m.Type, paths, ids...); err != nil {
+			return err
+		}
+	}
+	place := func(next *InterfaceSnapshot) {
+		list := next.Fields(m.Type)
+		*list = append(*list, *m)
+		*list = append(*list, moved...)
+	}
+	// 字段移到其他接口时，原接口按删除、新接口按新增分别检查
+	checks := make([]*ContractCheck, 0, 2)
+	check, err := s.Contracts.Check(ctx, oldHash, ContractSourceFieldEdit, p.ConfirmBreaking, func(next *InterfaceSnapshot) {
+		next.removeField(ids...)
+		if m.Hash == oldHash {
+			place(next)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	checks = append(checks, check)
+	if m.Hash != oldHash {
+		check, err := s.Contracts.Check(ctx, m.Hash, ContractSourceFieldEdit, p.ConfirmBreaking, place)
+		if err != nil {
+			return err
+		}
+		checks = append(checks, check)
+	}
+	s.Revisions.Baseline(ctx, oldHash)
+	if m.Hash != oldHash {
+		s.Revisions.Baseline(ctx, m.Hash)
+	}
+	// 根字段与子字段同一事务移动，避免子树一半在新路径一半在旧路径
+	err = s.DAO.DB.Transaction(func(tx *gorm.DB) error {
+		fields := s.DAO.WithTx(tx)
+		if err := fields.Update(ctx, m); err != nil {
+			return err
+		}
+		for i := range moved {
+			if err := fields.Update(ctx, &moved[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidateHash(oldHash)
+	if m.Hash != oldHash {
+		s.invalidateHash(m.Hash)
+	}
+	s.Search.Touch(ctx, oldHash, m.Hash)
+	s.Revisions.Record(ctx, oldHash, RevisionFields)
+	if m.Hash != oldHash {
+		s.Revisions.Record(ctx, m.Hash, RevisionFields)
+	}
+	for _, c := range checks {
+		s.Contracts.Emit(ctx, c)
 	}
 	return nil
 }
 
-func (s *FieldsService) Delete(ctx context.Context, id int64) error {
+// Delete 删除字段及其子字段；confirmBreaking 确认提交破坏性变更（如删除响应字段）
+func (s *FieldsService) Delete(ctx context.Context, id int64, confirmBreaking bool) error {
 	if id <= 0 {
 		return errors.New("invalid id")
 	}
-	m, _ := s.DAO.FindByID(ctx, id)
-	err := s.DAO.Delete(ctx, id)
+	m, err := s.DAO.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	var check *ContractCheck
+	var children []int64
+	if m != nil {
+		siblings, err := s.DAO.ListByHashAndType(ctx, m.Hash, m.Type)
+		if err != nil {
+			return err
+		}
+		for _, d := range fieldSubtree(siblings, FieldPath(*m)) {
+			children = append(children, d.ID)
+		}
+		if check, err = s.Contracts.Check(ctx, m.Hash, ContractSourceFieldDelete, confirmBreaking, func(next *InterfaceSnapshot) {
+			next.removeField(append([]int64{m.ID}, children...)...)
+		}); err != nil {
+			return err
+		}
+		s.Revisions.Baseline(ctx, m.Hash)
+	}
+	// 字段与子字段同一事务删除，避免留下挂在已删除路径下的子字段
+	err = s.DAO.DB.Transaction(func(tx *gorm.DB) error {
+		fields := s.DAO.WithTx(tx)
+		if err := fields.Delete(ctx, id); err != nil {
+			return err
+		}
+		return fields.DeleteByIDs(ctx, children)
+	})
+	if err != nil {
+		return err
+	}
 	if m != nil {
 		s.invalidateHash(m.Hash)
+		s.Search.Touch(ctx, m.Hash)
+		s.Revisions.Record(ctx, m.Hash, RevisionFields)
+		s.Contracts.Emit(ctx, check)
+	}
+	return nil
+}
+
+// resolvePath 计算字段路径：指定父字段时挂在父字段下（数组父字段挂在元素下），否则取 path，均为空时为顶层字段
+func (s *FieldsService) resolvePath(ctx context.Context, hash string, typ int8, name, path string, parentID int64) (string, error) {
+	switch {
+	case parentID > 0:
+		parent, err := s.DAO.FindByID(ctx, parentID)
+		if err != nil {
+			return "", err
+		}
+		if parent == nil || parent.Hash != hash || parent.Type != typ {
+			return "", errors.New("parent field not found")
+		}
+		path = NormalizeFieldPath(ChildFieldPath(*parent, name))
+	case strings.TrimSpace(path) != "":
+		path = NormalizeFieldPath(path)
+	default:
+		path = NormalizeFieldPath(name)
+	}
+	if path == "" {
+		return "", errors.New("invalid field path")
 	}
-	return err
+	if len(path) > fieldPathMax {
+		return "", errors.New("field path too long")
+	}
+	return path, nil
+}
+
+// pathTaken 同一接口同类字段下路径不可重复（exclude 为编辑中的字段及其子字段）
+func (s *FieldsService) pathTaken(ctx context.Context, hash string, typ int8, paths []string, exclude ...int64) error {
+	list, err := s.DAO.ListByHashAndType(ctx, hash, typ)
+	if err != nil {
+		return err
+	}
+	skip := make(map[int64]bool, len(exclude))
+	for _, id := range exclude {
+		skip[id] = true
+	}
+	taken := make(map[string]bool, len(list))
+	for _, f := range list {
+		if !skip[f.ID] {
+			taken[FieldPath(f)] = true
+		}
+	}
+	for _, path := range paths {
+		if taken[path] {
+			return errors.New("field path already exists: " + path)
+		}
+	}
+	return nil
+}
+
+// Tree 按路径还原的字段树（type 0 请求 / 1 响应）
+func (s *FieldsService) Tree(ctx context.Context, hash string, typ int8) ([]*FieldNode, error) {
+	if strings.TrimSpace(hash) == "" {
+		return nil, errors.New("hash required")
+	}
+	defs, err := s.typedFields(ctx, hash, typ)
+	if err != nil {
+		return nil, err
+	}
+	return BuildFieldTree(defs), nil
 }
 
 type BatchUploadParams struct {
-	Hash string
-	Type int8
-	JSON string
+	Hash            string
+	Type            int8
+	JSON            string
+	ConfirmBreaking bool
 }
 
 func (s *FieldsService) BatchUpload(ctx context.Context, p BatchUploadParams) error {
@@ -200,23 +432,85 @@ func (s *FieldsService) BatchUpload(ctx context.Context, p BatchUploadParams) er
 	if err := json.Unmarshal([]byte(p.JSON), &parsed); err != nil {
 		return err
 	}
-	if ifc, _ := s.InterfaceDAO.FindByHash(ctx, p.Hash); ifc != nil {
-		ifc.ReturnStr = p.JSON
-		_ = s.InterfaceDAO.Update(ctx, &model.AdminInterfaceList{ID: ifc.ID, ReturnStr: p.JSON})
-	}
-	dataNode, ok := parsed["data"]
-	if !ok {
-		dataNode = parsed
-	}
+	// 仅响应样例且含 data 键时以 data 为根；请求样例或无 data 的响应按顶层字段还原，不凭空包一层 data
 	var collect []model.AdminField
-	buildFieldsRecursive(&collect, p.Hash, p.Type, "data", dataNode)
-	if err := s.DAO.DeleteByHash(ctx, p.Hash); err != nil {
+	if dataNode, ok := parsed["data"]; ok && p.Type == 1 {
+		buildFieldsRecursive(&collect, p.Hash, p.Type, "data", "data", dataNode)
+	} else {
+		keys := make([]string, 0, len(parsed))
+		for k := range parsed {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			buildFieldsRecursive(&collect, p.Hash, p.Type, k, k, parsed[k])
+		}
+	}
+	// 与下方写入一致：清空该接口全部字段后写入解析结果，并以上传内容作为返回示例
+	check, err := s.Contracts.Check(ctx, p.Hash, ContractSourceBatchUpload, p.ConfirmBreaking, func(next *InterfaceSnapshot) {
+		next.Request, next.Response = []model.AdminField{}, []model.AdminField{}
+		list := next.Fields(p.Type)
+		*list = append(*list, collect...)
+		if next.Interface != nil {
+			next.Interface.ReturnStr = p.JSON
+		}
+	})
+	if err != nil {
 		return err
 	}
-	for i := range collect {
-		_ = s.DAO.Create(ctx, &collect[i])
+	s.Revisions.Baseline(ctx, p.Hash)
+	// 返回示例与字段重建同一事务，避免中途失败留下半截字段
+	err = s.DAO.DB.Transaction(func(tx *gorm.DB) error {
+		if ifc, _ := s.InterfaceDAO.WithTx(tx).FindByHash(ctx, p.Hash); ifc != nil {
+			if err := s.InterfaceDAO.WithTx(tx).Update(ctx, &model.AdminInterfaceList{ID: ifc.ID, ReturnStr: p.JSON}); err != nil {
+				return err
+			}
+		}
+		fields := s.DAO.WithTx(tx)
+		if err := fields.DeleteByHash(ctx, p.Hash); err != nil {
+			return err
+		}
+		for i := range collect {
+			if err := fields.Create(ctx, &collect[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 	s.invalidateHash(p.Hash)
+	s.Search.Touch(ctx, p.Hash)
+	s.Revisions.Record(ctx, p.Hash, RevisionFields)
+	s.Contracts.Emit(ctx, check)
+	return nil
+}
+
+// replace 以给定列表重建接口的请求 / 响应字段（单独事务）
+func (s *FieldsService) replace(ctx context.Context, hash string, req, resp []model.AdminField) error {
+	if err := s.DAO.DB.Transaction(func(tx *gorm.DB) error { return s.replaceTx(ctx, tx, hash, req, resp) }); err != nil {
+		return err
+	}
+	s.invalidateHash(hash)
+	return nil
+}
+
+// replaceTx 在调用方事务内删除并重建字段（导入、回滚与接口列更新放在同一事务），缓存由调用方在提交后失效
+func (s *FieldsService) replaceTx(ctx context.Context, tx *gorm.DB, hash string, req, resp []model.AdminField) error {
+	fields := s.DAO.WithTx(tx)
+	for typ, list := range [][]model.AdminField{req, resp} {
+		if err := fields.DeleteByHashAndType(ctx, hash, int8(typ)); err != nil {
+			return err
+		}
+		for i := range list {
+			f := list[i]
+			f.ID, f.Hash, f.Type = 0, hash, int8(typ)
+			if err := fields.Create(ctx, &f); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -230,7 +524,8 @@ func (s *FieldsService) invalidateHash(hash string) {
 	if hash == "" || s.Cache == nil {
 		return
 	}
-	// 简化: 由于接口未提供 Keys 遍历能力, 直接忽略精细失效; 生产可维护二级索引
+	// 简化: 由于接口未提供 Keys 遍历能力, 列表缓存不做精细失效; 生产可维护二级索引
+	_ = s.Cache.Del(context.Background(), s.typedFieldsKey(hash, 0), s.typedFieldsKey(hash, 1))
 }
 
 func pickShowName(show, field string) string {
@@ -240,20 +535,26 @@ func pickShowName(show, field string) string {
 	return show
 }
 
-func buildFieldsRecursive(out *[]model.AdminField, hash string, typ int8, key string, val interface{}) {
-	field := model.AdminField{FieldName: key, ShowName: key, Hash: hash, IsMust: 1, Type: typ, DataType: 2}
+// buildFieldsRecursive 由样例 JSON 生成字段：FieldName 为末段属性名，Path 为完整路径（对象属性 a.b、数组元素 a[]）
+func buildFieldsRecursive(out *[]model.AdminField, hash string, typ int8, key, path string, val interface{}) {
+	field := model.AdminField{FieldName: key, Path: path, ShowName: key, Hash: hash, IsMust: 1, Type: typ, DataType: 2}
 	switch v := val.(type) {
 	case map[string]interface{}:
 		field.DataType = 9
 		*out = append(*out, field)
-		for k, child := range v {
-			buildFieldsRecursive(out, hash, typ, k, child)
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			buildFieldsRecursive(out, hash, typ, k, path+"."+k, v[k])
 		}
 	case []interface{}:
 		field.DataType = 3
 		*out = append(*out, field)
 		if len(v) > 0 {
-			buildFieldsRecursive(out, hash, typ, key, v[0])
+			buildFieldsRecursive(out, hash, typ, key, path+fieldItemToken, v[0])
 		}
 	case float64:
 		if float64(int64(v)) == v {
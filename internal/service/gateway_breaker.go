@@ -0,0 +1,182 @@
+package service
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go-apiadmin/internal/metrics"
+)
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// BreakerConfig 熔断参数：连续失败 FailureThreshold 次打开，OpenTimeout 后进入半开，
+// 半开期间最多放行 HalfOpenMax 个探测请求，全部成功后关闭，任一失败重新打开。
+type BreakerConfig struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	HalfOpenMax      int
+}
+
+// CircuitBreaker 单个后端（base URL）的熔断器
+type CircuitBreaker struct {
+	name string
+	cfg  BreakerConfig
+
+	mu        sync.Mutex
+	state     string
+	failures  int // closed: 连续失败数
+	probes    int // half_open: 已放行探测数
+	successes int // half_open: 探测成功数
+	openedAt  time.Time
+}
+
+// BreakerStatus 熔断器快照（/readyz 与后台展示）
+type BreakerStatus struct {
+	Upstream string `json:"upstream"`
+	State    string `json:"state"`
+	Failures int    `json:"failures"`
+	OpenedAt string `json:"opened_at,omitempty"`
+}
+
+// Allow 判断是否放行；open 超时后转 half_open 并放行有限探测
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cfg.OpenTimeout {
+			return false
+		}
+		b.setState(BreakerHalfOpen)
+		b.probes, b.successes = 0, 0
+		fallthrough
+	case BreakerHalfOpen:
+		if b.probes >= b.cfg.HalfOpenMax {
+			return false
+		}
+		b.probes++
+	}
+	return true
+}
+
+// Record 记录一次调用结果（网络错误 / 5xx 视为失败）
+func (b *CircuitBreaker) Record(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerHalfOpen:
+		if !ok {
+			b.trip()
+			return
+		}
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenMax {
+			b.failures = 0
+			b.setState(BreakerClosed)
+		}
+	case BreakerClosed:
+		if ok {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.trip()
+		}
+	}
+}
+
+func (b *CircuitBreaker) trip() {
+	b.openedAt = time.Now()
+	b.setState(BreakerOpen)
+}
+
+func (b *CircuitBreaker) setState(st string) {
+	if b.state == st {
+		return
+	}
+	b.state = st
+	v := 0.0
+	switch st {
+	case BreakerHalfOpen:
+		v = 1
+	case BreakerOpen:
+		v = 2
+	}
+	metrics.GatewayBreakerState.WithLabelValues(b.name).Set(v)
+}
+
+// Cancel 放弃一次已放行但未完成的调用（调用方取消），归还半开探测名额
+func (b *CircuitBreaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+// Status 当前状态快照
+func (b *CircuitBreaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st := BreakerStatus{Upstream: b.name, State: b.state, Failures: b.failures}
+	if b.state != BreakerClosed {
+		st.OpenedAt = b.openedAt.Format(time.RFC3339)
+	}
+	return st
+}
+
+// BreakerGroup 按后端 base URL 懒创建熔断器
+type BreakerGroup struct {
+	cfg BreakerConfig
+	mu  sync.Mutex
+	m   map[string]*CircuitBreaker
+}
+
+func NewBreakerGroup(cfg BreakerConfig) *BreakerGroup {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenMax <= 0 {
+		cfg.HalfOpenMax = 1
+	}
+	return &BreakerGroup{cfg: cfg, m: map[string]*CircuitBreaker{}}
+}
+
+// Get 获取（必要时创建）后端熔断器
+func (g *BreakerGroup) Get(upstream string) *CircuitBreaker {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.m[upstream]
+	if !ok {
+		b = &CircuitBreaker{name: upstream, cfg: g.cfg}
+		b.setState(BreakerClosed)
+		g.m[upstream] = b
+	}
+	return b
+}
+
+// Snapshot 全部熔断器状态，按后端排序
+func (g *BreakerGroup) Snapshot() []BreakerStatus {
+	g.mu.Lock()
+	list := make([]*CircuitBreaker, 0, len(g.m))
+	for _, b := range g.m {
+		list = append(list, b)
+	}
+	g.mu.Unlock()
+	out := make([]BreakerStatus, 0, len(list))
+	for _, b := range list {
+		out = append(out, b.Status())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Upstream < out[j].Upstream })
+	return out
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/metrics"
+	"go-apiadmin/internal/pkg/cache"
+	redisrepo "go-apiadmin/internal/repository/redis"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 响应缓存 TTL 上限，清除依赖版本号递增，旧版本条目最长存活该时长
+const maxResponseCacheTTL = 24 * time.Hour
+
+// GatewayCache 网关接口级响应缓存：条目存于 LayeredCache，key 含接口缓存版本号；
+// 版本号保存在 Redis（多实例共享），清除时递增版本使旧条目失效，无需按前缀扫描。
+type GatewayCache struct {
+	Cache cache.Cache
+	Redis *redisrepo.Client
+}
+
+func NewGatewayCache(c cache.Cache, r *redisrepo.Client) *GatewayCache {
+	return &GatewayCache{Cache: c, Redis: r}
+}
+
+// CacheRequest 参与缓存 key 计算的请求要素
+type CacheRequest struct {
+	Method string
+	Query  url.Values
+	Header http.Header
+	AppID  string
+}
+
+// Enabled 接口开启缓存（cache_ttl>0）且为 GET/HEAD 请求
+func (g *GatewayCache) Enabled(api *model.AdminInterfaceList, method string) bool {
+	if g == nil || g.Cache == nil || api.CacheTTL <= 0 {
+		return false
+	}
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// splitList 逗号分隔列表，去空白与空项
+func splitList(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Key 生成缓存 key：gwresp:{hash}:{ver}:{sha1(要素)}。
+// 调用应用（令牌 / 签名识别出的 gw_app）始终参与，避免不同应用共用响应；
+// cache_keys 用于收窄参与的 query 参数名（* 表示全部），未列出任何参数时全部 query 参与；
+// 特殊项 app 保留兼容（应用已始终参与）。cache_vary 为参与的请求头名。
+func (g *GatewayCache) Key(ctx context.Context, api *model.AdminInterfaceList, r CacheRequest) (string, error) {
+	ver, err := g.version(ctx, api.Hash)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(r.Method)
+	b.WriteString("\napp=" + r.AppID)
+	names, star := []string{}, false
+	for _, k := range splitList(api.CacheKeys) {
+		switch {
+		case strings.EqualFold(k, "app"):
+		case k == "*":
+			star = true
+		default:
+			names = append(names, k)
+		}
+	}
+	if star || len(names) == 0 {
+		names = names[:0]
+		for q := range r.Query {
+			names = append(names, q)
+		}
+	}
+	sort.Strings(names)
+	prev := ""
+	for _, k := range names {
+		if k == prev {
+			continue
+		}
+		prev = k
+		vs := append([]string(nil), r.Query[k]...)
+		sort.Strings(vs)
+		b.WriteString("\nq:" + k + "=" + strings.Join(vs, ","))
+	}
+	vary := splitList(api.CacheVary)
+	sort.Slice(vary, func(i, j int) bool { return strings.ToLower(vary[i]) < strings.ToLower(vary[j]) })
+	for _, h := range vary {
+		b.WriteString("\nh:" + strings.ToLower(h) + "=" + strings.Join(r.Header.Values(h), ","))
+	}
+	sum := sha1.Sum([]byte(b.String()))
+	return "gwresp:" + api.Hash + ":" + ver + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+func (g *GatewayCache) versionKey(hash string) string { return "gwresp:ver:" + hash }
+
+func (g *GatewayCache) version(ctx context.Context, hash string) (string, error) {
+	if g.Redis == nil {
+		return "0", nil
+	}
+	v, err := g.Redis.Client.Get(ctx, g.versionKey(hash)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "0", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// Get 读取缓存响应
+func (g *GatewayCache) Get(ctx context.Context, key string) (*GatewayResponse, bool) {
+	raw, _ := g.Cache.Get(ctx, key)
+	if raw == "" {
+		metrics.GatewayCacheTotal.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+	var resp GatewayResponse
+	if json.Unmarshal([]byte(raw), &resp) != nil {
+		metrics.GatewayCacheTotal.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+	metrics.GatewayCacheTotal.WithLabelValues("hit").Inc()
+	return &resp, true
+}
+
+// Cacheable 仅缓存 2xx 且后端未声明 no-store/private、未下发 Set-Cookie 的响应
+func Cacheable(resp *GatewayResponse) bool {
+	if resp == nil || resp.Status < 200 || resp.Status >= 300 {
+		return false
+	}
+	if resp.Header.Get("Set-Cookie") != "" {
+		return false
+	}
+	cc := strings.ToLower(resp.Header.Get("Cache-Control"))
+	return !strings.Contains(cc, "no-store") && !strings.Contains(cc, "private")
+}
+
+// Set 写入缓存，TTL 取接口 cache_ttl（上限 24h）
+func (g *GatewayCache) Set(ctx context.Context, key string, api *model.AdminInterfaceList, resp *GatewayResponse) {
+	if !Cacheable(resp) {
+		return
+	}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	ttl := time.Duration(api.CacheTTL) * time.Second
+	if ttl > maxResponseCacheTTL {
+		ttl = maxResponseCacheTTL
+	}
+	_ = g.Cache.SetEX(ctx, key, string(b), ttl)
+	metrics.GatewayCacheTotal.WithLabelValues("store").Inc()
+}
+
+// Purge 清除接口全部缓存响应（递增版本号），返回新版本
+func (g *GatewayCache) Purge(ctx context.Context, hash string) (int64, error) {
+	if strings.TrimSpace(hash) == "" {
+		return 0, errors.New("hash required")
+	}
+	if g.Redis == nil {
+		return 0, errors.New("redis not configured")
+	}
+	v, err := g.Redis.Client.Incr(ctx, g.versionKey(hash)).Result()
+	if err != nil {
+		return 0, err
+	}
+	metrics.GatewayCacheTotal.WithLabelValues("purge").Inc()
+	return v, nil
+}
+
+// CacheTTLValid 校验接口缓存 TTL 配置
+func CacheTTLValid(ttl int) bool {
+	return ttl >= 0 && time.Duration(ttl)*time.Second <= maxResponseCacheTTL
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go-apiadmin/internal/discovery/etcd"
+)
+
+// 负载均衡策略
+const (
+	BalanceRoundRobin = "round_robin"
+	BalanceWeighted   = "weighted"
+)
+
+// DiscoveryUpstreams 基于 etcd 服务发现的后端解析：实例前缀 /services/{service}/{env}/（含全部版本），
+// 与 NewApp 注册的 key 结构一致；无可用实例时回退到 Fallback（通常为静态配置）。
+type DiscoveryUpstreams struct {
+	Watcher  *etcd.Watcher
+	Env      string
+	Balance  string
+	Fallback UpstreamResolver
+
+	rr sync.Map // service -> *uint64 轮询计数
+	mu sync.Mutex
+	sw map[string]map[string]int // service -> instance key -> 平滑加权当前值
+}
+
+func NewDiscoveryUpstreams(w *etcd.Watcher, env, balance string, fallback UpstreamResolver) *DiscoveryUpstreams {
+	return &DiscoveryUpstreams{Watcher: w, Env: env, Balance: balance, Fallback: fallback, sw: map[string]map[string]int{}}
+}
+
+// ServicePrefix 服务实例前缀
+func (d *DiscoveryUpstreams) ServicePrefix(service string) string {
+	p := "/services/" + strings.ToLower(service) + "/"
+	if d.Env != "" {
+		p += d.Env + "/"
+	}
+	return p
+}
+
+func (d *DiscoveryUpstreams) Resolve(ctx context.Context, service string) (string, error) {
+	list, err := d.Watcher.Instances(ctx, d.ServicePrefix(service))
+	if err != nil || len(list) == 0 {
+		if d.Fallback != nil {
+			return d.Fallback.Resolve(ctx, service)
+		}
+		if err != nil {
+			return "", err
+		}
+		return "", ErrGatewayNoUpstream
+	}
+	if d.Balance == BalanceWeighted {
+		return d.pickWeighted(service, list), nil
+	}
+	v, _ := d.rr.LoadOrStore(service, new(uint64))
+	n := atomic.AddUint64(v.(*uint64), 1)
+	return list[(n-1)%uint64(len(list))].Addr, nil
+}
+
+// pickWeighted 平滑加权轮询（nginx swrr）：每轮 current += weight，选最大者并减去总权重
+func (d *DiscoveryUpstreams) pickWeighted(service string, list []etcd.Instance) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cur := d.sw[service]
+	if cur == nil {
+		cur = map[string]int{}
+		d.sw[service] = cur
+	}
+	total, best := 0, -1
+	seen := make(map[string]struct{}, len(list))
+	for i, inst := range list {
+		seen[inst.Key] = struct{}{}
+		cur[inst.Key] += inst.Weight
+		total += inst.Weight
+		if best < 0 || cur[inst.Key] > cur[list[best].Key] {
+			best = i
+		}
+	}
+	for k := range cur { // 清理已下线实例
+		if _, ok := seen[k]; !ok {
+			delete(cur, k)
+		}
+	}
+	cur[list[best].Key] -= total
+	return list[best].Addr
+}
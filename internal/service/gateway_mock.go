@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"go-apiadmin/internal/domain/model"
+)
+
+// MockEnabled 接口 is_test=1 或请求携带 mock 头时返回模拟数据
+func MockEnabled(api *model.AdminInterfaceList, header string) bool {
+	if api.IsTest == 1 {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(header)) {
+	case "1", "true", "on", "yes":
+		return true
+	}
+	return false
+}
+
+// Mock 生成模拟响应：优先使用接口 return_str 样例（字段批量上传时写入），
+// 否则按响应字段定义（type=1）生成 {code, msg, data} 结构。
+func (s *GatewayService) Mock(ctx context.Context, api *model.AdminInterfaceList, fields *FieldsService) (*GatewayResponse, error) {
+	body := strings.TrimSpace(api.ReturnStr)
+	if body == "" || !json.Valid([]byte(body)) {
+		var defs []model.AdminField
+		if fields != nil {
+			var err error
+			if defs, err = fields.ResponseFields(ctx, api.Hash); err != nil {
+				return nil, err
+			}
+		}
+		b, err := json.Marshal(map[string]interface{}{"code": 1, "msg": "success", "data": MockData(defs)})
+		if err != nil {
+			return nil, err
+		}
+		body = string(b)
+	}
+	h := http.Header{}
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	h.Set("X-Api-Mock", "1")
+	return &GatewayResponse{Status: http.StatusOK, Header: h, Body: []byte(body)}, nil
+}
+
+// MockData 按字段定义生成示例对象；根字段 data（批量上传生成）本身不输出，其余字段按路径还原层级
+func MockData(defs []model.AdminField) map[string]interface{} {
+	return FieldsExample(ResponseDataFields(defs))
+}
+
+// MockValue 单字段示例值：有 default 时按类型转换，否则按类型与 range 约束生成贴近实际的样例
+// （数值落在取值范围内、字符串满足长度、枚举取首个候选、手机号符合号段格式）
+func MockValue(f model.AdminField) interface{} {
+	if f.Default != "" {
+		return defaultValue(f)
+	}
+	rg := parseRange(f.Range, f.DataType)
+	switch f.DataType {
+	case DataTypeInteger:
+		return int64(math.Ceil(mockNumber(rg, 1)))
+	case DataTypeFloat:
+		return mockNumber(rg, 1.5)
+	case DataTypeBoolean:
+		return true
+	case DataTypeArray:
+		return []interface{}{}
+	case DataTypeObject:
+		return map[string]interface{}{}
+	case DataTypeMobile:
+		return "13800138000"
+	case DataTypeEnum:
+		if len(rg.Enum) > 0 {
+			return rg.Enum[0]
+		}
+	case DataTypeFile:
+		return ""
+	}
+	return mockString(f, rg)
+}
+
+// mockNumber 缺省值夹在 [min, max] 内
+func mockNumber(rg fieldRange, def float64) float64 {
+	if rg.Min != nil && def < *rg.Min {
+		def = *rg.Min
+	}
+	if rg.Max != nil && def > *rg.Max {
+		def = *rg.Max
+	}
+	return def
+}
+
+// mockString 按字段名推断常见格式（id、时间、邮箱、链接等），其余取说明或字段名，再按长度约束截断 / 补齐
+func mockString(f model.AdminField, rg fieldRange) string {
+	name := strings.ToLower(f.FieldName)
+	var v string
+	switch {
+	case strings.Contains(name, "mail"):
+		v = "user@example.com"
+	case strings.Contains(name, "url") || strings.Contains(name, "link") || strings.Contains(name, "avatar") || strings.Contains(name, "image"):
+		v = "https://example.com/" + f.FieldName
+	case strings.HasSuffix(name, "date"):
+		v = "2025-08-01"
+	case strings.HasSuffix(name, "time") || strings.HasSuffix(name, "_at"):
+		v = "2025-08-01 12:00:00"
+	case name == "id" || strings.HasSuffix(name, "_id"):
+		v = "10001"
+	case name == "ip" || strings.HasSuffix(name, "_ip"):
+		v = "192.168.1.1"
+	default:
+		v = pickShowName(f.Info, f.FieldName)
+	}
+	if rg.Max != nil && *rg.Max >= 0 {
+		v = truncateRunes(v, int(*rg.Max))
+	}
+	if rg.Min != nil {
+		for n := utf8.RuneCountInString(v); float64(n) < *rg.Min && n < fieldTextMax; n++ {
+			v += "x"
+		}
+	}
+	return v
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/metrics"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// GatewayService 接口网关：按 hash / api_class 解析 admin_list 中的接口定义，校验状态与方法后转发至后端服务。
+// 路由规则与 RefreshRoutes 生成的 PHP 路由保持一致：hash_type=1 使用 api_class 作为路径，其余使用 hash。
+type GatewayService struct {
+	IfList   *InterfaceListService
+	Upstream UpstreamResolver
+	Client   *http.Client
+	MaxBody  int64                // 请求/响应体读取上限（字节）
+	Timeout  time.Duration        // 单次后端调用超时（接口 timeout_ms 为 0 时）
+	Retries  int                  // 幂等方法失败后的最大重试次数
+	Breakers *BreakerGroup        // 按后端 base URL 熔断，nil 表示不启用
+	Cache    *GatewayCache        // 接口级响应缓存，nil 表示不启用
+	Stats    *GatewayStatsService // 调用统计（按应用/接口分钟聚合）
+	// RejectAfterSunset 已废弃接口过了 sunset_at 后拒绝调用（retired 接口始终拒绝）
+	RejectAfterSunset bool
+}
+
+// UpstreamResolver 将服务名解析为后端 base URL（静态配置 / 服务发现等实现）
+type UpstreamResolver interface {
+	Resolve(ctx context.Context, service string) (string, error)
+}
+
+// StaticUpstreams 基于配置的静态后端映射；服务名统一小写（viper 读取 map 时 key 已小写）
+type StaticUpstreams struct {
+	Default string
+	Targets map[string]string
+}
+
+func (u *StaticUpstreams) Resolve(_ context.Context, service string) (string, error) {
+	if base, ok := u.Targets[strings.ToLower(service)]; ok && base != "" {
+		return base, nil
+	}
+	if u.Default != "" {
+		return u.Default, nil
+	}
+	return "", ErrGatewayNoUpstream
+}
+
+// GatewayRequest 转发请求（与 gin 解耦，handler 负责组装）
+type GatewayRequest struct {
+	Method   string
+	RawQuery string
+	Header   http.Header
+	Body     []byte
+	ClientIP string
+}
+
+// GatewayResponse 网关统一响应结构，可来自后端转发或后续扩展（mock / 缓存）
+type GatewayResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+var (
+	ErrGatewayNotFound     = errors.New("接口不存在")
+	ErrGatewayDisabled     = errors.New("接口已禁用")
+	ErrGatewayMethod       = errors.New("请求方法不被允许")
+	ErrGatewayNoUpstream   = errors.New("未配置后端服务")
+	ErrGatewayTooLarge     = errors.New("请求体过大")
+	ErrGatewayRespTooLarge = errors.New("后端响应体过大")
+	ErrGatewayBreakerOpen  = errors.New("后端服务熔断中")
+	ErrGatewayDraft        = errors.New("接口尚未发布")
+	ErrGatewayRetired      = errors.New("接口已下线")
+	ErrGatewaySunset       = errors.New("接口已过计划下线时间")
+)
+
+// hopHeaders 逐跳头，不向后端/客户端透传
+var hopHeaders = []string{"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization", "Proxy-Connection", "Te", "Trailer", "Transfer-Encoding", "Upgrade"}
+
+func NewGatewayService(ifl *InterfaceListService, up UpstreamResolver, timeout time.Duration, maxBody int64) *GatewayService {
+	// 超时由每次调用的 context 控制（可按接口覆盖），Client 本身不设全局超时
+	return &GatewayService{IfList: ifl, Upstream: up, Client: &http.Client{}, MaxBody: maxBody, Timeout: timeout}
+}
+
+// Resolve 按路径 key 查找接口：先按 hash，再按 api_class（仅 hash_type=1）；禁用接口返回 ErrGatewayDisabled，
+// 草稿接口返回 ErrGatewayDraft
+func (s *GatewayService) Resolve(ctx context.Context, key string) (*model.AdminInterfaceList, error) {
+	key = strings.Trim(key, "/")
+	if key == "" {
+		return nil, ErrGatewayNotFound
+	}
+	api, err := s.IfList.FindByHash(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if api == nil || api.HashType == 1 {
+		if api, err = s.IfList.FindByAPIClass(ctx, key); err != nil {
+			return nil, err
+		}
+		if api != nil && api.HashType != 1 {
+			api = nil
+		}
+	}
+	if api == nil {
+		return nil, ErrGatewayNotFound
+	}
+	if api.Status != 1 {
+		return nil, ErrGatewayDisabled
+	}
+	if InterfaceLifecycle(api) == LifecycleDraft {
+		return nil, ErrGatewayDraft
+	}
+	return api, nil
+}
+
+// CheckLifecycle retired 接口返回 ErrGatewayRetired；开启 RejectAfterSunset 时已过 sunset_at 的废弃接口返回 ErrGatewaySunset
+func (s *GatewayService) CheckLifecycle(api *model.AdminInterfaceList, now time.Time) error {
+	switch InterfaceLifecycle(api) {
+	case LifecycleRetired:
+		return ErrGatewayRetired
+	case LifecycleDeprecated:
+		if s.RejectAfterSunset && Sunset(api, now) {
+			return ErrGatewaySunset
+		}
+	}
+	return nil
+}
+
+// MethodAllowed 校验请求方法是否符合接口 method 配置
+func (s *GatewayService) MethodAllowed(api *model.AdminInterfaceList, method string) bool {
+	m := InterfaceMethod(api.Method)
+	return m == "*" || strings.EqualFold(m, method)
+}
+
+// UpstreamService 从 api_class 解析服务名（首段，如 User/login -> user）
+func UpstreamService(apiClass string) string {
+	cls := strings.Trim(strings.ReplaceAll(apiClass, ".", "/"), "/")
+	if i := strings.Index(cls, "/"); i > 0 {
+		cls = cls[:i]
+	}
+	return strings.ToLower(cls)
+}
+
+// retryBackoff 重试间隔基数（第 n 次重试等待 n 倍）
+const retryBackoff = 50 * time.Millisecond
+
+// idempotentMethod 可安全重试的方法
+func idempotentMethod(m string) bool {
+	switch strings.ToUpper(m) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// CallTimeout 接口单次调用超时：timeout_ms>0 时覆盖全局配置
+func (s *GatewayService) CallTimeout(api *model.AdminInterfaceList) time.Duration {
+	if api.TimeoutMS > 0 {
+		return time.Duration(api.TimeoutMS) * time.Millisecond
+	}
+	return s.Timeout
+}
+
+// Forward 转发请求至后端：路径为 base + "/" + api_class，透传 query/header/body 并注入 trace 上下文。
+// 每次尝试独立超时；网络错误或 5xx 计入后端熔断器，幂等方法最多重试 Retries 次（每次重新解析后端，
+// 服务发现时可切换实例）；熔断打开的后端直接跳过，全部不可用时返回 ErrGatewayBreakerOpen。
+func (s *GatewayService) Forward(ctx context.Context, api *model.AdminInterfaceList, req GatewayRequest) (*GatewayResponse, error) {
+	if s.MaxBody > 0 && int64(len(req.Body)) > s.MaxBody {
+		return nil, ErrGatewayTooLarge
+	}
+	svc := UpstreamService(api.APIClass)
+	attempts := 1
+	if s.Retries > 0 && idempotentMethod(req.Method) {
+		attempts += s.Retries
+	}
+	var (
+		lastResp *GatewayResponse
+		lastErr  error
+		wait     bool
+	)
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			metrics.GatewayRetriesTotal.WithLabelValues(svc).Inc()
+		}
+		if wait {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(retryBackoff * time.Duration(i)):
+			}
+		}
+		base, err := s.Upstream.Resolve(ctx, svc)
+		if err != nil {
+			return nil, err
+		}
+		var br *CircuitBreaker
+		if s.Breakers != nil {
+			br = s.Breakers.Get(base)
+			if !br.Allow() {
+				metrics.GatewayBreakerRejectedTotal.WithLabelValues(base).Inc()
+				lastResp, lastErr, wait = nil, ErrGatewayBreakerOpen, false
+				continue
+			}
+		}
+		resp, err := s.forwardOnce(ctx, base, api, req)
+		if err != nil && ctx.Err() != nil { // 调用方取消，不计入后端失败
+			if br != nil {
+				br.Cancel()
+			}
+			return nil, err
+		}
+		if errors.Is(err, ErrGatewayRespTooLarge) { // 后端已正常响应，重试无意义，不计入熔断失败
+			if br != nil {
+				br.Record(true)
+			}
+			return nil, err
+		}
+		ok := err == nil && resp.Status < http.StatusInternalServerError
+		if br != nil {
+			br.Record(ok)
+		}
+		if ok {
+			return resp, nil
+		}
+		lastResp, lastErr, wait = resp, err, true
+	}
+	if lastResp != nil { // 重试耗尽时 5xx 原样返回
+		return lastResp, nil
+	}
+	return nil, lastErr
+}
+
+// forwardOnce 单次调用后端，超时取 CallTimeout；响应体超出 MaxBody 返回 ErrGatewayRespTooLarge
+func (s *GatewayService) forwardOnce(ctx context.Context, base string, api *model.AdminInterfaceList, req GatewayRequest) (*GatewayResponse, error) {
+	if d := s.CallTimeout(api); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	target := strings.TrimRight(base, "/") + "/" + strings.Trim(strings.ReplaceAll(api.APIClass, ".", "/"), "/")
+	if req.RawQuery != "" {
+		target += "?" + req.RawQuery
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, target, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header = cloneHeader(req.Header)
+	if req.ClientIP != "" {
+		if prior := httpReq.Header.Get("X-Forwarded-For"); prior != "" {
+			httpReq.Header.Set("X-Forwarded-For", prior+", "+req.ClientIP)
+		} else {
+			httpReq.Header.Set("X-Forwarded-For", req.ClientIP)
+		}
+	}
+	httpReq.Header.Set("X-Api-Hash", api.Hash)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(httpReq.Header))
+	resp, err := s.Client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var body []byte
+	if s.MaxBody > 0 {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, s.MaxBody+1))
+	} else {
+		body, err = io.ReadAll(resp.Body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.MaxBody > 0 && int64(len(body)) > s.MaxBody { // 不返回截断的响应体（也避免被写入响应缓存）
+		return nil, ErrGatewayRespTooLarge
+	}
+	return &GatewayResponse{Status: resp.StatusCode, Header: cloneHeader(resp.Header), Body: body}, nil
+}
+
+// ReadRequestBody 读取请求体（超出 max 返回 ErrGatewayTooLarge），并回填供后续中间件 / 处理器再次读取
+func ReadRequestBody(r *http.Request, max int64) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	var rd io.Reader = r.Body
+	if max > 0 {
+		rd = io.LimitReader(r.Body, max+1)
+	}
+	b, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+	if max > 0 && int64(len(b)) > max {
+		return nil, ErrGatewayTooLarge
+	}
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+// cloneHeader 复制 header 并剔除逐跳头
+func cloneHeader(h http.Header) http.Header {
+	out := h.Clone()
+	if out == nil {
+		out = http.Header{}
+	}
+	for _, k := range hopHeaders {
+		out.Del(k)
+	}
+	out.Del("Content-Length")
+	return out
+}
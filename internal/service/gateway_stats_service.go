@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	redisrepo "go-apiadmin/internal/repository/redis"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 网关调用统计：按分钟桶写入 Redis hash（gwstat:{scope}:{id}:{unix分钟}），
+// 字段 n 调用数 / err 错误数 / lat 累计耗时(ms) / b{i} 耗时直方图桶计数，p95 由直方图估算。
+const (
+	StatScopeApp = "app"
+	StatScopeAPI = "api"
+
+	statRetention  = 25 * time.Hour
+	MaxStatMinutes = 1440
+)
+
+// statBounds 耗时直方图上界（毫秒），超出最后一档计入溢出桶
+var statBounds = []int64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+type GatewayStatsService struct {
+	Redis *redisrepo.Client
+}
+
+func NewGatewayStatsService(r *redisrepo.Client) *GatewayStatsService {
+	return &GatewayStatsService{Redis: r}
+}
+
+// CallRecord 单次网关调用
+type CallRecord struct {
+	AppID   string
+	Hash    string
+	Latency time.Duration
+	Error   bool
+	At      time.Time
+}
+
+// MinuteStat 单分钟统计
+type MinuteStat struct {
+	Minute int64 `json:"minute"` // unix 秒（分钟起点）
+	Total  int64 `json:"total"`
+	Errors int64 `json:"errors"`
+}
+
+// CallStats 窗口聚合结果
+type CallStats struct {
+	Scope     string       `json:"scope"`
+	ID        string       `json:"id"`
+	Minutes   int          `json:"minutes"`
+	Total     int64        `json:"total"`
+	Errors    int64        `json:"errors"`
+	QPS       float64      `json:"qps"`
+	ErrorRate float64      `json:"error_rate"`
+	AvgMS     float64      `json:"avg_ms"`
+	P95MS     float64      `json:"p95_ms"`
+	Series    []MinuteStat `json:"series"`
+}
+
+func statKey(scope, id string, minute int64) string {
+	return "gwstat:" + scope + ":" + id + ":" + strconv.FormatInt(minute, 10)
+}
+
+func statBucket(ms int64) int {
+	for i, b := range statBounds {
+		if ms <= b {
+			return i
+		}
+	}
+	return len(statBounds)
+}
+
+// Record 累加接口（及应用，若有）当前分钟统计
+func (s *GatewayStatsService) Record(ctx context.Context, r CallRecord) error {
+	if s == nil || s.Redis == nil || r.Hash == "" {
+		return nil
+	}
+	if r.At.IsZero() {
+		r.At = time.Now()
+	}
+	minute := r.At.Unix() / 60
+	ms := r.Latency.Milliseconds()
+	bucket := "b" + strconv.Itoa(statBucket(ms))
+	keys := []string{statKey(StatScopeAPI, r.Hash, minute)}
+	if r.AppID != "" {
+		keys = append(keys, statKey(StatScopeApp, r.AppID, minute))
+	}
+	pipe := s.Redis.Client.Pipeline()
+	for _, k := range keys {
+		pipe.HIncrBy(ctx, k, "n", 1)
+		if r.Error {
+			pipe.HIncrBy(ctx, k, "err", 1)
+		}
+		pipe.HIncrBy(ctx, k, "lat", ms)
+		pipe.HIncrBy(ctx, k, bucket, 1)
+		pipe.Expire(ctx, k, statRetention)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Query 最近 minutes 分钟（含当前分钟）聚合：QPS 按窗口秒数平均，p95 为直方图桶内线性插值估算
+func (s *GatewayStatsService) Query(ctx context.Context, scope, id string, minutes int) (*CallStats, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, errors.New("id required")
+	}
+	if scope != StatScopeApp && scope != StatScopeAPI {
+		return nil, errors.New("invalid scope")
+	}
+	if minutes <= 0 {
+		minutes = 60
+	}
+	if minutes > MaxStatMinutes {
+		minutes = MaxStatMinutes
+	}
+	if s.Redis == nil {
+		return nil, errors.New("redis not configured")
+	}
+	now := time.Now().Unix() / 60
+	pipe := s.Redis.Client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, minutes)
+	for i := 0; i < minutes; i++ {
+		cmds[i] = pipe.HGetAll(ctx, statKey(scope, id, now-int64(minutes-1-i)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	out := &CallStats{Scope: scope, ID: id, Minutes: minutes, Series: make([]MinuteStat, 0, minutes)}
+	hist := make([]int64, len(statBounds)+1)
+	var lat int64
+	for i, cmd := range cmds {
+		m := cmd.Val()
+		ms := MinuteStat{Minute: (now - int64(minutes-1-i)) * 60, Total: atoi64(m["n"]), Errors: atoi64(m["err"])}
+		out.Series = append(out.Series, ms)
+		out.Total += ms.Total
+		out.Errors += ms.Errors
+		lat += atoi64(m["lat"])
+		for b := range hist {
+			hist[b] += atoi64(m["b"+strconv.Itoa(b)])
+		}
+	}
+	if out.Total > 0 {
+		out.QPS = float64(out.Total) / float64(minutes*60)
+		out.ErrorRate = float64(out.Errors) / float64(out.Total)
+		out.AvgMS = float64(lat) / float64(out.Total)
+		out.P95MS = histQuantile(hist, 0.95)
+	}
+	return out, nil
+}
+
+// histQuantile 直方图分位数估算；落在溢出桶时返回最后一档上界
+func histQuantile(hist []int64, q float64) float64 {
+	var total int64
+	for _, n := range hist {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := q * float64(total)
+	var cum int64
+	for i, n := range hist {
+		if n == 0 {
+			continue
+		}
+		if float64(cum+n) >= rank {
+			if i >= len(statBounds) {
+				return float64(statBounds[len(statBounds)-1])
+			}
+			lo := 0.0
+			if i > 0 {
+				lo = float64(statBounds[i-1])
+			}
+			hi := float64(statBounds[i])
+			return lo + (hi-lo)*(rank-float64(cum))/float64(n)
+		}
+		cum += n
+	}
+	return float64(statBounds[len(statBounds)-1])
+}
+
+func atoi64(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+// 废弃 / 下线接口调用方：gwdep:{hash} 记录各应用累计调用数，gwdep:last:{hash} 记录最近调用时间（unix 秒），
+// 每次写入续期，连续 deprecatedUsageTTL 无调用后清空
+const deprecatedUsageTTL = 30 * 24 * time.Hour
+
+func deprecatedKey(hash string) string     { return "gwdep:" + hash }
+func deprecatedLastKey(hash string) string { return "gwdep:last:" + hash }
+
+// RecordDeprecated 累加应用对废弃 / 下线接口的调用（appID 为空表示未鉴权调用）
+func (s *GatewayStatsService) RecordDeprecated(ctx context.Context, hash, appID string, at time.Time) error {
+	if s == nil || s.Redis == nil || hash == "" {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	pipe := s.Redis.Client.Pipeline()
+	pipe.HIncrBy(ctx, deprecatedKey(hash), appID, 1)
+	pipe.HSet(ctx, deprecatedLastKey(hash), appID, at.Unix())
+	pipe.Expire(ctx, deprecatedKey(hash), deprecatedUsageTTL)
+	pipe.Expire(ctx, deprecatedLastKey(hash), deprecatedUsageTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// DeprecatedCallers 接口各调用方统计，按调用量倒序；未配置 Redis 时返回空
+func (s *GatewayStatsService) DeprecatedCallers(ctx context.Context, hash string) ([]DeprecationCaller, error) {
+	out := []DeprecationCaller{}
+	if s == nil || s.Redis == nil {
+		return out, nil
+	}
+	pipe := s.Redis.Client.Pipeline()
+	calls := pipe.HGetAll(ctx, deprecatedKey(hash))
+	last := pipe.HGetAll(ctx, deprecatedLastKey(hash))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	lastMap := last.Val()
+	for app, n := range calls.Val() {
+		out = append(out, DeprecationCaller{AppID: app, Calls: atoi64(n), LastCall: atoi64(lastMap[app])})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Calls != out[j].Calls {
+			return out[i].Calls > out[j].Calls
+		}
+		return out[i].AppID < out[j].AppID
+	})
+	return out, nil
+}
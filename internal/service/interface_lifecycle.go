@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+)
+
+// 接口生命周期：draft（草稿，网关不可调用）→ active → deprecated（仍可调用，网关返回 Deprecation / Sunset 头）
+// → retired（已下线，网关拒绝）。deprecated 可撤销回 active，retired 可恢复为 deprecated。
+// 生命周期属于运营状态，不随修订回滚；变更经契约检查记录事件，下线视为破坏性变更需确认。
+const (
+	LifecycleDraft      = "draft"
+	LifecycleActive     = "active"
+	LifecycleDeprecated = "deprecated"
+	LifecycleRetired    = "retired"
+
+	ContractSourceLifecycle = "lifecycle"
+)
+
+var lifecycleTransitions = map[string][]string{
+	LifecycleDraft:      {LifecycleActive},
+	LifecycleActive:     {LifecycleDeprecated},
+	LifecycleDeprecated: {LifecycleActive, LifecycleRetired},
+	LifecycleRetired:    {LifecycleDeprecated},
+}
+
+var (
+	ErrLifecycle            = errors.New("lifecycle 取值应为 draft / active / deprecated / retired")
+	ErrLifecycleTransition  = errors.New("不允许的生命周期变更")
+	ErrLifecycleSunset      = errors.New("仅 deprecated / retired 接口可设置 sunset_at，且不能为负数")
+	ErrLifecycleReplacement = errors.New("替代接口须为其他已启用的 active 接口")
+	ErrLifecycleNotFound    = errors.New("接口不存在")
+)
+
+// InterfaceLifecycle 接口生命周期，历史数据为空时视为 active
+func InterfaceLifecycle(api *model.AdminInterfaceList) string {
+	if api == nil || api.Lifecycle == "" {
+		return LifecycleActive
+	}
+	return api.Lifecycle
+}
+
+// LifecycleNotice 是否需要向调用方 / 文档读者提示废弃或下线
+func LifecycleNotice(api *model.AdminInterfaceList) bool {
+	l := InterfaceLifecycle(api)
+	return l == LifecycleDeprecated || l == LifecycleRetired
+}
+
+func validLifecycle(l string) bool {
+	_, ok := lifecycleTransitions[l]
+	return ok
+}
+
+// LifecycleParams 生命周期变更；SunsetAt / Replacement 为 nil 时保持原值（回到 active / draft 时清空）
+type LifecycleParams struct {
+	ID          int64
+	Lifecycle   string
+	SunsetAt    *int64
+	Replacement *string
+	// ConfirmBreaking 确认下线（retired 为破坏性变更）
+	ConfirmBreaking bool
+}
+
+// ChangeLifecycle 校验状态流转与替代接口后更新生命周期列；进入 deprecated 时记录废弃时间，
+// 下线未指定 sunset_at 时取当前时间
+func (s *InterfaceListService) ChangeLifecycle(ctx context.Context, p LifecycleParams) error {
+	if p.ID <= 0 {
+		return errors.New("invalid id")
+	}
+	m, err := s.DAO.FindByID(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return ErrLifecycleNotFound
+	}
+	cur := InterfaceLifecycle(m)
+	next := strings.ToLower(strings.TrimSpace(p.Lifecycle))
+	if next == "" {
+		next = cur
+	}
+	if !validLifecycle(next) {
+		return ErrLifecycle
+	}
+	if next != cur {
+		allowed := false
+		for _, l := range lifecycleTransitions[cur] {
+			allowed = allowed || l == next
+		}
+		if !allowed {
+			return fmt.Errorf("%w：%s -> %s", ErrLifecycleTransition, cur, next)
+		}
+	}
+	edited := *m
+	edited.Lifecycle = next
+	now := time.Now().Unix()
+	switch next {
+	case LifecycleActive, LifecycleDraft:
+		if p.SunsetAt != nil && *p.SunsetAt != 0 {
+			return ErrLifecycleSunset
+		}
+		edited.DeprecatedAt, edited.SunsetAt, edited.Replacement = 0, 0, ""
+	default:
+		if p.SunsetAt != nil {
+			if *p.SunsetAt < 0 {
+				return ErrLifecycleSunset
+			}
+			edited.SunsetAt = *p.SunsetAt
+		}
+		if p.Replacement != nil {
+			edited.Replacement = strings.TrimSpace(*p.Replacement)
+		}
+		if edited.DeprecatedAt == 0 {
+			edited.DeprecatedAt = now
+		}
+		if next == LifecycleRetired && edited.SunsetAt == 0 {
+			edited.SunsetAt = now
+		}
+	}
+	if edited.Replacement != "" && edited.Replacement != m.Replacement {
+		if err := s.validateReplacement(ctx, m, edited.Replacement); err != nil {
+			return err
+		}
+	}
+	s.Revisions.Baseline(ctx, m.Hash)
+	check, err := s.Contracts.Check(ctx, m.Hash, ContractSourceLifecycle, p.ConfirmBreaking, func(snap *InterfaceSnapshot) {
+		e := edited
+		snap.Interface = &e
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.DAO.UpdateColumns(ctx, m.ID, lifecycleColumns(&edited)); err != nil {
+		return err
+	}
+	s.invalidateOne(m.ID, m.Hash, m.APIClass)
+	s.Search.Touch(ctx, m.Hash)
+	s.Revisions.Record(ctx, m.Hash, RevisionLifecycle)
+	s.Contracts.Emit(ctx, check)
+	return nil
+}
+
+// validateReplacement 替代接口须存在、非自身、已启用且处于 active
+func (s *InterfaceListService) validateReplacement(ctx context.Context, m *model.AdminInterfaceList, hash string) error {
+	if hash == m.Hash {
+		return ErrLifecycleReplacement
+	}
+	r, err := s.DAO.FindByHash(ctx, hash)
+	if err != nil {
+		return err
+	}
+	if r == nil || r.Status != 1 || InterfaceLifecycle(r) != LifecycleActive {
+		return ErrLifecycleReplacement
+	}
+	return nil
+}
+
+// lifecycleColumns 生命周期列（允许置零 / 置空）
+func lifecycleColumns(m *model.AdminInterfaceList) map[string]interface{} {
+	return map[string]interface{}{
+		"lifecycle": InterfaceLifecycle(m), "deprecated_at": m.DeprecatedAt, "sunset_at": m.SunsetAt, "replacement": m.Replacement,
+	}
+}
+
+// Successor 替代接口（未设置或已不存在时返回 nil）
+func (s *InterfaceListService) Successor(ctx context.Context, api *model.AdminInterfaceList) *model.AdminInterfaceList {
+	if api == nil || api.Replacement == "" {
+		return nil
+	}
+	r, err := s.FindByHash(ctx, api.Replacement)
+	if err != nil {
+		return nil
+	}
+	return r
+}
+
+// Sunset 是否已过计划下线时间
+func Sunset(api *model.AdminInterfaceList, now time.Time) bool {
+	return api.SunsetAt > 0 && now.Unix() >= api.SunsetAt
+}
+
+// LifecycleHeaders 废弃 / 下线接口的响应头：Deprecation（RFC 9745，@unix 秒）、Sunset（RFC 8594，HTTP-date）、
+// Link rel="successor-version" 指向替代接口网关路径
+func LifecycleHeaders(api, successor *model.AdminInterfaceList) http.Header {
+	h := http.Header{}
+	if !LifecycleNotice(api) {
+		return h
+	}
+	if api.DeprecatedAt > 0 {
+		h.Set("Deprecation", "@"+strconv.FormatInt(api.DeprecatedAt, 10))
+	} else {
+		h.Set("Deprecation", "true")
+	}
+	if api.SunsetAt > 0 {
+		h.Set("Sunset", time.Unix(api.SunsetAt, 0).UTC().Format(http.TimeFormat))
+	}
+	if successor != nil {
+		h.Set("Link", "<"+GatewayPath(*successor)+`>; rel="successor-version"`)
+	}
+	return h
+}
+
+// LifecycleBanner wiki 文档中的生命周期提示
+type LifecycleBanner struct {
+	Lifecycle    string                `json:"lifecycle"`
+	Message      string                `json:"message"`
+	DeprecatedAt int64                 `json:"deprecated_at"`
+	SunsetAt     int64                 `json:"sunset_at"`
+	Replacement  *LifecycleReplacement `json:"replacement"` // 未设置替代接口时为 null
+}
+
+// LifecycleReplacement 替代接口
+type LifecycleReplacement struct {
+	Hash     string `json:"hash"`
+	APIClass string `json:"api_class"`
+	Info     string `json:"info"`
+	Path     string `json:"path"`
+}
+
+// NewLifecycleBanner active 接口返回 nil
+func NewLifecycleBanner(api, successor *model.AdminInterfaceList) *LifecycleBanner {
+	l := InterfaceLifecycle(api)
+	if l == LifecycleActive {
+		return nil
+	}
+	b := &LifecycleBanner{Lifecycle: l, DeprecatedAt: api.DeprecatedAt, SunsetAt: api.SunsetAt}
+	switch l {
+	case LifecycleDraft:
+		b.Message = "该接口为草稿，尚未发布，暂不可调用"
+	case LifecycleDeprecated:
+		b.Message = "该接口已废弃，请尽快迁移"
+		if api.SunsetAt > 0 {
+			b.Message += "，计划于 " + time.Unix(api.SunsetAt, 0).Format("2006-01-02") + " 下线"
+		}
+	case LifecycleRetired:
+		b.Message = "该接口已下线，不再提供服务"
+	}
+	if successor != nil {
+		b.Replacement = &LifecycleReplacement{Hash: successor.Hash, APIClass: successor.APIClass, Info: successor.Info, Path: GatewayPath(*successor)}
+		b.Message += "，替代接口：" + successor.APIClass
+	}
+	return b
+}
+
+// DeprecationUsage 废弃 / 下线接口的调用方统计
+type DeprecationUsage struct {
+	Hash         string              `json:"hash"`
+	APIClass     string              `json:"api_class"`
+	Info         string              `json:"info"`
+	Lifecycle    string              `json:"lifecycle"`
+	DeprecatedAt int64               `json:"deprecated_at"`
+	SunsetAt     int64               `json:"sunset_at"`
+	Replacement  string              `json:"replacement"`
+	Calls        int64               `json:"calls"`
+	Apps         []DeprecationCaller `json:"apps"`
+}
+
+// DeprecationCaller 单个调用方；app_id 为空表示未鉴权调用
+type DeprecationCaller struct {
+	AppID    string `json:"app_id"`
+	AppName  string `json:"app_name"`
+	Calls    int64  `json:"calls"`
+	LastCall int64  `json:"last_call"`
+}
+
+// DeprecationUsage 废弃 / 下线接口按应用的调用统计（调用量倒序）；hash 为空时返回全部废弃 / 下线接口
+func (s *InterfaceListService) DeprecationUsage(ctx context.Context, hash string) ([]DeprecationUsage, error) {
+	var apis []model.AdminInterfaceList
+	if hash = strings.TrimSpace(hash); hash != "" {
+		m, err := s.DAO.FindByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			return nil, ErrLifecycleNotFound
+		}
+		apis = append(apis, *m)
+	} else {
+		list, err := s.DAO.ListByLifecycle(ctx, LifecycleDeprecated, LifecycleRetired)
+		if err != nil {
+			return nil, err
+		}
+		apis = list
+	}
+	out := make([]DeprecationUsage, 0, len(apis))
+	appIDs := map[string]struct{}{}
+	for _, a := range apis {
+		u := DeprecationUsage{Hash: a.Hash, APIClass: a.APIClass, Info: a.Info, Lifecycle: InterfaceLifecycle(&a),
+			DeprecatedAt: a.DeprecatedAt, SunsetAt: a.SunsetAt, Replacement: a.Replacement, Apps: []DeprecationCaller{}}
+		callers, err := s.Usage.DeprecatedCallers(ctx, a.Hash)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range callers {
+			u.Calls += c.Calls
+			u.Apps = append(u.Apps, c)
+			if c.AppID != "" {
+				appIDs[c.AppID] = struct{}{}
+			}
+		}
+		out = append(out, u)
+	}
+	if len(appIDs) > 0 && s.AppDAO != nil {
+		ids := make([]string, 0, len(appIDs))
+		for id := range appIDs {
+			ids = append(ids, id)
+		}
+		apps, err := s.AppDAO.BulkByAppIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for i := range out {
+			for j := range out[i].Apps {
+				out[i].Apps[j].AppName = apps[out[i].Apps[j].AppID].AppName
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Calls > out[j].Calls })
+	return out, nil
+}
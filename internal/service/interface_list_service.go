@@ -16,11 +16,18 @@ import (
 	"go-apiadmin/internal/metrics"
 	"go-apiadmin/internal/pkg/cache"
 	"go-apiadmin/internal/repository/dao"
+
+	"gorm.io/gorm"
 )
 
 type InterfaceListService struct {
-	DAO   *dao.AdminInterfaceListDAO
-	Cache cache.Cache
+	DAO       *dao.AdminInterfaceListDAO
+	Cache     cache.Cache
+	Search    *WikiSearchIndex     // 可选：接口变更时同步 wiki 搜索索引
+	Revisions *RevisionService     // 可选：接口变更时保存修订快照
+	Contracts *ContractService     // 可选：编辑时检查破坏性变更并记录契约事件
+	Usage     *GatewayStatsService // 可选：废弃接口调用方统计（网关写入）
+	AppDAO    *dao.AdminAppDAO     // 可选：调用方统计补充应用名
 }
 
 func NewInterfaceListService(d *dao.AdminInterfaceListDAO) *InterfaceListService {
@@ -41,15 +48,23 @@ type ListInterfaceParams struct {
 }
 
 type InterfaceDTO struct {
-	ID          int64  `json:"id"`
-	APIClass    string `json:"api_class"`
-	Hash        string `json:"hash"`
-	AccessToken int8   `json:"access_token"`
-	Status      int8   `json:"status"`
-	Method      int8   `json:"method"`
-	Info        string `json:"info"`
-	IsTest      int8   `json:"is_test"`
-	GroupHash   string `json:"group_hash"`
+	ID           int64  `json:"id"`
+	APIClass     string `json:"api_class"`
+	Hash         string `json:"hash"`
+	AccessToken  int8   `json:"access_token"`
+	Status       int8   `json:"status"`
+	Method       int8   `json:"method"`
+	Info         string `json:"info"`
+	IsTest       int8   `json:"is_test"`
+	GroupHash    string `json:"group_hash"`
+	TimeoutMS    int    `json:"timeout_ms"`
+	CacheTTL     int    `json:"cache_ttl"`
+	CacheKeys    string `json:"cache_keys"`
+	CacheVary    string `json:"cache_vary"`
+	Lifecycle    string `json:"lifecycle"`
+	DeprecatedAt int64  `json:"deprecated_at"`
+	SunsetAt     int64  `json:"sunset_at"`
+	Replacement  string `json:"replacement"`
 }
 
 type ListInterfaceResult struct {
@@ -66,6 +81,20 @@ func (s *InterfaceListService) listKey(p ListInterfaceParams) string {
 }
 func (s *InterfaceListService) infoKeyID(id int64) string      { return "iflist:info:id:" + _intToStr(id) }
 func (s *InterfaceListService) infoKeyHash(hash string) string { return "iflist:info:hash:" + hash }
+func (s *InterfaceListService) infoKeyClass(apiClass string) string {
+	return "iflist:info:class:" + strings.ToLower(apiClass)
+}
+
+// interfaceMethods 接口 method 字段取值: 0 不限 1 POST 2 GET（与 RefreshRoutes 生成的路由一致）
+var interfaceMethods = []string{"*", "POST", "GET"}
+
+// InterfaceMethod 返回接口允许的 HTTP 方法，"*" 表示不限
+func InterfaceMethod(m int8) string {
+	if int(m) < 0 || int(m) >= len(interfaceMethods) {
+		return interfaceMethods[0]
+	}
+	return interfaceMethods[m]
+}
 
 func (s *InterfaceListService) List(ctx context.Context, p ListInterfaceParams) (*ListInterfaceResult, error) {
 	if s.Cache != nil {
@@ -92,7 +121,8 @@ func (s *InterfaceListService) List(ctx context.Context, p ListInterfaceParams)
 	}
 	res := make([]InterfaceDTO, 0, len(list))
 	for _, m := range list {
-		res = append(res, InterfaceDTO{ID: m.ID, APIClass: m.APIClass, Hash: m.Hash, AccessToken: m.AccessToken, Status: m.Status, Method: m.Method, Info: m.Info, IsTest: m.IsTest, GroupHash: m.GroupHash})
+		res = append(res, InterfaceDTO{ID: m.ID, APIClass: m.APIClass, Hash: m.Hash, AccessToken: m.AccessToken, Status: m.Status, Method: m.Method, Info: m.Info, IsTest: m.IsTest, GroupHash: m.GroupHash, TimeoutMS: m.TimeoutMS, CacheTTL: m.CacheTTL, CacheKeys: m.CacheKeys, CacheVary: m.CacheVary,
+			Lifecycle: InterfaceLifecycle(&m), DeprecatedAt: m.DeprecatedAt, SunsetAt: m.SunsetAt, Replacement: m.Replacement})
 	}
 	result := &ListInterfaceResult{List: res, Total: total}
 	if s.Cache != nil {
@@ -102,6 +132,44 @@ func (s *InterfaceListService) List(ctx context.Context, p ListInterfaceParams)
 	return result, nil
 }
 
+// FindByHash 按 hash 读取接口（带缓存，未找到返回 nil），供网关/文档复用
+func (s *InterfaceListService) FindByHash(ctx context.Context, hash string) (*model.AdminInterfaceList, error) {
+	return s.findCached(ctx, s.infoKeyHash(hash), func() (*model.AdminInterfaceList, error) { return s.DAO.FindByHash(ctx, hash) })
+}
+
+// FindByAPIClass 按 api_class 读取接口（带缓存，未找到返回 nil）
+func (s *InterfaceListService) FindByAPIClass(ctx context.Context, apiClass string) (*model.AdminInterfaceList, error) {
+	return s.findCached(ctx, s.infoKeyClass(apiClass), func() (*model.AdminInterfaceList, error) { return s.DAO.FindByAPIClass(ctx, apiClass) })
+}
+
+func (s *InterfaceListService) findCached(ctx context.Context, key string, load func() (*model.AdminInterfaceList, error)) (*model.AdminInterfaceList, error) {
+	if s.Cache != nil {
+		if v, _ := s.Cache.Get(ctx, key); v != "" {
+			if cache.IsNilSentinel(v) {
+				metrics.CacheNilHit.Inc()
+				return nil, nil
+			}
+			var cached model.AdminInterfaceList
+			if json.Unmarshal([]byte(v), &cached) == nil {
+				return &cached, nil
+			}
+		}
+	}
+	m, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if s.Cache != nil {
+		if m == nil { // 不存在 sentinel 防穿透
+			_ = s.Cache.SetEX(ctx, key, cache.WrapNil(true), 10*time.Second)
+			return nil, nil
+		}
+		b, _ := json.Marshal(m)
+		_ = s.Cache.SetEX(ctx, key, string(b), 60*time.Second)
+	}
+	return m, nil
+}
+
 type AddInterfaceParams struct {
 	APIClass    string
 	AccessToken int8
@@ -111,6 +179,11 @@ type AddInterfaceParams struct {
 	IsTest      int8
 	ReturnStr   string
 	GroupHash   string
+	TimeoutMS   int
+	CacheTTL    int
+	CacheKeys   string
+	CacheVary   string
+	Lifecycle   string // 仅 draft / active，空为 active
 }
 
 type EditInterfaceParams struct {
@@ -123,32 +196,57 @@ type EditInterfaceParams struct {
 	IsTest      *int8
 	ReturnStr   *string
 	GroupHash   *string
+	TimeoutMS   *int
+	CacheTTL    *int
+	CacheKeys   *string
+	CacheVary   *string
+	// ConfirmBreaking 确认提交破坏性变更（默认拒绝并返回 *BreakingChangeError）
+	ConfirmBreaking bool
 }
 
 func (s *InterfaceListService) Add(ctx context.Context, p AddInterfaceParams) (int64, error) {
 	if strings.TrimSpace(p.APIClass) == "" {
 		return 0, errors.New("api_class required")
 	}
+	if p.TimeoutMS < 0 {
+		return 0, errors.New("invalid timeout_ms")
+	}
+	if !CacheTTLValid(p.CacheTTL) {
+		return 0, errors.New("invalid cache_ttl")
+	}
+	if p.Lifecycle == "" {
+		p.Lifecycle = LifecycleActive
+	}
+	if p.Lifecycle != LifecycleActive && p.Lifecycle != LifecycleDraft {
+		return 0, ErrLifecycle
+	}
 	if ok, err := s.DAO.ExistsAPIClass(ctx, p.APIClass, 0); err != nil {
 		return 0, err
 	} else if ok {
 		return 0, errors.New("api_class exists")
 	}
-	// 生成 hash (短 sha1)
-	h := sha1.New()
-	h.Write([]byte(p.APIClass + time.Now().Format(time.RFC3339Nano)))
-	hash := hex.EncodeToString(h.Sum(nil))
-	if len(hash) > 32 {
-		hash = hash[:32]
-	}
-	m := &model.AdminInterfaceList{APIClass: p.APIClass, Hash: hash, AccessToken: p.AccessToken, Status: p.Status, Method: p.Method, Info: p.Info, IsTest: p.IsTest, ReturnStr: p.ReturnStr, GroupHash: p.GroupHash}
+	m := &model.AdminInterfaceList{APIClass: p.APIClass, Hash: newInterfaceHash(p.APIClass), AccessToken: p.AccessToken, Status: p.Status, Method: p.Method, Info: p.Info, IsTest: p.IsTest, ReturnStr: p.ReturnStr, GroupHash: p.GroupHash, TimeoutMS: p.TimeoutMS, CacheTTL: p.CacheTTL, CacheKeys: p.CacheKeys, CacheVary: p.CacheVary, Lifecycle: p.Lifecycle}
 	if err := s.DAO.Create(ctx, m); err != nil {
 		return 0, err
 	}
+	s.invalidateOne(m.ID, m.Hash, m.APIClass) // 清除可能存在的不存在 sentinel
 	s.invalidateAll()
+	s.Search.Touch(ctx, m.Hash)
+	s.Revisions.Record(ctx, m.Hash, RevisionAdd)
 	return m.ID, nil
 }
 
+// newInterfaceHash 生成接口 hash (短 sha1)
+func newInterfaceHash(apiClass string) string {
+	h := sha1.New()
+	h.Write([]byte(apiClass + time.Now().Format(time.RFC3339Nano)))
+	hash := hex.EncodeToString(h.Sum(nil))
+	if len(hash) > 32 {
+		hash = hash[:32]
+	}
+	return hash
+}
+
 func (s *InterfaceListService) Edit(ctx context.Context, p EditInterfaceParams) error {
 	if p.ID <= 0 {
 		return errors.New("invalid id")
@@ -160,6 +258,8 @@ func (s *InterfaceListService) Edit(ctx context.Context, p EditInterfaceParams)
 	if m == nil {
 		return errors.New("not found")
 	}
+	s.Revisions.Baseline(ctx, m.Hash)
+	oldClass := m.APIClass
 	if p.APIClass != nil {
 		m.APIClass = *p.APIClass
 	}
@@ -184,6 +284,12 @@ func (s *InterfaceListService) Edit(ctx context.Context, p EditInterfaceParams)
 	if p.GroupHash != nil {
 		m.GroupHash = *p.GroupHash
 	}
+	if p.TimeoutMS != nil && *p.TimeoutMS < 0 {
+		return errors.New("invalid timeout_ms")
+	}
+	if p.CacheTTL != nil && !CacheTTLValid(*p.CacheTTL) {
+		return errors.New("invalid cache_ttl")
+	}
 	if p.APIClass != nil {
 		if ok, err := s.DAO.ExistsAPIClass(ctx, m.APIClass, m.ID); err != nil {
 			return err
@@ -191,10 +297,45 @@ func (s *InterfaceListService) Edit(ctx context.Context, p EditInterfaceParams)
 			return errors.New("api_class exists")
 		}
 	}
-	if err := s.DAO.Update(ctx, m); err != nil {
+	check, err := s.Contracts.Check(ctx, m.Hash, ContractSourceEdit, p.ConfirmBreaking, func(next *InterfaceSnapshot) {
+		edited := *m
+		next.Interface = &edited
+	})
+	if err != nil {
 		return err
 	}
-	s.invalidateOne(m.ID, m.Hash)
+	// 网关列允许置零/置空（0 超时恢复全局、0 TTL 关闭缓存），Updates 会跳过零值，单独更新
+	cols := map[string]interface{}{}
+	if p.TimeoutMS != nil {
+		cols["timeout_ms"] = *p.TimeoutMS
+	}
+	if p.CacheTTL != nil {
+		cols["cache_ttl"] = *p.CacheTTL
+	}
+	if p.CacheKeys != nil {
+		cols["cache_keys"] = *p.CacheKeys
+	}
+	if p.CacheVary != nil {
+		cols["cache_vary"] = *p.CacheVary
+	}
+	// 两次写入同一事务，避免留下改了一半的接口
+	err = s.DAO.DB.Transaction(func(tx *gorm.DB) error {
+		d := s.DAO.WithTx(tx)
+		if err := d.Update(ctx, m); err != nil {
+			return err
+		}
+		if len(cols) > 0 {
+			return d.UpdateColumns(ctx, m.ID, cols)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidateOne(m.ID, m.Hash, oldClass, m.APIClass)
+	s.Search.Touch(ctx, m.Hash)
+	s.Revisions.Record(ctx, m.Hash, RevisionEdit)
+	s.Contracts.Emit(ctx, check)
 	return nil
 }
 
@@ -202,11 +343,15 @@ func (s *InterfaceListService) ChangeStatus(ctx context.Context, id int64, st in
 	if id <= 0 {
 		return errors.New("invalid id")
 	}
+	m, _ := s.DAO.FindByID(ctx, id)
+	if m != nil {
+		s.Revisions.Baseline(ctx, m.Hash)
+	}
 	err := s.DAO.ChangeStatus(ctx, id, st)
-	if err == nil {
-		if m, _ := s.DAO.FindByID(ctx, id); m != nil {
-			s.invalidateOne(m.ID, m.Hash)
-		}
+	if err == nil && m != nil {
+		s.invalidateOne(m.ID, m.Hash, m.APIClass)
+		s.Search.Touch(ctx, m.Hash)
+		s.Revisions.Record(ctx, m.Hash, RevisionStatus)
 	}
 	return err
 }
@@ -215,9 +360,14 @@ func (s *InterfaceListService) Delete(ctx context.Context, id int64) error {
 		return errors.New("invalid id")
 	}
 	m, _ := s.DAO.FindByID(ctx, id)
+	if m != nil {
+		s.Revisions.Baseline(ctx, m.Hash)
+	}
 	err := s.DAO.Delete(ctx, id)
 	if err == nil && m != nil {
-		s.invalidateOne(m.ID, m.Hash)
+		s.invalidateOne(m.ID, m.Hash, m.APIClass)
+		s.Search.Touch(ctx, m.Hash)
+		s.Revisions.Record(ctx, m.Hash, RevisionDelete)
 	}
 	return err
 }
@@ -232,17 +382,13 @@ func (s *InterfaceListService) RefreshRoutes(ctx context.Context, tplPath, outPa
 	if err != nil {
 		return err
 	}
-	methodArr := []string{"*", "POST", "GET"}
 	lines := make([]string, 0, len(list))
 	for _, v := range list {
-		mIdx := int(v.Method)
-		if mIdx < 0 || mIdx >= len(methodArr) {
-			mIdx = 0
-		}
+		method := InterfaceMethod(v.Method)
 		if v.HashType == 1 {
-			lines = append(lines, fmt.Sprintf("Route::rule('%s','api.%s','%s')->middleware([app\\\\middleware\\\\ApiAuth::class, app\\\\middleware\\\\ApiPermission::class, app\\\\middleware\\\\RequestFilter::class, app\\\\middleware\\\\ApiLog::class]);", escapePHP(v.APIClass), escapePHP(v.APIClass), methodArr[mIdx]))
+			lines = append(lines, fmt.Sprintf("Route::rule('%s','api.%s','%s')->middleware([app\\\\middleware\\\\ApiAuth::class, app\\\\middleware\\\\ApiPermission::class, app\\\\middleware\\\\RequestFilter::class, app\\\\middleware\\\\ApiLog::class]);", escapePHP(v.APIClass), escapePHP(v.APIClass), method))
 		} else {
-			lines = append(lines, fmt.Sprintf("Route::rule('%s','api.%s','%s')->middleware([app\\\\middleware\\\\ApiAuth::class, app\\\\middleware\\\\ApiPermission::class, app\\\\middleware\\\\RequestFilter::class, app\\\\middleware\\\\ApiLog::class]);", escapePHP(v.Hash), escapePHP(v.APIClass), methodArr[mIdx]))
+			lines = append(lines, fmt.Sprintf("Route::rule('%s','api.%s','%s')->middleware([app\\\\middleware\\\\ApiAuth::class, app\\\\middleware\\\\ApiPermission::class, app\\\\middleware\\\\RequestFilter::class, app\\\\middleware\\\\ApiLog::class]);", escapePHP(v.Hash), escapePHP(v.APIClass), method))
 		}
 	}
 	finalStr := strings.Replace(string(b), "{$API_RULE}", strings.Join(lines, "\n    "), 1)
@@ -255,10 +401,14 @@ func (s *InterfaceListService) RefreshRoutes(ctx context.Context, tplPath, outPa
 func escapePHP(s string) string { return strings.ReplaceAll(s, "'", "\\'") }
 
 // ===== 缓存辅助 =====
-func (s *InterfaceListService) invalidateOne(id int64, hash string) {
+func (s *InterfaceListService) invalidateOne(id int64, hash string, apiClasses ...string) {
 	if s.Cache == nil {
 		return
 	}
-	_ = s.Cache.Del(context.Background(), s.infoKeyID(id), s.infoKeyHash(hash))
+	keys := []string{s.infoKeyID(id), s.infoKeyHash(hash)}
+	for _, c := range apiClasses {
+		keys = append(keys, s.infoKeyClass(c))
+	}
+	_ = s.Cache.Del(context.Background(), keys...)
 }
 func (s *InterfaceListService) invalidateAll() { /* rely on TTL */ }
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go-apiadmin/internal/domain/model"
+)
+
+// jsonSchemaDialect 生成的 schema 遵循 JSON Schema draft 2020-12
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ErrSchemaNotFound 接口不存在或当前用户不可见
+var ErrSchemaNotFound = errors.New("接口不存在或无权查看")
+
+// InterfaceSchema 接口请求 / 响应 JSON Schema 与示例报文，供客户端校验与 mock
+type InterfaceSchema struct {
+	Hash            string                 `json:"hash"`
+	APIClass        string                 `json:"api_class"`
+	Info            string                 `json:"info"`
+	Method          string                 `json:"method"`
+	Path            string                 `json:"path"`
+	Request         *JSONSchema            `json:"request"`
+	Response        *JSONSchema            `json:"response"`
+	RequestExample  map[string]interface{} `json:"request_example"`
+	ResponseExample map[string]interface{} `json:"response_example"`
+}
+
+// BuildInterfaceSchema 按字段定义生成请求 schema 与统一包装 {code,msg,data} 的响应 schema，
+// 示例报文按字段约束生成（与网关 mock 同源）
+func BuildInterfaceSchema(api model.AdminInterfaceList, req, resp []model.AdminField) *InterfaceSchema {
+	title := firstNonEmpty(api.Info, api.APIClass)
+	reqSchema := FieldsSchema(req)
+	reqSchema.Schema, reqSchema.ID, reqSchema.Title = jsonSchemaDialect, "urn:apiadmin:"+api.Hash+":request", title+" 请求参数"
+	respSchema := ResponseEnvelopeSchema(resp)
+	respSchema.Schema, respSchema.ID, respSchema.Title = jsonSchemaDialect, "urn:apiadmin:"+api.Hash+":response", title+" 响应"
+	return &InterfaceSchema{
+		Hash:            api.Hash,
+		APIClass:        api.APIClass,
+		Info:            api.Info,
+		Method:          InterfaceMethod(api.Method),
+		Path:            GatewayPath(api),
+		Request:         reqSchema,
+		Response:        respSchema,
+		RequestExample:  FieldsExample(req),
+		ResponseExample: map[string]interface{}{"code": 1, "msg": "success", "data": MockData(resp)},
+	}
+}
+
+// ResponseEnvelopeSchema 响应统一包装 {code,msg,data}，data 取响应字段（去掉批量上传生成的根字段 data）
+func ResponseEnvelopeSchema(defs []model.AdminField) *JSONSchema {
+	return &JSONSchema{Type: "object", Required: []string{"code", "msg"}, Properties: map[string]*JSONSchema{
+		"code": {Type: "integer", Description: "业务码，1 为成功，其余见 /wiki/errorCode"},
+		"msg":  {Type: "string"},
+		"data": FieldsSchema(ResponseDataFields(defs)),
+	}}
+}
+
+// Schema 后台查看接口 JSON Schema 与示例（不限状态）
+func (s *FieldsService) Schema(ctx context.Context, hash string) (*InterfaceSchema, error) {
+	if strings.TrimSpace(hash) == "" {
+		return nil, errors.New("hash required")
+	}
+	api, err := s.InterfaceDAO.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if api == nil {
+		return nil, ErrSchemaNotFound
+	}
+	req, err := s.RequestFields(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.ResponseFields(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return BuildInterfaceSchema(*api, req, resp), nil
+}
+
+// Schema wiki 用户可见的启用接口的 JSON Schema 与示例；后台登录（app_id=-1）可查看全部启用接口
+func (s *WikiService) Schema(ctx context.Context, user WikiUserInfo, hash string) (*InterfaceSchema, error) {
+	hash = strings.TrimSpace(hash)
+	if hash == "" {
+		return nil, errors.New("hash required")
+	}
+	allowed, _, err := s.visibleSet(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	api, err := s.ListDAO.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if api == nil || api.Status != 1 {
+		return nil, ErrSchemaNotFound
+	}
+	if allowed != nil {
+		if _, ok := allowed[api.GroupHash+"|"+api.Hash]; !ok {
+			return nil, ErrSchemaNotFound
+		}
+	}
+	req, err := s.FieldsDAO.ListByHashAndType(ctx, hash, 0)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.FieldsDAO.ListByHashAndType(ctx, hash, 1)
+	if err != nil {
+		return nil, err
+	}
+	return BuildInterfaceSchema(*api, req, resp), nil
+}
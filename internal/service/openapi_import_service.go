@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/repository/dao"
+
+	"gorm.io/gorm"
+)
+
+// 导入 OpenAPI / Swagger 文档：按 path+method 与现有接口比对（优先 x-api-hash，其次 api_class），
+// 生成新增 / 更新 / 冲突报告；dry_run 仅返回报告不落库。
+const (
+	ImportSkip      = "skip"      // 已存在且有差异：报告冲突，不修改（默认）
+	ImportUpdate    = "update"    // 合并：更新接口属性与文档中的字段，保留文档未出现的已有字段
+	ImportOverwrite = "overwrite" // 覆盖：字段以文档为准（删除文档未出现的字段）
+
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+	ImportActionConflict  = "conflict"
+	ImportActionSkip      = "skip"
+
+	fieldNameMax = 50
+	fieldTextMax = 500
+)
+
+type ImportService struct {
+	IfList *InterfaceListService
+	Fields *FieldsService
+	Groups *dao.AdminGroupDAO
+}
+
+func NewImportService(ifl *InterfaceListService, fields *FieldsService, g *dao.AdminGroupDAO) *ImportService {
+	return &ImportService{IfList: ifl, Fields: fields, Groups: g}
+}
+
+var (
+	ErrImportOnConflict = errors.New("on_conflict 取值应为 skip / update / overwrite")
+	ErrImportGroup      = errors.New("分组不存在")
+)
+
+// ImportOptions 导入参数
+type ImportOptions struct {
+	DryRun       bool
+	OnConflict   string
+	DefaultGroup string // 文档未声明 tag 时使用的分组 hash
+	Status       int8   // 新建接口状态
+}
+
+// DefinitionChange 接口定义单项变更（导入比对、修订 diff 共用）
+type DefinitionChange struct {
+	Scope string `json:"scope"` // interface / request / response
+	Name  string `json:"name"`
+	Op    string `json:"op"` // add / remove / change
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// ImportItem 单个接口的比对结果
+type ImportItem struct {
+	Path     string             `json:"path"`
+	Method   string             `json:"method"`
+	APIClass string             `json:"api_class"`
+	Hash     string             `json:"hash,omitempty"`
+	Group    string             `json:"group,omitempty"`
+	Action   string             `json:"action"`
+	Reason   string             `json:"reason,omitempty"`
+	Changes  []DefinitionChange `json:"changes,omitempty"`
+}
+
+// ImportGroup 分组处理结果
+type ImportGroup struct {
+	Name   string `json:"name"`
+	Hash   string `json:"hash,omitempty"`
+	Action string `json:"action"` // create / exists
+}
+
+// ImportReport 导入报告
+type ImportReport struct {
+	DryRun   bool           `json:"dry_run"`
+	Format   string         `json:"format"`
+	Groups   []ImportGroup  `json:"groups"`
+	Items    []ImportItem   `json:"items"`
+	Summary  map[string]int `json:"summary"`
+	Warnings []string       `json:"warnings"`
+}
+
+// Import 解析并导入文档
+func (s *ImportService) Import(ctx context.Context, raw []byte, opt ImportOptions) (*ImportReport, error) {
+	switch opt.OnConflict {
+	case "":
+		opt.OnConflict = ImportSkip
+	case ImportSkip, ImportUpdate, ImportOverwrite:
+	default:
+		return nil, ErrImportOnConflict
+	}
+	if opt.DefaultGroup != "" {
+		if g, err := s.Groups.FindByHash(ctx, opt.DefaultGroup); err != nil {
+			return nil, err
+		} else if g == nil {
+			return nil, ErrImportGroup
+		}
+	}
+	spec, err := ParseSpec(raw)
+	if err != nil {
+		return nil, err
+	}
+	rep := &ImportReport{DryRun: opt.DryRun, Format: spec.Format, Groups: []ImportGroup{}, Items: []ImportItem{}, Summary: map[string]int{}, Warnings: spec.Warnings}
+	if rep.Warnings == nil {
+		rep.Warnings = []string{}
+	}
+	groups := map[string]string{} // tag 名 -> group hash
+	seen := map[string]string{}   // api_class(小写) -> path
+	for _, it := range spec.Interfaces {
+		item := ImportItem{Path: it.Path, Method: InterfaceMethod(it.Method), APIClass: it.APIClass, Group: it.Group.Name}
+		if it.APIClass == "" {
+			item.Action, item.Reason = ImportActionSkip, "api_class 为空"
+			rep.add(item)
+			continue
+		}
+		if prev, dup := seen[strings.ToLower(it.APIClass)]; dup {
+			item.Action, item.Reason = ImportActionSkip, "api_class 与 "+prev+" 重复"
+			rep.add(item)
+			continue
+		}
+		seen[strings.ToLower(it.APIClass)] = it.Path
+		req, rw := sanitizeFields(it.Path, it.Request)
+		resp, ww := sanitizeFields(it.Path, it.Response)
+		rep.Warnings = append(rep.Warnings, append(rw, ww...)...)
+		it.Request, it.Response = req, resp
+
+		groupHash := opt.DefaultGroup
+		if it.Group.Name != "" {
+			gh, ok := groups[it.Group.Name]
+			if !ok {
+				if gh, err = s.resolveGroup(ctx, it.Group, opt.DryRun, rep); err != nil {
+					return nil, err
+				}
+				groups[it.Group.Name] = gh
+			}
+			groupHash = gh
+		}
+		existing, err := s.findExisting(ctx, it)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			item.Action = ImportActionCreate
+			if !opt.DryRun {
+				if item.Hash, err = s.create(ctx, it, groupHash, opt.Status); err != nil {
+					return nil, err
+				}
+			}
+			rep.add(item)
+			continue
+		}
+		item.Hash = existing.Hash
+		if existing.Method != 0 && it.Method != 0 && existing.Method != it.Method {
+			item.Action, item.Reason = ImportActionConflict, "api_class 已存在且方法不同（"+InterfaceMethod(existing.Method)+"）"
+			rep.add(item)
+			continue
+		}
+		if !strings.EqualFold(existing.APIClass, it.APIClass) { // x-api-hash 命中但路径已变更
+			if taken, err := s.IfList.DAO.ExistsAPIClass(ctx, it.APIClass, existing.ID); err != nil {
+				return nil, err
+			} else if taken {
+				item.Action, item.Reason = ImportActionConflict, "api_class 已被其他接口占用"
+				rep.add(item)
+				continue
+			}
+		}
+		oldReq, err := s.Fields.DAO.ListByHashAndType(ctx, existing.Hash, 0)
+		if err != nil {
+			return nil, err
+		}
+		oldResp, err := s.Fields.DAO.ListByHashAndType(ctx, existing.Hash, 1)
+		if err != nil {
+			return nil, err
+		}
+		overwrite := opt.OnConflict == ImportOverwrite
+		cols := interfaceChanges(existing, it, groupHash, &item.Changes)
+		newReq := mergeFields("request", oldReq, it.Request, overwrite, &item.Changes)
+		newResp := mergeFields("response", oldResp, it.Response, overwrite, &item.Changes)
+		switch {
+		case len(item.Changes) == 0:
+			item.Action = ImportActionUnchanged
+		case opt.OnConflict == ImportSkip:
+			item.Action, item.Reason = ImportActionConflict, "已存在且有差异（on_conflict=skip）"
+		default:
+			item.Action = ImportActionUpdate
+			if !opt.DryRun {
+				if err := s.update(ctx, existing, cols, newReq, newResp); err != nil {
+					return nil, err
+				}
+			}
+		}
+		rep.add(item)
+	}
+	return rep, nil
+}
+
+func (r *ImportReport) add(item ImportItem) {
+	r.Items = append(r.Items, item)
+	r.Summary[item.Action]++
+}
+
+// findExisting 先按 x-api-hash（本系统导出）匹配，再按 api_class
+func (s *ImportService) findExisting(ctx context.Context, it SpecInterface) (*model.AdminInterfaceList, error) {
+	if it.Hash != "" {
+		if m, err := s.IfList.DAO.FindByHash(ctx, it.Hash); err != nil || m != nil {
+			return m, err
+		}
+	}
+	return s.IfList.DAO.FindByAPIClass(ctx, it.APIClass)
+}
+
+// resolveGroup 按 x-group-hash、名称查找分组，不存在则新建（dry_run 不落库）
+func (s *ImportService) resolveGroup(ctx context.Context, g SpecGroup, dryRun bool, rep *ImportReport) (string, error) {
+	if g.Hash != "" {
+		if m, err := s.Groups.FindByHash(ctx, g.Hash); err != nil {
+			return "", err
+		} else if m != nil {
+			rep.Groups = append(rep.Groups, ImportGroup{Name: g.Name, Hash: m.Hash, Action: "exists"})
+			return m.Hash, nil
+		}
+	}
+	m, err := s.Groups.FindByName(ctx, g.Name)
+	if err != nil {
+		return "", err
+	}
+	if m != nil {
+		rep.Groups = append(rep.Groups, ImportGroup{Name: g.Name, Hash: m.Hash, Action: "exists"})
+		return m.Hash, nil
+	}
+	out := ImportGroup{Name: g.Name, Action: "create"}
+	if !dryRun {
+		now := time.Now().Unix()
+		ng := &model.AdminGroup{Name: g.Name, Description: g.Description, Status: 1, Hash: generateShortHash(g.Name + time.Now().Format(time.RFC3339Nano)), CreateTime: now, UpdateTime: now}
+		if err := s.Groups.Create(ctx, ng); err != nil {
+			return "", err
+		}
+		out.Hash = ng.Hash
+	}
+	rep.Groups = append(rep.Groups, out)
+	return out.Hash, nil
+}
+
+func (s *ImportService) create(ctx context.Context, it SpecInterface, groupHash string, status int8) (string, error) {
+	m := &model.AdminInterfaceList{APIClass: it.APIClass, Hash: newInterfaceHash(it.APIClass), AccessToken: it.AccessToken, Status: status, Method: it.Method, Info: truncateRunes(it.Info, fieldTextMax), ReturnStr: it.Example, GroupHash: groupHash}
+	err := s.IfList.DAO.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.IfList.DAO.WithTx(tx).Create(ctx, m); err != nil {
+			return err
+		}
+		return s.Fields.replaceTx(ctx, tx, m.Hash, it.Request, it.Response)
+	})
+	if err != nil {
+		return "", err
+	}
+	s.IfList.invalidateOne(m.ID, m.Hash, m.APIClass)
+	s.afterReplace(ctx, m.Hash)
+	return m.Hash, nil
+}
+
+func (s *ImportService) update(ctx context.Context, m *model.AdminInterfaceList, cols map[string]interface{}, req, resp []model.AdminField) error {
+	s.IfList.Revisions.Baseline(ctx, m.Hash)
+	// 接口列与字段同一事务写入，中途失败不留下半截导入
+	err := s.IfList.DAO.DB.Transaction(func(tx *gorm.DB) error {
+		if len(cols) > 0 {
+			if err := s.IfList.DAO.WithTx(tx).UpdateColumns(ctx, m.ID, cols); err != nil {
+				return err
+			}
+		}
+		return s.Fields.replaceTx(ctx, tx, m.Hash, req, resp)
+	})
+	if err != nil {
+		return err
+	}
+	if len(cols) > 0 {
+		classes := []string{m.APIClass}
+		if c, ok := cols["api_class"].(string); ok {
+			classes = append(classes, c)
+		}
+		s.IfList.invalidateOne(m.ID, m.Hash, classes...)
+	}
+	s.afterReplace(ctx, m.Hash)
+	return nil
+}
+
+// afterReplace 事务提交后失效字段缓存、刷新搜索索引并记录修订
+func (s *ImportService) afterReplace(ctx context.Context, hash string) {
+	s.Fields.invalidateHash(hash)
+	s.IfList.Search.Touch(ctx, hash) // 接口与字段均已落库，一次性刷新搜索索引
+	s.IfList.Revisions.Record(ctx, hash, RevisionImport)
+}
+
+// interfaceChanges 比对接口属性，返回需要更新的列；文档缺失的说明 / 示例不视为变更
+func interfaceChanges(m *model.AdminInterfaceList, it SpecInterface, groupHash string, changes *[]DefinitionChange) map[string]interface{} {
+	cols := map[string]interface{}{}
+	diff := func(name, col, from, to string, val interface{}) {
+		if from != to {
+			*changes = append(*changes, DefinitionChange{Scope: "interface", Name: name, Op: "change", From: from, To: to})
+			cols[col] = val
+		}
+	}
+	if !strings.EqualFold(m.APIClass, it.APIClass) {
+		diff("api_class", "api_class", m.APIClass, it.APIClass, it.APIClass)
+	}
+	if it.Info != "" {
+		info := truncateRunes(it.Info, fieldTextMax)
+		diff("info", "info", m.Info, info, info)
+	}
+	diff("method", "method", InterfaceMethod(m.Method), InterfaceMethod(it.Method), it.Method)
+	diff("access_token", "access_token", fmt.Sprint(m.AccessToken), fmt.Sprint(it.AccessToken), it.AccessToken)
+	if groupHash != "" {
+		diff("group_hash", "group_hash", m.GroupHash, groupHash, groupHash)
+	}
+	if it.Example != "" {
+		diff("return_str", "return_str", m.ReturnStr, it.Example, it.Example)
+	}
+	return cols
+}
+
+// mergeFields 比对字段并返回导入后的字段列表。合并模式保留旧字段的展示名，文档说明为空时保留旧说明。
+func mergeFields(scope string, old, incoming []model.AdminField, overwrite bool, changes *[]DefinitionChange) []model.AdminField {
+	byName := make(map[string]model.AdminField, len(old))
+	for _, f := range old {
+		byName[FieldPath(f)] = f
+	}
+	in := make(map[string]bool, len(incoming))
+	out := make([]model.AdminField, 0, len(incoming)+len(old))
+	for _, f := range incoming {
+		in[FieldPath(f)] = true
+		prev, ok := byName[FieldPath(f)]
+		if !ok {
+			*changes = append(*changes, DefinitionChange{Scope: scope, Name: FieldPath(f), Op: "add", To: fieldSignature(f)})
+			out = append(out, f)
+			continue
+		}
+		if !overwrite {
+			f.ShowName = prev.ShowName
+		}
+		if f.Info == "" {
+			f.Info = prev.Info
+		}
+		if a, b := fieldSignature(prev), fieldSignature(f); a != b || prev.Info != f.Info {
+			*changes = append(*changes, DefinitionChange{Scope: scope, Name: FieldPath(f), Op: "change", From: a, To: b})
+		}
+		out = append(out, f)
+	}
+	for _, f := range old {
+		if in[FieldPath(f)] {
+			continue
+		}
+		if overwrite {
+			*changes = append(*changes, DefinitionChange{Scope: scope, Name: FieldPath(f), Op: "remove", From: fieldSignature(f)})
+		} else {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// fieldSignature 字段比对摘要：类型 / 必填 / 默认值 / 范围
+func fieldSignature(f model.AdminField) string {
+	sig := dataTypeMap[int(f.DataType)]
+	if f.IsMust == 1 {
+		sig += ",required"
+	}
+	if f.Default != "" {
+		sig += ",default=" + f.Default
+	}
+	if f.Range != "" {
+		sig += ",range=" + f.Range
+	}
+	return sig
+}
+
+// sanitizeFields 按列宽截断：字段名超长无法保存则丢弃并告警
+func sanitizeFields(path string, list []model.AdminField) ([]model.AdminField, []string) {
+	var warns []string
+	out := list[:0]
+	for _, f := range list {
+		if utf8.RuneCountInString(f.FieldName) > fieldNameMax {
+			warns = append(warns, path+": 字段名过长已忽略 "+f.FieldName)
+			continue
+		}
+		if len(FieldPath(f)) > fieldPathMax {
+			warns = append(warns, path+": 字段路径过长已忽略 "+FieldPath(f))
+			continue
+		}
+		f.ShowName = truncateRunes(f.ShowName, fieldNameMax)
+		f.Info = truncateRunes(f.Info, fieldTextMax)
+		f.Default = truncateRunes(f.Default, fieldTextMax)
+		f.Range = truncateRunes(f.Range, fieldTextMax)
+		out = append(out, f)
+	}
+	return out, warns
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,549 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-apiadmin/internal/domain/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 解析 OpenAPI 3.x / Swagger 2.0（JSON 或 YAML）为接口与字段定义，供导入比对。
+// 仅处理本地 $ref（#/...），allOf 合并属性，oneOf/anyOf 取第一项；嵌套字段按 "." 拼接名称（与导出一致）。
+
+const specMaxDepth = 8
+
+// SpecGroup 文档中的分组（tag）
+type SpecGroup struct {
+	Name        string
+	Description string
+	Hash        string // x-group-hash（由本系统导出时携带）
+}
+
+// SpecInterface 文档中的一个接口（同一路径多个方法合并为一个接口）
+type SpecInterface struct {
+	Path        string
+	Methods     []string
+	APIClass    string
+	Hash        string // x-api-hash
+	Info        string
+	Method      int8 // 0 不限 1 POST 2 GET
+	AccessToken int8
+	Group       SpecGroup
+	Example     string // 响应示例（写入 return_str）
+	Request     []model.AdminField
+	Response    []model.AdminField
+}
+
+// ParsedSpec 解析结果
+type ParsedSpec struct {
+	Format     string // 如 "openapi 3.1.0" / "swagger 2.0"
+	Interfaces []SpecInterface
+	Warnings   []string
+}
+
+var ErrSpecFormat = errors.New("无法识别的文档：需为 OpenAPI 3.x 或 Swagger 2.0")
+
+type specParser struct {
+	doc      map[string]interface{}
+	swagger2 bool
+	warnings []string
+}
+
+var specMethods = []string{"get", "post", "put", "delete", "patch", "head", "options"}
+
+// ParseSpec 解析文档
+func ParseSpec(raw []byte) (*ParsedSpec, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSpecFormat, err)
+	}
+	p := &specParser{doc: doc}
+	out := &ParsedSpec{}
+	if v, ok := doc["openapi"].(string); ok && strings.HasPrefix(v, "3.") {
+		out.Format = "openapi " + v
+	} else if v := fmt.Sprint(doc["swagger"]); v == "2.0" || v == "2" {
+		p.swagger2 = true
+		out.Format = "swagger 2.0"
+	} else {
+		return nil, ErrSpecFormat
+	}
+	tags := map[string]SpecGroup{}
+	for _, t := range asList(doc["tags"]) {
+		tm := asMap(t)
+		g := SpecGroup{Name: asString(tm["name"]), Description: asString(tm["description"]), Hash: asString(tm["x-group-hash"])}
+		if g.Name != "" {
+			tags[g.Name] = g
+		}
+	}
+	paths := asMap(doc["paths"])
+	keys := make([]string, 0, len(paths))
+	for k := range paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, path := range keys {
+		item := p.resolve(asMap(paths[path]), nil)
+		if it, ok := p.buildInterface(path, item, tags); ok {
+			out.Interfaces = append(out.Interfaces, it)
+		}
+	}
+	out.Warnings = p.warnings
+	return out, nil
+}
+
+func (p *specParser) warn(format string, args ...interface{}) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+// buildInterface 合并同一路径下的操作：单方法映射为 GET/POST，多方法或其他方法为不限
+func (p *specParser) buildInterface(path string, item map[string]interface{}, tags map[string]SpecGroup) (SpecInterface, bool) {
+	it := SpecInterface{Path: path, APIClass: specAPIClass(path)}
+	if strings.Contains(path, "{") {
+		p.warn("%s: 网关不支持路径参数，按字面量作为 api_class", path)
+	}
+	shared := asList(item["parameters"])
+	seenReq := map[string]bool{}
+	for _, m := range specMethods {
+		op := asMap(item[m])
+		if op == nil {
+			continue
+		}
+		it.Methods = append(it.Methods, strings.ToUpper(m))
+		if it.Info == "" {
+			it.Info = firstNonEmpty(asString(op["summary"]), asString(op["description"]), asString(op["operationId"]))
+		}
+		if it.Hash == "" { // 本系统导出的文档携带 x-api-hash / x-api-class，路径为 /api/{hash}
+			it.Hash = asString(op["x-api-hash"])
+			if cls := asString(op["x-api-class"]); cls != "" {
+				it.APIClass = cls
+			}
+		}
+		if it.Group.Name == "" {
+			if tl := asList(op["tags"]); len(tl) > 0 {
+				name := asString(tl[0])
+				g, ok := tags[name]
+				if !ok {
+					g = SpecGroup{Name: name}
+				}
+				it.Group = g
+			}
+		}
+		if p.secured(op) {
+			it.AccessToken = 1
+		}
+		for _, f := range p.requestFields(path, op, shared) {
+			if !seenReq[FieldPath(f)] {
+				seenReq[FieldPath(f)] = true
+				it.Request = append(it.Request, f)
+			}
+		}
+		if it.Response == nil {
+			it.Response, it.Example = p.responseFields(op)
+		}
+	}
+	if len(it.Methods) == 0 {
+		return it, false
+	}
+	switch {
+	case len(it.Methods) == 1 && it.Methods[0] == "GET":
+		it.Method = 2
+	case len(it.Methods) == 1 && it.Methods[0] == "POST":
+		it.Method = 1
+	default:
+		it.Method = 0
+		if len(it.Methods) > 1 {
+			p.warn("%s: 多个方法 %s 合并为一个不限方法的接口，请求字段取并集", path, strings.Join(it.Methods, "/"))
+		} else {
+			p.warn("%s: 方法 %s 映射为不限方法", path, it.Methods[0])
+		}
+	}
+	return it, true
+}
+
+// specAPIClass 路径转 api_class：去掉首尾 "/" 及本系统导出的 /api/ 前缀
+func specAPIClass(path string) string {
+	cls := strings.Trim(path, "/")
+	cls = strings.TrimPrefix(cls, "api/")
+	return cls
+}
+
+// secured 操作（或全局）声明了安全要求即视为需要 access_token
+func (p *specParser) secured(op map[string]interface{}) bool {
+	if sec, ok := op["security"]; ok {
+		return len(asList(sec)) > 0
+	}
+	return len(asList(p.doc["security"])) > 0
+}
+
+func (p *specParser) requestFields(path string, op map[string]interface{}, shared []interface{}) []model.AdminField {
+	var out []model.AdminField
+	params := append(append([]interface{}{}, shared...), asList(op["parameters"])...)
+	for _, raw := range params {
+		prm := p.resolve(asMap(raw), nil)
+		name, in := asString(prm["name"]), asString(prm["in"])
+		switch in {
+		case "query", "formData", "path":
+		case "body": // swagger 2 请求体
+			out = append(out, p.children("", asMap(prm["schema"]), 0, nil)...)
+			continue
+		default: // header / cookie（鉴权头等）不导入
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		sc := asMap(prm["schema"])
+		if p.swagger2 || sc == nil {
+			sc = prm
+		}
+		sc = p.resolve(sc, nil)
+		f := p.field(name, sc, asBool(prm["required"]) || in == "path")
+		if f.Info == "" {
+			f.Info = asString(prm["description"])
+		}
+		out = append(out, f)
+		out = append(out, p.children(name, sc, 0, nil)...)
+	}
+	if body := p.resolve(asMap(op["requestBody"]), nil); body != nil {
+		if sc := pickContent(asMap(body["content"])); sc != nil {
+			out = append(out, p.children("", asMap(sc["schema"]), 0, nil)...)
+		}
+	}
+	return out
+}
+
+// responseFields 取 200/201/default/首个 2xx 响应；{code,msg,data} 包装时取 data
+func (p *specParser) responseFields(op map[string]interface{}) ([]model.AdminField, string) {
+	resps := asMap(op["responses"])
+	var resp map[string]interface{}
+	for _, code := range []string{"200", "201", "default"} {
+		if r := asMap(resps[code]); r != nil {
+			resp = r
+			break
+		}
+	}
+	if resp == nil {
+		codes := make([]string, 0, len(resps))
+		for k := range resps {
+			if strings.HasPrefix(k, "2") {
+				codes = append(codes, k)
+			}
+		}
+		sort.Strings(codes)
+		if len(codes) > 0 {
+			resp = asMap(resps[codes[0]])
+		}
+	}
+	resp = p.resolve(resp, nil)
+	if resp == nil {
+		return nil, ""
+	}
+	var schema interface{}
+	var example interface{}
+	if p.swagger2 {
+		schema = resp["schema"]
+		if ex := asMap(resp["examples"]); ex != nil {
+			example = ex["application/json"]
+		}
+	} else if mt := pickContent(asMap(resp["content"])); mt != nil {
+		schema, example = mt["schema"], mt["example"]
+	}
+	raw := asMap(schema)
+	if props := asMap(p.resolve(raw, nil)["properties"]); props != nil && props["code"] != nil && props["data"] != nil {
+		raw = asMap(props["data"])
+	}
+	exStr := ""
+	if example != nil {
+		if b, err := json.Marshal(normalizeYAML(example)); err == nil {
+			exStr = string(b)
+		}
+	}
+	return p.children("", raw, 0, nil), exStr
+}
+
+// pickContent 按 json > multipart > form > 其他 选择媒体类型
+func pickContent(content map[string]interface{}) map[string]interface{} {
+	for _, mt := range []string{"application/json", "multipart/form-data", "application/x-www-form-urlencoded"} {
+		if v := asMap(content[mt]); v != nil {
+			return v
+		}
+	}
+	keys := make([]string, 0, len(content))
+	for k := range content {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		return asMap(content[keys[0]])
+	}
+	return nil
+}
+
+// children 展开 object 属性（或数组元素的属性），路径以 prefix. 拼接（数组元素为 prefix[].）。
+// raw 为未解析的 schema；chain 记录当前展开链上的 $ref，递归结构展开到再次引用自身为止。
+func (p *specParser) children(prefix string, raw map[string]interface{}, depth int, chain map[string]bool) []model.AdminField {
+	if raw == nil || depth >= specMaxDepth {
+		return nil
+	}
+	sc, chain, ok := p.enter(raw, chain)
+	if !ok {
+		return nil
+	}
+	if schemaType(sc) == "array" {
+		if sc, chain, ok = p.enter(asMap(sc["items"]), chain); !ok {
+			return nil
+		}
+		if prefix != "" {
+			prefix += fieldItemToken
+		}
+	}
+	props := asMap(sc["properties"])
+	if len(props) == 0 {
+		return nil
+	}
+	required := map[string]bool{}
+	for _, r := range asList(sc["required"]) {
+		required[asString(r)] = true
+	}
+	names := make([]string, 0, len(props))
+	for k := range props {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var out []model.AdminField
+	for _, k := range names {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+		childRaw := asMap(props[k])
+		out = append(out, p.field(name, p.resolve(childRaw, nil), required[k]))
+		out = append(out, p.children(name, childRaw, depth+1, chain)...)
+	}
+	return out
+}
+
+// enter 解析 schema 并把其 $ref 加入展开链；已在链上（循环引用）时返回 false
+func (p *specParser) enter(raw map[string]interface{}, chain map[string]bool) (map[string]interface{}, map[string]bool, bool) {
+	if raw == nil {
+		return nil, chain, false
+	}
+	if ref := asString(raw["$ref"]); ref != "" {
+		if chain[ref] {
+			return nil, chain, false
+		}
+		next := map[string]bool{ref: true}
+		for k := range chain {
+			next[k] = true
+		}
+		chain = next
+	}
+	sc := p.resolve(raw, nil)
+	return sc, chain, sc != nil
+}
+
+// field schema -> 字段定义；name 为完整路径，字段名取末段
+func (p *specParser) field(name string, sc map[string]interface{}, required bool) model.AdminField {
+	leaf := pathLeaf(name)
+	f := model.AdminField{FieldName: leaf, Path: name, ShowName: leaf, DataType: DataTypeString}
+	if required {
+		f.IsMust = 1
+	}
+	f.Info = firstNonEmpty(asString(sc["description"]), asString(sc["title"]))
+	rg := map[string]interface{}{}
+	setRange := func(minKey, maxKey string) {
+		if v, ok := numberOf(sc[minKey]); ok {
+			rg["min"] = v
+		}
+		if v, ok := numberOf(sc[maxKey]); ok {
+			rg["max"] = v
+		}
+	}
+	typ := schemaType(sc)
+	switch {
+	case asList(sc["enum"]) != nil && typ != "boolean":
+		f.DataType = DataTypeEnum
+		if b, err := json.Marshal(normalizeYAML(sc["enum"])); err == nil {
+			f.Range = string(b)
+		}
+	case typ == "integer":
+		f.DataType = DataTypeInteger
+		setRange("minimum", "maximum")
+	case typ == "number":
+		f.DataType = DataTypeFloat
+		setRange("minimum", "maximum")
+	case typ == "boolean":
+		f.DataType = DataTypeBoolean
+	case typ == "file", typ == "string" && (asString(sc["format"]) == "binary" || asString(sc["contentMediaType"]) != ""):
+		f.DataType = DataTypeFile
+	case typ == "string" && asString(sc["pattern"]) == mobileRe.String():
+		f.DataType = DataTypeMobile
+	case typ == "array":
+		f.DataType = DataTypeArray
+		setRange("minItems", "maxItems")
+	case typ == "object":
+		f.DataType = DataTypeObject
+	default:
+		setRange("minLength", "maxLength")
+	}
+	if len(rg) > 0 {
+		b, _ := json.Marshal(rg)
+		f.Range = string(b)
+	}
+	if d, ok := sc["default"]; ok && d != nil {
+		if s, ok := d.(string); ok {
+			f.Default = s
+		} else if b, err := json.Marshal(normalizeYAML(d)); err == nil {
+			f.Default = string(b)
+		}
+	}
+	return f
+}
+
+// resolve 展开本地 $ref 与 allOf/oneOf/anyOf；seen 防止循环引用
+func (p *specParser) resolve(sc map[string]interface{}, seen map[string]bool) map[string]interface{} {
+	if sc == nil {
+		return nil
+	}
+	if ref := asString(sc["$ref"]); ref != "" {
+		if seen[ref] || !strings.HasPrefix(ref, "#/") {
+			if !strings.HasPrefix(ref, "#/") {
+				p.warn("不支持外部引用 %s", ref)
+			}
+			return map[string]interface{}{"type": "object"}
+		}
+		next := map[string]bool{ref: true}
+		for k := range seen {
+			next[k] = true
+		}
+		var cur interface{} = p.doc
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			cur = asMap(cur)[part]
+		}
+		return p.resolve(asMap(cur), next)
+	}
+	if all := asList(sc["allOf"]); len(all) > 0 {
+		merged := map[string]interface{}{"type": "object"}
+		props := map[string]interface{}{}
+		var required []interface{}
+		for k, v := range sc {
+			if k != "allOf" {
+				merged[k] = v
+			}
+		}
+		for _, part := range all {
+			ps := p.resolve(asMap(part), seen)
+			for k, v := range asMap(ps["properties"]) {
+				props[k] = v
+			}
+			required = append(required, asList(ps["required"])...)
+			if merged["description"] == nil && ps["description"] != nil {
+				merged["description"] = ps["description"]
+			}
+		}
+		for k, v := range asMap(sc["properties"]) {
+			props[k] = v
+		}
+		merged["properties"], merged["required"] = props, append(required, asList(sc["required"])...)
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if alt := asList(sc[key]); len(alt) > 0 {
+			return p.resolve(asMap(alt[0]), seen)
+		}
+	}
+	return sc
+}
+
+// schemaType type 可为字符串或数组（3.1，如 ["string","null"]）；无 type 但有 properties 视为 object
+func schemaType(sc map[string]interface{}) string {
+	switch t := sc["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if s := asString(v); s != "" && s != "null" {
+				return s
+			}
+		}
+	}
+	if sc["properties"] != nil {
+		return "object"
+	}
+	if sc["items"] != nil {
+		return "array"
+	}
+	return ""
+}
+
+// normalizeYAML 将 yaml 解出的 map[interface{}]interface{} 转为可 JSON 编码的结构
+func normalizeYAML(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, vv := range t {
+			m[fmt.Sprint(k)] = normalizeYAML(vv)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, vv := range t {
+			m[k] = normalizeYAML(vv)
+		}
+		return m
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, vv := range t {
+			out[i] = normalizeYAML(vv)
+		}
+		return out
+	}
+	return v
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return t
+	case map[interface{}]interface{}:
+		return normalizeYAML(t).(map[string]interface{})
+	}
+	return nil
+}
+
+func asList(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func asString(v interface{}) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
+
+func asBool(v interface{}) bool {
+	b, _ := v.(bool)
+	return b
+}
+
+func numberOf(v interface{}) (float64, bool) {
+	if _, ok := v.(string); ok {
+		return 0, false
+	}
+	return toFloat(v)
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
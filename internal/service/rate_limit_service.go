@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/pkg/cache"
+	"go-apiadmin/internal/repository/dao"
+	redisrepo "go-apiadmin/internal/repository/redis"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitService 网关限流与配额：令牌桶限速 + 日/月调用配额，计数均存 Redis。
+// 规则作用域：应用(app)、接口(api)、应用+接口(app_api)，一次调用同时受所有命中规则约束。
+type RateLimitService struct {
+	DAO   *dao.AdminRateLimitDAO
+	Redis *redisrepo.Client
+	Cache cache.Cache // 启用规则整体缓存（规则量小）
+}
+
+const (
+	LimitScopeApp    = "app"
+	LimitScopeAPI    = "api"
+	LimitScopeAppAPI = "app_api"
+
+	LimitReasonRate    = "rate"
+	LimitReasonDaily   = "daily"
+	LimitReasonMonthly = "monthly"
+
+	rateLimitRulesKey = "ratelimit:rules"
+)
+
+// LimitResult 限流判定结果；Allowed=false 时 Reason/Scope 指明触发的规则
+type LimitResult struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+	Scope   string `json:"scope"`
+	RuleID  int64  `json:"rule_id"`
+}
+
+// tokenBucketScript 令牌桶：按毫秒补充令牌，返回 1 放行 / 0 拒绝
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil then tokens = burst; ts = now end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed = 0
+if tokens >= 1 then tokens = tokens - 1; allowed = 1 end
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return allowed
+`)
+
+// quotaScript 配额计数：超出上限回退并返回 -1，否则返回当前计数
+var quotaScript = redis.NewScript(`
+local v = redis.call('INCR', KEYS[1])
+if v == 1 then redis.call('EXPIRE', KEYS[1], tonumber(ARGV[2])) end
+local limit = tonumber(ARGV[1])
+if limit > 0 and v > limit then
+  redis.call('DECR', KEYS[1])
+  return -1
+end
+return v
+`)
+
+func NewRateLimitService(d *dao.AdminRateLimitDAO, r *redisrepo.Client, c cache.Cache) *RateLimitService {
+	return &RateLimitService{DAO: d, Redis: r, Cache: c}
+}
+
+// LimitScope 规则作用域及其计数 key 片段
+func LimitScope(appID, hash string) (scope, key string) {
+	switch {
+	case appID != "" && hash != "":
+		return LimitScopeAppAPI, LimitScopeAppAPI + ":" + appID + ":" + hash
+	case appID != "":
+		return LimitScopeApp, LimitScopeApp + ":" + appID
+	default:
+		return LimitScopeAPI, LimitScopeAPI + ":" + hash
+	}
+}
+
+func quotaKeys(scopeKey string, now time.Time) (daily, monthly string) {
+	return "rl:qd:" + scopeKey + ":" + now.Format("20060102"), "rl:qm:" + scopeKey + ":" + now.Format("200601")
+}
+
+// Allow 判定一次调用：先按全部命中规则限速，再累加日/月配额（任一超限则回退已累加计数）
+func (s *RateLimitService) Allow(ctx context.Context, appID, hash string) (*LimitResult, error) {
+	rules, err := s.Match(ctx, appID, hash)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, r := range rules {
+		if r.Rate <= 0 {
+			continue
+		}
+		burst := r.Burst
+		if burst <= 0 {
+			burst = r.Rate
+		}
+		scope, sk := LimitScope(r.AppID, r.Hash)
+		ok, err := tokenBucketScript.Run(ctx, s.Redis.Client, []string{"rl:tb:" + sk}, r.Rate, burst, now.UnixMilli()).Int()
+		if err != nil {
+			return nil, err
+		}
+		if ok != 1 {
+			return &LimitResult{Reason: LimitReasonRate, Scope: scope, RuleID: r.ID}, nil
+		}
+	}
+	var counted []string
+	rollback := func() {
+		for _, k := range counted {
+			_ = s.Redis.Client.Decr(ctx, k).Err()
+		}
+	}
+	for _, r := range rules {
+		scope, sk := LimitScope(r.AppID, r.Hash)
+		dk, mk := quotaKeys(sk, now)
+		for _, q := range []struct {
+			key    string
+			limit  int64
+			ttl    time.Duration
+			reason string
+		}{{dk, r.DailyQuota, 48 * time.Hour, LimitReasonDaily}, {mk, r.MonthlyQuota, 32 * 24 * time.Hour, LimitReasonMonthly}} {
+			v, err := quotaScript.Run(ctx, s.Redis.Client, []string{q.key}, q.limit, int64(q.ttl/time.Second)).Int64()
+			if err != nil {
+				rollback()
+				return nil, err
+			}
+			if v < 0 {
+				rollback()
+				return &LimitResult{Reason: q.reason, Scope: scope, RuleID: r.ID}, nil
+			}
+			counted = append(counted, q.key)
+		}
+	}
+	return &LimitResult{Allowed: true}, nil
+}
+
+// Match 返回本次调用命中的启用规则（应用级 / 接口级 / 应用+接口级）
+func (s *RateLimitService) Match(ctx context.Context, appID, hash string) ([]model.AdminRateLimit, error) {
+	all, err := s.activeRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []model.AdminRateLimit
+	for _, r := range all {
+		if (r.AppID == "" || r.AppID == appID) && (r.Hash == "" || r.Hash == hash) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (s *RateLimitService) activeRules(ctx context.Context) ([]model.AdminRateLimit, error) {
+	if s.Cache != nil {
+		if v, _ := s.Cache.Get(ctx, rateLimitRulesKey); v != "" {
+			var list []model.AdminRateLimit
+			if json.Unmarshal([]byte(v), &list) == nil {
+				return list, nil
+			}
+		}
+	}
+	list, err := s.DAO.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.Cache != nil {
+		b, _ := json.Marshal(list)
+		_ = s.Cache.SetEX(ctx, rateLimitRulesKey, string(b), 30*time.Second)
+	}
+	return list, nil
+}
+
+// ===== 后台管理 =====
+
+type ListRateLimitResult struct {
+	List  []model.AdminRateLimit `json:"list"`
+	Count int64                  `json:"count"`
+}
+
+func (s *RateLimitService) List(ctx context.Context, appID, hash string, page, limit int) (*ListRateLimitResult, error) {
+	list, total, err := s.DAO.List(ctx, appID, hash, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &ListRateLimitResult{List: list, Count: total}, nil
+}
+
+type SaveRateLimitParams struct {
+	ID           int64
+	AppID        string
+	Hash         string
+	Rate         int
+	Burst        int
+	DailyQuota   int64
+	MonthlyQuota int64
+	Status       *int8 // 新增时缺省为 1；编辑时未传保持原值
+}
+
+func (s *RateLimitService) Add(ctx context.Context, p SaveRateLimitParams) (int64, error) {
+	p.AppID, p.Hash = strings.TrimSpace(p.AppID), strings.TrimSpace(p.Hash)
+	if p.AppID == "" && p.Hash == "" {
+		return 0, errors.New("app_id或hash至少填写一项")
+	}
+	if err := checkLimitValues(p); err != nil {
+		return 0, err
+	}
+	exist, err := s.DAO.FindByScope(ctx, p.AppID, p.Hash)
+	if err != nil {
+		return 0, err
+	}
+	if exist != nil {
+		return 0, errors.New("该作用域规则已存在")
+	}
+	status := int8(1)
+	if p.Status != nil {
+		status = *p.Status
+	}
+	m := &model.AdminRateLimit{AppID: p.AppID, Hash: p.Hash, Rate: p.Rate, Burst: p.Burst, DailyQuota: p.DailyQuota, MonthlyQuota: p.MonthlyQuota, Status: status, AddTime: time.Now().Unix()}
+	if err := s.DAO.Create(ctx, m); err != nil {
+		return 0, err
+	}
+	s.invalidate()
+	return m.ID, nil
+}
+
+// Edit 修改限额与状态（作用域不可变更）
+func (s *RateLimitService) Edit(ctx context.Context, p SaveRateLimitParams) error {
+	if p.ID <= 0 {
+		return errors.New("invalid id")
+	}
+	if err := checkLimitValues(p); err != nil {
+		return err
+	}
+	m, err := s.DAO.FindByID(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return errors.New("not found")
+	}
+	m.Rate, m.Burst, m.DailyQuota, m.MonthlyQuota = p.Rate, p.Burst, p.DailyQuota, p.MonthlyQuota
+	if p.Status != nil {
+		m.Status = *p.Status
+	}
+	if err := s.DAO.Update(ctx, m); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *RateLimitService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+	if err := s.DAO.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// LimitUsage 作用域当前消耗
+type LimitUsage struct {
+	Scope        string                `json:"scope"`
+	AppID        string                `json:"app_id"`
+	Hash         string                `json:"hash"`
+	Day          string                `json:"day"`
+	Month        string                `json:"month"`
+	Daily        int64                 `json:"daily"`
+	Monthly      int64                 `json:"monthly"`
+	DailyQuota   int64                 `json:"daily_quota"`
+	MonthlyQuota int64                 `json:"monthly_quota"`
+	Rule         *model.AdminRateLimit `json:"rule"`
+}
+
+// Usage 查询作用域当日/当月调用量（仅统计存在规则的作用域）
+func (s *RateLimitService) Usage(ctx context.Context, appID, hash string) (*LimitUsage, error) {
+	appID, hash = strings.TrimSpace(appID), strings.TrimSpace(hash)
+	if appID == "" && hash == "" {
+		return nil, errors.New("app_id或hash至少填写一项")
+	}
+	rule, err := s.DAO.FindByScope(ctx, appID, hash)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	scope, sk := LimitScope(appID, hash)
+	dk, mk := quotaKeys(sk, now)
+	u := &LimitUsage{Scope: scope, AppID: appID, Hash: hash, Day: now.Format("20060102"), Month: now.Format("200601"), Rule: rule}
+	u.Daily, _ = s.Redis.Client.Get(ctx, dk).Int64()
+	u.Monthly, _ = s.Redis.Client.Get(ctx, mk).Int64()
+	if rule != nil {
+		u.DailyQuota, u.MonthlyQuota = rule.DailyQuota, rule.MonthlyQuota
+	}
+	return u, nil
+}
+
+func checkLimitValues(p SaveRateLimitParams) error {
+	if p.Rate < 0 || p.Burst < 0 || p.DailyQuota < 0 || p.MonthlyQuota < 0 {
+		return errors.New("限额不能为负数")
+	}
+	return nil
+}
+
+func (s *RateLimitService) invalidate() {
+	if s.Cache != nil {
+		_ = s.Cache.Del(context.Background(), rateLimitRulesKey)
+	}
+}
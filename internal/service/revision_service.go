@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/repository/dao"
+
+	"gorm.io/gorm"
+)
+
+// 接口定义修订：接口 / 字段每次写入后保存完整快照（内容未变不生成新版本），
+// 支持任意两版本比对与回滚。首次修改前自动补录 baseline 版本，保证改动前的定义可回滚。
+const (
+	RevisionBaseline  = "baseline"
+	RevisionAdd       = "add"
+	RevisionEdit      = "edit"
+	RevisionStatus    = "status"
+	RevisionDelete    = "delete"
+	RevisionFields    = "fields"
+	RevisionImport    = "import"
+	RevisionRollback  = "rollback"
+	RevisionLifecycle = "lifecycle"
+
+	revisionKeep  = 100 // 每个接口保留的修订数
+	revisionRetry = 3   // 版本号冲突时的最大尝试次数
+)
+
+var (
+	ErrRevisionNotFound = errors.New("修订版本不存在")
+	ErrRevisionDeleted  = errors.New("该版本接口已删除，不能回滚到此版本")
+	ErrRevisionSame     = errors.New("当前定义与该版本一致")
+)
+
+type RevisionService struct {
+	DAO    *dao.AdminInterfaceRevisionDAO
+	IfList *InterfaceListService
+	Fields *FieldsService
+}
+
+func NewRevisionService(d *dao.AdminInterfaceRevisionDAO, ifl *InterfaceListService, fields *FieldsService) *RevisionService {
+	return &RevisionService{DAO: d, IfList: ifl, Fields: fields}
+}
+
+// InterfaceSnapshot 接口定义快照；Interface 为 nil 表示接口已删除。字段 / 接口 id 置 0，仅比对内容
+type InterfaceSnapshot struct {
+	Interface *model.AdminInterfaceList `json:"interface"`
+	Request   []model.AdminField        `json:"request"`
+	Response  []model.AdminField        `json:"response"`
+}
+
+// RevisionDetail 修订详情（含快照）
+type RevisionDetail struct {
+	model.AdminInterfaceRevision
+	Snapshot InterfaceSnapshot `json:"snapshot"`
+}
+
+// RevisionDiff 两个版本的比对结果
+type RevisionDiff struct {
+	Hash    string             `json:"hash"`
+	From    int                `json:"from"`
+	To      int                `json:"to"`
+	Changes []DefinitionChange `json:"changes"`
+}
+
+// ListRevisionResult 修订列表
+type ListRevisionResult struct {
+	List  []model.AdminInterfaceRevision `json:"list"`
+	Total int64                          `json:"total"`
+}
+
+// Baseline 接口尚无修订时保存当前定义为 baseline，须在写入前调用
+func (s *RevisionService) Baseline(ctx context.Context, hash string) {
+	if s == nil || hash == "" {
+		return
+	}
+	if latest, err := s.DAO.Latest(ctx, hash); err != nil || latest != nil {
+		return
+	}
+	_, _ = s.record(ctx, hash, RevisionBaseline, "")
+}
+
+// Record 写入后保存修订，失败不影响主流程
+func (s *RevisionService) Record(ctx context.Context, hash, action string) {
+	if s == nil || hash == "" {
+		return
+	}
+	_, _ = s.record(ctx, hash, action, "")
+}
+
+// record 保存修订；(hash, version) 唯一索引冲突（并发写入抢占了同一版本号）时按最新版本重算后重试
+func (s *RevisionService) record(ctx context.Context, hash, action, note string) (*model.AdminInterfaceRevision, error) {
+	for attempt := 1; ; attempt++ {
+		rev, err := s.recordOnce(ctx, hash, action, note)
+		if err == nil || rev == nil || attempt >= revisionRetry {
+			return rev, err
+		}
+		if latest, lerr := s.DAO.Latest(ctx, hash); lerr != nil || latest == nil || latest.Version < rev.Version {
+			return nil, err // 非版本冲突
+		}
+	}
+}
+
+// recordOnce 写入失败时仍返回待写入的修订，供 record 判断版本冲突
+func (s *RevisionService) recordOnce(ctx context.Context, hash, action, note string) (*model.AdminInterfaceRevision, error) {
+	snap, err := s.snapshot(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.DAO.Latest(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if snap.Interface == nil && latest == nil { // 接口不存在且无历史：无可记录
+		return nil, nil
+	}
+	raw, _ := json.Marshal(snap)
+	sum := sha1.Sum(raw)
+	digest := hex.EncodeToString(sum[:])
+	if latest != nil && latest.Digest == digest {
+		return latest, nil
+	}
+	rev := &model.AdminInterfaceRevision{Hash: hash, Version: 1, Action: action, Snapshot: string(raw), Digest: digest,
+		UserID: operatorID(ctx), AddTime: time.Now().Unix()}
+	var changes []DefinitionChange
+	if latest != nil {
+		rev.Version = latest.Version + 1
+		var prev InterfaceSnapshot
+		_ = json.Unmarshal([]byte(latest.Snapshot), &prev)
+		changes = DiffSnapshots(&prev, snap)
+	}
+	rev.Summary = truncateRunes(revisionSummary(note, changes), fieldTextMax)
+	if err := s.DAO.Create(ctx, rev); err != nil {
+		return rev, err
+	}
+	if rev.Version > revisionKeep {
+		_ = s.DAO.Prune(ctx, hash, rev.Version-revisionKeep+1)
+	}
+	return rev, nil
+}
+
+// snapshot 读取接口当前定义（id 置 0，仅保留内容）
+func (s *RevisionService) snapshot(ctx context.Context, hash string) (*InterfaceSnapshot, error) {
+	snap, err := loadSnapshot(ctx, s.IfList.DAO, s.Fields.DAO, hash)
+	if err != nil {
+		return nil, err
+	}
+	if snap.Interface != nil {
+		snap.Interface.ID = 0
+	}
+	for _, list := range [][]model.AdminField{snap.Request, snap.Response} {
+		for i := range list {
+			list[i].ID = 0
+		}
+	}
+	return snap, nil
+}
+
+// loadSnapshot 读取接口及其请求 / 响应字段
+func loadSnapshot(ctx context.Context, list *dao.AdminInterfaceListDAO, fields *dao.AdminFieldsDAO, hash string) (*InterfaceSnapshot, error) {
+	api, err := list.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	snap := &InterfaceSnapshot{Interface: api, Request: []model.AdminField{}, Response: []model.AdminField{}}
+	for typ, dst := range []*[]model.AdminField{&snap.Request, &snap.Response} {
+		rows, err := fields.ListByHashAndType(ctx, hash, int8(typ))
+		if err != nil {
+			return nil, err
+		}
+		*dst = append(*dst, rows...)
+	}
+	return snap, nil
+}
+
+// List 修订列表
+func (s *RevisionService) List(ctx context.Context, hash string, page, limit int) (*ListRevisionResult, error) {
+	if strings.TrimSpace(hash) == "" {
+		return nil, errors.New("hash required")
+	}
+	list, total, err := s.DAO.List(ctx, hash, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []model.AdminInterfaceRevision{}
+	}
+	return &ListRevisionResult{List: list, Total: total}, nil
+}
+
+// Get 指定版本详情
+func (s *RevisionService) Get(ctx context.Context, hash string, version int) (*RevisionDetail, error) {
+	rev, err := s.DAO.FindVersion(ctx, hash, version)
+	if err != nil {
+		return nil, err
+	}
+	if rev == nil {
+		return nil, ErrRevisionNotFound
+	}
+	out := &RevisionDetail{AdminInterfaceRevision: *rev}
+	if err := json.Unmarshal([]byte(rev.Snapshot), &out.Snapshot); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Diff 比对两个版本；to<=0 取最新版本，from<=0 取 to 的上一版本（不存在时与空定义比对）
+func (s *RevisionService) Diff(ctx context.Context, hash string, from, to int) (*RevisionDiff, error) {
+	if to <= 0 {
+		latest, err := s.DAO.Latest(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil {
+			return nil, ErrRevisionNotFound
+		}
+		to = latest.Version
+	}
+	b, err := s.Get(ctx, hash, to)
+	if err != nil {
+		return nil, err
+	}
+	if from <= 0 {
+		from = to - 1
+	}
+	a := &RevisionDetail{}
+	if from > 0 {
+		prev, err := s.Get(ctx, hash, from)
+		switch {
+		case err == nil:
+			a = prev
+		case errors.Is(err, ErrRevisionNotFound) && from == to-1: // 上一版本已被清理，与空定义比对
+			from = 0
+		default:
+			return nil, err
+		}
+	}
+	changes := DiffSnapshots(&a.Snapshot, &b.Snapshot)
+	if changes == nil {
+		changes = []DefinitionChange{}
+	}
+	return &RevisionDiff{Hash: hash, From: from, To: to, Changes: changes}, nil
+}
+
+// Rollback 将接口及字段恢复为指定版本并生成新的 rollback 修订
+func (s *RevisionService) Rollback(ctx context.Context, hash string, version int) (*model.AdminInterfaceRevision, error) {
+	target, err := s.Get(ctx, hash, version)
+	if err != nil {
+		return nil, err
+	}
+	api := target.Snapshot.Interface
+	if api == nil {
+		return nil, ErrRevisionDeleted
+	}
+	if latest, err := s.DAO.Latest(ctx, hash); err != nil {
+		return nil, err
+	} else if latest != nil && latest.Digest == target.Digest {
+		return nil, ErrRevisionSame
+	}
+	cur, err := s.IfList.DAO.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	var excludeID int64
+	if cur != nil {
+		excludeID = cur.ID
+	}
+	if ok, err := s.IfList.DAO.ExistsAPIClass(ctx, api.APIClass, excludeID); err != nil {
+		return nil, err
+	} else if ok {
+		return nil, errors.New("api_class exists")
+	}
+	restored := *api
+	restored.Hash = hash
+	classes := []string{restored.APIClass}
+	if cur != nil {
+		restored.ID = cur.ID
+		classes = append(classes, cur.APIClass)
+	} else { // 已删除的接口按原 hash 重建
+		restored.ID = 0
+	}
+	// 接口列与字段在同一事务内恢复，避免留下新旧混合的定义
+	err = s.IfList.DAO.DB.Transaction(func(tx *gorm.DB) error {
+		if cur == nil {
+			if err := s.IfList.DAO.WithTx(tx).Create(ctx, &restored); err != nil {
+				return err
+			}
+		} else if err := s.IfList.DAO.WithTx(tx).UpdateColumns(ctx, cur.ID, interfaceColumns(&restored)); err != nil {
+			return err
+		}
+		return s.Fields.replaceTx(ctx, tx, hash, target.Snapshot.Request, target.Snapshot.Response)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.IfList.invalidateOne(restored.ID, hash, classes...)
+	s.Fields.invalidateHash(hash)
+	s.IfList.Search.Touch(ctx, hash)
+	return s.record(ctx, hash, RevisionRollback, fmt.Sprintf("回滚到 v%d", version))
+}
+
+// interfaceColumns 快照中可恢复的接口列（显式列出，允许写入零值）；生命周期列不随回滚恢复
+func interfaceColumns(m *model.AdminInterfaceList) map[string]interface{} {
+	return map[string]interface{}{
+		"api_class": m.APIClass, "access_token": m.AccessToken, "status": m.Status, "method": m.Method, "info": m.Info,
+		"is_test": m.IsTest, "return_str": m.ReturnStr, "group_hash": m.GroupHash, "hash_type": m.HashType,
+		"timeout_ms": m.TimeoutMS, "cache_ttl": m.CacheTTL, "cache_keys": m.CacheKeys, "cache_vary": m.CacheVary,
+	}
+}
+
+// DiffSnapshots 比对两个快照：接口属性逐列比对，字段按 请求 / 响应 + 字段名 比对
+func DiffSnapshots(a, b *InterfaceSnapshot) []DefinitionChange {
+	var out []DefinitionChange
+	switch {
+	case a.Interface == nil && b.Interface != nil:
+		out = append(out, DefinitionChange{Scope: "interface", Name: b.Interface.APIClass, Op: "add"})
+	case a.Interface != nil && b.Interface == nil:
+		out = append(out, DefinitionChange{Scope: "interface", Name: a.Interface.APIClass, Op: "remove"})
+	case a.Interface != nil && b.Interface != nil:
+		from, to := interfaceColumns(a.Interface), interfaceColumns(b.Interface)
+		for k, v := range lifecycleColumns(a.Interface) {
+			from[k] = v
+		}
+		for k, v := range lifecycleColumns(b.Interface) {
+			to[k] = v
+		}
+		for _, col := range sortedKeys(from) {
+			x, y := fmt.Sprint(from[col]), fmt.Sprint(to[col])
+			if col == "method" {
+				x, y = InterfaceMethod(a.Interface.Method), InterfaceMethod(b.Interface.Method)
+			}
+			if x != y {
+				out = append(out, DefinitionChange{Scope: "interface", Name: col, Op: "change", From: x, To: y})
+			}
+		}
+	}
+	out = append(out, diffFields("request", a.Request, b.Request)...)
+	return append(out, diffFields("response", a.Response, b.Response)...)
+}
+
+func diffFields(scope string, old, cur []model.AdminField) []DefinitionChange {
+	var out []DefinitionChange
+	byName := make(map[string]model.AdminField, len(old))
+	for _, f := range old {
+		byName[FieldPath(f)] = f
+	}
+	seen := make(map[string]bool, len(cur))
+	for _, f := range cur {
+		seen[FieldPath(f)] = true
+		prev, ok := byName[FieldPath(f)]
+		if !ok {
+			out = append(out, DefinitionChange{Scope: scope, Name: FieldPath(f), Op: "add", To: fieldDetail(f)})
+		} else if a, b := fieldDetail(prev), fieldDetail(f); a != b {
+			out = append(out, DefinitionChange{Scope: scope, Name: FieldPath(f), Op: "change", From: a, To: b})
+		}
+	}
+	for _, f := range old {
+		if !seen[FieldPath(f)] {
+			out = append(out, DefinitionChange{Scope: scope, Name: FieldPath(f), Op: "remove", From: fieldDetail(f)})
+		}
+	}
+	return out
+}
+
+// fieldDetail 字段比对摘要：在 fieldSignature 基础上包含展示名与说明
+func fieldDetail(f model.AdminField) string {
+	sig := fieldSignature(f)
+	if f.ShowName != "" && f.ShowName != f.FieldName {
+		sig += ",show_name=" + f.ShowName
+	}
+	if f.Info != "" {
+		sig += ",info=" + f.Info
+	}
+	return sig
+}
+
+// revisionSummary 变更摘要，如 "修改 info；请求字段 新增 page、删除 size"
+func revisionSummary(note string, changes []DefinitionChange) string {
+	var parts []string
+	if note != "" {
+		parts = append(parts, note)
+	}
+	scopes := map[string]string{"interface": "", "request": "请求字段 ", "response": "响应字段 "}
+	ops := map[string]string{"add": "新增", "remove": "删除", "change": "修改"}
+	for _, scope := range []string{"interface", "request", "response"} {
+		var seg []string
+		for _, op := range []string{"add", "remove", "change"} {
+			var names []string
+			for _, c := range changes {
+				if c.Scope == scope && c.Op == op {
+					names = append(names, c.Name)
+				}
+			}
+			if len(names) > 0 {
+				if scope == "interface" && op != "change" {
+					seg = append(seg, ops[op]+"接口")
+				} else {
+					seg = append(seg, ops[op]+" "+strings.Join(names, "、"))
+				}
+			}
+		}
+		if len(seg) > 0 {
+			parts = append(parts, scopes[scope]+strings.Join(seg, "、"))
+		}
+	}
+	if len(parts) == 0 {
+		return "初始版本"
+	}
+	return strings.Join(parts, "；")
+}
+
+// operatorID 请求 context 中的后台用户 id（由 JWT 鉴权中间件注入）
+func operatorID(ctx context.Context) int64 {
+	if v, ok := ctx.Value("user_id").(int64); ok {
+		return v
+	}
+	return 0
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+)
+
+// 文档导出为 Postman Collection v2.1 / Insomnia v4，范围与 OpenAPI 导出一致（应用可见接口）。
+// 地址、令牌、签名头均以变量占位（base_url / app_id / app_secret / access_token / timestamp / nonce / signature），
+// 另附一个“获取 AccessToken”请求。
+const (
+	ExportPostman  = "postman"
+	ExportInsomnia = "insomnia"
+
+	postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+)
+
+var ErrExportFormat = errors.New("format 取值应为 postman / insomnia")
+
+// ExportOptions 导出参数
+type ExportOptions struct {
+	Format  string
+	Name    string
+	BaseURL string
+	User    WikiUserInfo
+}
+
+// exportRequest 与格式无关的请求描述；头部取值为变量名
+type exportRequest struct {
+	ID          string
+	Name        string
+	Description string
+	Method      string
+	Path        string
+	Headers     [][2]string // 名称, 变量名
+	Query       [][2]string
+	JSON        string
+	Form        []exportFormField // 含 File 字段时使用 multipart
+}
+
+type exportFormField struct {
+	Name, Value string
+	File        bool
+}
+
+type exportFolder struct {
+	ID, Name, Description string
+}
+
+// Export 生成指定格式的集合文档
+func (s *WikiService) Export(ctx context.Context, opt ExportOptions) (interface{}, error) {
+	if opt.Format != ExportPostman && opt.Format != ExportInsomnia {
+		return nil, ErrExportFormat
+	}
+	list, app, err := s.VisibleAPIs(ctx, opt.User)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.fieldsByHash(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	signed := app != nil && app.SignEnable == 1
+	vars := [][2]string{{"base_url", opt.BaseURL}, {"app_id", ""}, {"app_secret", ""}, {"access_token", ""}}
+	if app != nil {
+		vars[1][1] = app.AppID
+		opt.Name += " - " + app.AppName
+	}
+	if signed {
+		vars = append(vars, [2]string{"timestamp", ""}, [2]string{"nonce", ""}, [2]string{"signature", ""})
+	}
+	var folders []exportFolder
+	reqs := map[string][]exportRequest{}
+	for _, item := range list {
+		api := item.API
+		fid := "fld_" + item.Group.Hash
+		if _, ok := reqs[fid]; !ok {
+			folders = append(folders, exportFolder{ID: fid, Name: item.Group.Name, Description: item.Group.Description})
+		}
+		method := InterfaceMethod(api.Method)
+		if method == "*" {
+			method = "POST"
+		}
+		r := exportRequest{ID: "req_" + api.Hash, Name: firstNonEmpty(api.Info, api.APIClass), Description: api.APIClass, Method: method, Path: GatewayPath(api)}
+		if api.AccessToken == 1 {
+			r.Headers = append(r.Headers, [2]string{"Access-Token", "access_token"})
+		}
+		if signed {
+			r.Headers = append(r.Headers, [2]string{"X-App-Id", "app_id"}, [2]string{"X-Timestamp", "timestamp"}, [2]string{"X-Nonce", "nonce"}, [2]string{"X-Signature", "signature"})
+		}
+		defs := fields[api.Hash][0]
+		example := FieldsExample(defs)
+		switch {
+		case len(example) == 0:
+		case method == "GET":
+			for _, k := range sortedKeys(example) {
+				r.Query = append(r.Query, [2]string{k, exampleString(example[k])})
+			}
+		case hasFileField(defs):
+			for _, k := range sortedKeys(example) {
+				r.Form = append(r.Form, exportFormField{Name: k, Value: exampleString(example[k]), File: fileField(defs, k)})
+			}
+		default:
+			b, _ := json.MarshalIndent(example, "", "  ")
+			r.JSON = string(b)
+		}
+		reqs[fid] = append(reqs[fid], r)
+	}
+	token := exportRequest{ID: "req_access_token", Name: "获取 AccessToken", Description: "app_id + app_secret 换取访问令牌，结果写入 access_token 变量",
+		Method: "POST", Path: "/gateway/accessToken", JSON: "{\n  \"app_id\": \"{{app_id}}\",\n  \"app_secret\": \"{{app_secret}}\"\n}"}
+	if opt.Format == ExportPostman {
+		return postmanCollection(opt.Name, vars, token, folders, reqs), nil
+	}
+	return insomniaExport(opt.Name, vars, token, folders, reqs), nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// exampleString 查询参数 / 表单取值：标量直接输出，对象与数组输出 JSON
+func exampleString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(t)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+func hasFileField(defs []model.AdminField) bool {
+	for _, f := range defs {
+		if f.DataType == DataTypeFile {
+			return true
+		}
+	}
+	return false
+}
+
+func fileField(defs []model.AdminField, name string) bool {
+	for _, f := range defs {
+		if FieldPath(f) == name {
+			return f.DataType == DataTypeFile
+		}
+	}
+	return false
+}
+
+// exportID 由种子生成稳定的 UUID 形式标识（重复导出可覆盖同一集合）
+func exportID(seed string) string {
+	h := sha1.Sum([]byte(seed))
+	x := hex.EncodeToString(h[:16])
+	return x[:8] + "-" + x[8:12] + "-" + x[12:16] + "-" + x[16:20] + "-" + x[20:32]
+}
+
+// ===== Postman Collection v2.1 =====
+
+type PostmanCollection struct {
+	Info     PostmanInfo   `json:"info"`
+	Item     []PostmanItem `json:"item"`
+	Variable []PostmanKV   `json:"variable"`
+}
+
+type PostmanInfo struct {
+	PostmanID   string `json:"_postman_id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type PostmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []PostmanItem   `json:"item,omitempty"`
+	Request     *PostmanRequest `json:"request,omitempty"`
+	Event       []PostmanEvent  `json:"event,omitempty"`
+}
+
+type PostmanRequest struct {
+	Method      string       `json:"method"`
+	Header      []PostmanKV  `json:"header"`
+	URL         PostmanURL   `json:"url"`
+	Body        *PostmanBody `json:"body,omitempty"`
+	Description string       `json:"description,omitempty"`
+}
+
+type PostmanURL struct {
+	Raw   string      `json:"raw"`
+	Host  []string    `json:"host"`
+	Path  []string    `json:"path"`
+	Query []PostmanKV `json:"query,omitempty"`
+}
+
+type PostmanKV struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+	Type  string `json:"type,omitempty"`
+}
+
+type PostmanBody struct {
+	Mode     string                 `json:"mode"`
+	Raw      string                 `json:"raw,omitempty"`
+	FormData []PostmanKV            `json:"formdata,omitempty"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+}
+
+type PostmanEvent struct {
+	Listen string        `json:"listen"`
+	Script PostmanScript `json:"script"`
+}
+
+type PostmanScript struct {
+	Type string   `json:"type"`
+	Exec []string `json:"exec"`
+}
+
+func postmanCollection(name string, vars [][2]string, token exportRequest, folders []exportFolder, reqs map[string][]exportRequest) *PostmanCollection {
+	col := &PostmanCollection{
+		Info: PostmanInfo{PostmanID: exportID("postman:" + name), Name: name, Schema: postmanSchema,
+			Description: "变量：base_url 网关地址、app_id/app_secret 应用凭据；先执行“获取 AccessToken”自动写入 access_token。"},
+	}
+	for _, v := range vars {
+		col.Variable = append(col.Variable, PostmanKV{Key: v[0], Value: v[1]})
+	}
+	tokenItem := postmanItem(token)
+	tokenItem.Event = []PostmanEvent{{Listen: "test", Script: PostmanScript{Type: "text/javascript", Exec: []string{
+		"var res = pm.response.json();",
+		"if (res.data && res.data.access_token) { pm.collectionVariables.set('access_token', res.data.access_token); }",
+	}}}}
+	col.Item = append(col.Item, tokenItem)
+	for _, f := range folders {
+		folder := PostmanItem{Name: f.Name, Description: f.Description}
+		for _, r := range reqs[f.ID] {
+			folder.Item = append(folder.Item, postmanItem(r))
+		}
+		col.Item = append(col.Item, folder)
+	}
+	return col
+}
+
+func postmanItem(r exportRequest) PostmanItem {
+	req := &PostmanRequest{Method: r.Method, Header: []PostmanKV{}, Description: r.Description,
+		URL: PostmanURL{Raw: "{{base_url}}" + r.Path, Host: []string{"{{base_url}}"}, Path: strings.Split(strings.Trim(r.Path, "/"), "/")}}
+	for _, h := range r.Headers {
+		req.Header = append(req.Header, PostmanKV{Key: h[0], Value: "{{" + h[1] + "}}"})
+	}
+	if len(r.Query) > 0 {
+		q := make([]string, 0, len(r.Query))
+		for _, kv := range r.Query {
+			req.URL.Query = append(req.URL.Query, PostmanKV{Key: kv[0], Value: kv[1]})
+			q = append(q, kv[0]+"="+kv[1])
+		}
+		req.URL.Raw += "?" + strings.Join(q, "&")
+	}
+	switch {
+	case len(r.Form) > 0:
+		req.Body = &PostmanBody{Mode: "formdata"}
+		for _, f := range r.Form {
+			kv := PostmanKV{Key: f.Name, Value: f.Value, Type: "text"}
+			if f.File {
+				kv = PostmanKV{Key: f.Name, Type: "file"}
+			}
+			req.Body.FormData = append(req.Body.FormData, kv)
+		}
+	case r.JSON != "":
+		req.Header = append(req.Header, PostmanKV{Key: "Content-Type", Value: "application/json"})
+		req.Body = &PostmanBody{Mode: "raw", Raw: r.JSON, Options: map[string]interface{}{"raw": map[string]string{"language": "json"}}}
+	}
+	return PostmanItem{Name: r.Name, Request: req}
+}
+
+// ===== Insomnia v4 =====
+
+type InsomniaExport struct {
+	Type      string             `json:"_type"`
+	Format    int                `json:"__export_format"`
+	Date      string             `json:"__export_date"`
+	Source    string             `json:"__export_source"`
+	Resources []InsomniaResource `json:"resources"`
+}
+
+type InsomniaResource struct {
+	ID          string            `json:"_id"`
+	Type        string            `json:"_type"`
+	ParentID    *string           `json:"parentId"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Scope       string            `json:"scope,omitempty"`
+	Data        map[string]string `json:"data,omitempty"`
+	Method      string            `json:"method,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Body        *InsomniaBody     `json:"body,omitempty"`
+	Parameters  []InsomniaParam   `json:"parameters,omitempty"`
+	Headers     []InsomniaParam   `json:"headers,omitempty"`
+}
+
+type InsomniaBody struct {
+	MimeType string          `json:"mimeType"`
+	Text     string          `json:"text,omitempty"`
+	Params   []InsomniaParam `json:"params,omitempty"`
+}
+
+type InsomniaParam struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+func insomniaExport(name string, vars [][2]string, token exportRequest, folders []exportFolder, reqs map[string][]exportRequest) *InsomniaExport {
+	wid := "wrk_" + generateShortHash("insomnia:"+name)
+	out := &InsomniaExport{Type: "export", Format: 4, Date: time.Now().UTC().Format(time.RFC3339), Source: "go-apiadmin"}
+	env := map[string]string{}
+	for _, v := range vars {
+		env[v[0]] = v[1]
+	}
+	out.Resources = append(out.Resources,
+		InsomniaResource{ID: wid, Type: "workspace", Name: name, Scope: "collection"},
+		InsomniaResource{ID: "env_" + generateShortHash("insomnia:"+name), Type: "environment", ParentID: &wid, Name: "Base Environment", Data: env},
+	)
+	token.JSON = strings.NewReplacer("{{app_id}}", "{{ _.app_id }}", "{{app_secret}}", "{{ _.app_secret }}").Replace(token.JSON)
+	out.Resources = append(out.Resources, insomniaRequest(token, wid))
+	for _, f := range folders {
+		out.Resources = append(out.Resources, InsomniaResource{ID: f.ID, Type: "request_group", ParentID: &wid, Name: f.Name, Description: f.Description})
+		for _, r := range reqs[f.ID] {
+			out.Resources = append(out.Resources, insomniaRequest(r, f.ID))
+		}
+	}
+	return out
+}
+
+func insomniaRequest(r exportRequest, parent string) InsomniaResource {
+	res := InsomniaResource{ID: r.ID, Type: "request", ParentID: &parent, Name: r.Name, Description: r.Description, Method: r.Method, URL: "{{ _.base_url }}" + r.Path, Headers: []InsomniaParam{}}
+	for _, h := range r.Headers {
+		res.Headers = append(res.Headers, InsomniaParam{Name: h[0], Value: "{{ _." + h[1] + " }}"})
+	}
+	for _, kv := range r.Query {
+		res.Parameters = append(res.Parameters, InsomniaParam{Name: kv[0], Value: kv[1]})
+	}
+	switch {
+	case len(r.Form) > 0:
+		res.Headers = append(res.Headers, InsomniaParam{Name: "Content-Type", Value: "multipart/form-data"})
+		res.Body = &InsomniaBody{MimeType: "multipart/form-data"}
+		for _, f := range r.Form {
+			p := InsomniaParam{Name: f.Name, Value: f.Value}
+			if f.File {
+				p = InsomniaParam{Name: f.Name, Type: "file"}
+			}
+			res.Body.Params = append(res.Body.Params, p)
+		}
+	case r.JSON != "":
+		res.Headers = append(res.Headers, InsomniaParam{Name: "Content-Type", Value: "application/json"})
+		res.Body = &InsomniaBody{MimeType: "application/json", Text: r.JSON}
+	}
+	return res
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"go-apiadmin/internal/domain/model"
+)
+
+// OpenAPIDoc OpenAPI 3.1 文档（仅包含导出用到的对象）
+type OpenAPIDoc struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Servers    []OpenAPIServer                         `json:"servers,omitempty"`
+	Tags       []OpenAPITag                            `json:"tags,omitempty"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+type OpenAPITag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	XGroupHash  string `json:"x-group-hash,omitempty"`
+}
+
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+	Deprecated  bool                       `json:"deprecated,omitempty"` // deprecated / retired 接口
+	XAPIHash    string                     `json:"x-api-hash"`
+	XAPIClass   string                     `json:"x-api-class"`
+}
+
+type OpenAPIParameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Required    bool        `json:"required,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Schema      *JSONSchema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIMediaType struct {
+	Schema  *JSONSchema `json:"schema"`
+	Example interface{} `json:"example,omitempty"`
+}
+
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIComponents struct {
+	SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type OpenAPISecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIOptions 导出参数：User 为可见范围（app_id=-1 表示后台全部接口）
+type OpenAPIOptions struct {
+	Title     string
+	Version   string
+	ServerURL string
+	User      WikiUserInfo
+}
+
+// WikiAPI 可见接口及其分组
+type WikiAPI struct {
+	API   model.AdminInterfaceList
+	Group model.AdminGroup
+}
+
+// visibleSet 当前用户可见的 "分组hash|接口hash" 集合；后台登录（app_id=-1）返回 nil 表示不限制
+func (s *WikiService) visibleSet(ctx context.Context, user WikiUserInfo) (map[string]struct{}, *model.AdminApp, error) {
+	v, err := s.Visibility.ResolveUser(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v.Allowed, v.App, nil
+}
+
+// VisibleAPIs 返回用户可见的启用接口：后台用户(app_id=-1)为全部，应用按可见范围（应用分组模板 + 应用覆盖）过滤；
+// 按分组 hash、api_class 排序，供文档/SDK 等导出复用。应用信息实时读取，不依赖登录缓存。
+func (s *WikiService) VisibleAPIs(ctx context.Context, user WikiUserInfo) ([]WikiAPI, *model.AdminApp, error) {
+	allowed, app, err := s.visibleSet(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	groups, err := s.GroupDAO.ListAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	groupMap := make(map[string]model.AdminGroup, len(groups))
+	for _, g := range groups {
+		groupMap[g.Hash] = g
+	}
+	apis, err := s.ListDAO.ListAllActive(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := make([]WikiAPI, 0, len(apis))
+	for _, a := range apis {
+		if allowed != nil {
+			if _, ok := allowed[a.GroupHash+"|"+a.Hash]; !ok {
+				continue
+			}
+		}
+		g, ok := groupMap[a.GroupHash]
+		if !ok {
+			g = model.AdminGroup{Hash: a.GroupHash, Name: a.GroupHash}
+		}
+		out = append(out, WikiAPI{API: a, Group: g})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].API.GroupHash != out[j].API.GroupHash {
+			return out[i].API.GroupHash < out[j].API.GroupHash
+		}
+		return out[i].API.APIClass < out[j].API.APIClass
+	})
+	return out, app, nil
+}
+
+// GatewayPath 接口在网关上的路径（与 GatewayService.Resolve 规则一致）
+func GatewayPath(api model.AdminInterfaceList) string {
+	if api.HashType == 1 {
+		return "/api/" + strings.Trim(api.APIClass, "/")
+	}
+	return "/api/" + api.Hash
+}
+
+// fieldsByHash 批量加载字段并按 hash/type 分组
+func (s *WikiService) fieldsByHash(ctx context.Context, list []WikiAPI) (map[string][2][]model.AdminField, error) {
+	hashes := make([]string, 0, len(list))
+	for _, a := range list {
+		hashes = append(hashes, a.API.Hash)
+	}
+	rows, err := s.FieldsDAO.ListByHashes(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][2][]model.AdminField, len(list))
+	for _, f := range rows {
+		v := out[f.Hash]
+		if f.Type == 1 {
+			v[1] = append(v[1], f)
+		} else {
+			v[0] = append(v[0], f)
+		}
+		out[f.Hash] = v
+	}
+	return out, nil
+}
+
+// OpenAPI 按可见范围构建 OpenAPI 3.1 文档：分组 -> tag，接口 -> /api/{hash|api_class} 操作，
+// 请求字段 GET 为 query 参数、POST 为请求体（含文件字段时为 multipart），响应为 {code,msg,data} 包装。
+func (s *WikiService) OpenAPI(ctx context.Context, opt OpenAPIOptions) (*OpenAPIDoc, error) {
+	list, app, err := s.VisibleAPIs(ctx, opt.User)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.fieldsByHash(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	doc := &OpenAPIDoc{
+		OpenAPI: "3.1.0",
+		Info:    OpenAPIInfo{Title: opt.Title, Version: opt.Version},
+		Paths:   map[string]map[string]*OpenAPIOperation{},
+		Components: OpenAPIComponents{SecuritySchemes: map[string]OpenAPISecurityScheme{
+			"AccessToken": {Type: "apiKey", In: "header", Name: "Access-Token", Description: "POST /gateway/accessToken 换取的应用访问令牌"},
+		}},
+	}
+	if app != nil {
+		doc.Info.Description = app.AppName + "（" + app.AppID + "）可见接口"
+	}
+	if opt.ServerURL != "" {
+		doc.Servers = []OpenAPIServer{{URL: opt.ServerURL}}
+	}
+	signed := app != nil && app.SignEnable == 1
+	if signed {
+		doc.Components.SecuritySchemes["AppSignature"] = OpenAPISecurityScheme{Type: "apiKey", In: "header", Name: "X-Signature",
+			Description: "HMAC-SHA256 签名，需同时携带 X-App-Id / X-Timestamp / X-Nonce"}
+	}
+	seenTag := map[string]bool{}
+	for _, item := range list {
+		api := item.API
+		if !seenTag[item.Group.Hash] {
+			seenTag[item.Group.Hash] = true
+			doc.Tags = append(doc.Tags, OpenAPITag{Name: item.Group.Name, Description: item.Group.Description, XGroupHash: item.Group.Hash})
+		}
+		defs := fields[api.Hash]
+		methods := []string{strings.ToLower(InterfaceMethod(api.Method))}
+		if methods[0] == "*" {
+			methods = []string{"get", "post"}
+		}
+		path := GatewayPath(api)
+		ops := doc.Paths[path]
+		if ops == nil {
+			ops = map[string]*OpenAPIOperation{}
+			doc.Paths[path] = ops
+		}
+		for _, m := range methods {
+			op := &OpenAPIOperation{
+				OperationID: operationID(api, m, len(methods) > 1),
+				Summary:     api.Info,
+				Tags:        []string{item.Group.Name},
+				Responses:   map[string]OpenAPIResponse{"200": openAPIResponse(api, defs[1])},
+				Deprecated:  LifecycleNotice(&api),
+				XAPIHash:    api.Hash,
+				XAPIClass:   api.APIClass,
+			}
+			reqSchema := FieldsSchema(defs[0])
+			if m == "get" {
+				op.Parameters = queryParameters(reqSchema)
+			} else if len(reqSchema.Properties) > 0 {
+				op.RequestBody = requestBody(reqSchema, defs[0])
+			}
+			req := map[string][]string{}
+			if api.AccessToken == 1 {
+				req["AccessToken"] = []string{}
+			}
+			if signed {
+				req["AppSignature"] = []string{}
+			}
+			if len(req) > 0 {
+				op.Security = []map[string][]string{req}
+			}
+			ops[m] = op
+		}
+	}
+	return doc, nil
+}
+
+// operationID api_class 转驼峰（User/login -> userLogin），不限方法的接口追加方法后缀
+func operationID(api model.AdminInterfaceList, method string, suffix bool) string {
+	parts := strings.FieldsFunc(api.APIClass, func(r rune) bool { return r == '/' || r == '.' || r == '_' || r == '-' })
+	var b strings.Builder
+	for i, p := range parts {
+		if i == 0 {
+			b.WriteString(strings.ToLower(p[:1]) + p[1:])
+		} else {
+			b.WriteString(strings.ToUpper(p[:1]) + p[1:])
+		}
+	}
+	id := b.String()
+	if id == "" {
+		id = "api" + api.Hash
+	}
+	if suffix {
+		id += strings.ToUpper(method[:1]) + method[1:]
+	}
+	return id
+}
+
+func queryParameters(sc *JSONSchema) []OpenAPIParameter {
+	names := make([]string, 0, len(sc.Properties))
+	for k := range sc.Properties {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	required := map[string]bool{}
+	for _, r := range sc.Required {
+		required[r] = true
+	}
+	out := make([]OpenAPIParameter, 0, len(names))
+	for _, k := range names {
+		p := sc.Properties[k]
+		out = append(out, OpenAPIParameter{Name: k, In: "query", Required: required[k], Description: p.Description, Schema: p})
+	}
+	return out
+}
+
+func requestBody(sc *JSONSchema, defs []model.AdminField) *OpenAPIRequestBody {
+	body := &OpenAPIRequestBody{Required: len(sc.Required) > 0, Content: map[string]OpenAPIMediaType{}}
+	for _, f := range defs {
+		if f.DataType == DataTypeFile {
+			body.Content["multipart/form-data"] = OpenAPIMediaType{Schema: sc}
+			return body
+		}
+	}
+	body.Content["application/json"] = OpenAPIMediaType{Schema: sc}
+	body.Content["application/x-www-form-urlencoded"] = OpenAPIMediaType{Schema: sc}
+	return body
+}
+
+// openAPIResponse 统一包装 {code,msg,data}；return_str 为合法 JSON 时作为示例
+func openAPIResponse(api model.AdminInterfaceList, defs []model.AdminField) OpenAPIResponse {
+	mt := OpenAPIMediaType{Schema: ResponseEnvelopeSchema(defs)}
+	if ex := strings.TrimSpace(api.ReturnStr); ex != "" && json.Valid([]byte(ex)) {
+		var v interface{}
+		if json.Unmarshal([]byte(ex), &v) == nil {
+			mt.Example = v
+		}
+	}
+	return OpenAPIResponse{Description: "success", Content: map[string]OpenAPIMediaType{"application/json": mt}}
+}
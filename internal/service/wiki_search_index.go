@@ -0,0 +1,563 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"html"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/repository/dao"
+	redisrepo "go-apiadmin/internal/repository/redis"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Wiki 搜索索引：进程内倒排索引，覆盖接口 api_class / 说明、分组名、字段名与字段说明，BM25 排序。
+// 分词：英文数字按词（驼峰 / 下划线 / 斜杠拆分，并保留整词），中日韩文字取单字 + 二元组。
+// 同步：本节点变更立即重建对应文档；多节点通过 Redis 变更流（版本号 + 有序集合）增量拉取，
+// 落后超过窗口或距上次全量超过 rebuildInterval 时全量重建。
+const (
+	searchVerKey     = "wiki:searchidx:ver"
+	searchChangesKey = "wiki:searchidx:changes"
+	searchWindow     = 1000 // 变更流保留条数
+	searchSyncEvery  = time.Second
+	rebuildInterval  = 10 * time.Minute
+
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// 字段权重
+const (
+	searchFieldAPI = iota
+	searchFieldInfo
+	searchFieldGroup
+	searchFieldName
+	searchFieldDesc
+	searchFieldCount
+)
+
+var searchWeights = [searchFieldCount]float64{3, 2.5, 1.5, 1, 0.5}
+
+type searchDoc struct {
+	API       model.AdminInterfaceList
+	GroupName string
+	texts     [searchFieldCount]string
+	tf        map[string]float64 // 加权词频
+	length    float64
+}
+
+type WikiSearchIndex struct {
+	ListDAO   *dao.AdminInterfaceListDAO
+	GroupDAO  *dao.AdminGroupDAO
+	FieldsDAO *dao.AdminFieldsDAO
+	Redis     *redisrepo.Client
+
+	mu       sync.RWMutex
+	docs     map[string]*searchDoc
+	postings map[string]map[string]float64 // token -> hash -> 加权词频
+	totalLen float64
+	version  int64 // 已应用的变更版本
+	builtAt  time.Time
+	syncedAt time.Time
+
+	syncMu     sync.Mutex
+	rebuilding bool
+}
+
+func NewWikiSearchIndex(list *dao.AdminInterfaceListDAO, grp *dao.AdminGroupDAO, fields *dao.AdminFieldsDAO, r *redisrepo.Client) *WikiSearchIndex {
+	return &WikiSearchIndex{ListDAO: list, GroupDAO: grp, FieldsDAO: fields, Redis: r}
+}
+
+// SearchHit 搜索结果
+type SearchHit struct {
+	ID        int64             `json:"id"`
+	APIClass  string            `json:"api_class"`
+	Hash      string            `json:"hash"`
+	Info      string            `json:"info"`
+	GroupHash string            `json:"group_hash"`
+	GroupName string            `json:"group_name"`
+	Status    int8              `json:"status"`
+	Score     float64           `json:"score"`
+	Highlight map[string]string `json:"highlight"` // api_class / info / group / fields / field_info -> 片段（<em> 标记命中）
+}
+
+// Touch 接口或其字段变更：本节点立即更新，并写入变更流通知其他节点
+func (x *WikiSearchIndex) Touch(ctx context.Context, hashes ...string) {
+	if x == nil {
+		return
+	}
+	x.mu.RLock()
+	built := !x.builtAt.IsZero()
+	x.mu.RUnlock()
+	seen := map[string]bool{}
+	for _, h := range hashes {
+		if h == "" || seen[h] {
+			continue
+		}
+		seen[h] = true
+		if built { // 尚未构建时由首次搜索全量加载
+			_ = x.reindex(ctx, h)
+		}
+		x.publish(ctx, h)
+	}
+}
+
+func (x *WikiSearchIndex) publish(ctx context.Context, hash string) {
+	if x.Redis == nil {
+		return
+	}
+	v, err := x.Redis.Client.Incr(ctx, searchVerKey).Result()
+	if err != nil {
+		return
+	}
+	pipe := x.Redis.Client.Pipeline()
+	pipe.ZAdd(ctx, searchChangesKey, redis.Z{Score: float64(v), Member: hash})
+	pipe.ZRemRangeByScore(ctx, searchChangesKey, "-inf", "("+strconv.FormatInt(v-searchWindow, 10))
+	_, _ = pipe.Exec(ctx)
+	x.mu.Lock()
+	if x.version == v-1 { // 无遗漏时直接推进，避免重复拉取自身变更
+		x.version = v
+	}
+	x.mu.Unlock()
+}
+
+// ensure 首次全量构建；之后按间隔拉取变更流、定期后台全量重建
+func (x *WikiSearchIndex) ensure(ctx context.Context) error {
+	x.mu.RLock()
+	builtAt, syncedAt := x.builtAt, x.syncedAt
+	x.mu.RUnlock()
+	if builtAt.IsZero() {
+		return x.Rebuild(ctx)
+	}
+	if time.Since(builtAt) > rebuildInterval {
+		x.syncMu.Lock()
+		if !x.rebuilding {
+			x.rebuilding = true
+			go func() {
+				bctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				defer cancel()
+				_ = x.Rebuild(bctx)
+				x.syncMu.Lock()
+				x.rebuilding = false
+				x.syncMu.Unlock()
+			}()
+		}
+		x.syncMu.Unlock()
+	}
+	if x.Redis == nil || time.Since(syncedAt) < searchSyncEvery {
+		return nil
+	}
+	return x.sync(ctx)
+}
+
+func (x *WikiSearchIndex) sync(ctx context.Context) error {
+	x.syncMu.Lock()
+	defer x.syncMu.Unlock()
+	x.mu.RLock()
+	local := x.version
+	x.mu.RUnlock()
+	remote, err := x.Redis.Client.Get(ctx, searchVerKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	x.mu.Lock()
+	x.syncedAt = time.Now()
+	x.mu.Unlock()
+	if remote <= local {
+		return nil
+	}
+	if remote-local > searchWindow {
+		return x.Rebuild(ctx)
+	}
+	hashes, err := x.Redis.Client.ZRangeByScore(ctx, searchChangesKey, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(local, 10), Max: strconv.FormatInt(remote, 10),
+	}).Result()
+	if err != nil {
+		return err
+	}
+	for _, h := range hashes {
+		if err := x.reindex(ctx, h); err != nil {
+			return err
+		}
+	}
+	x.mu.Lock()
+	if x.version < remote {
+		x.version = remote
+	}
+	x.mu.Unlock()
+	return nil
+}
+
+// Rebuild 全量重建索引
+func (x *WikiSearchIndex) Rebuild(ctx context.Context) error {
+	var ver int64
+	if x.Redis != nil { // 先取版本：构建期间的新变更会在下次同步时补上
+		v, err := x.Redis.Client.Get(ctx, searchVerKey).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		ver = v
+	}
+	apis, err := x.ListDAO.ListAllActive(ctx)
+	if err != nil {
+		return err
+	}
+	groups, err := x.GroupDAO.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	groupName := make(map[string]string, len(groups))
+	for _, g := range groups {
+		groupName[g.Hash] = g.Name
+	}
+	hashes := make([]string, 0, len(apis))
+	for _, a := range apis {
+		hashes = append(hashes, a.Hash)
+	}
+	fields, err := x.FieldsDAO.ListByHashes(ctx, hashes)
+	if err != nil {
+		return err
+	}
+	byHash := map[string][]model.AdminField{}
+	for _, f := range fields {
+		byHash[f.Hash] = append(byHash[f.Hash], f)
+	}
+	docs := make(map[string]*searchDoc, len(apis))
+	postings := map[string]map[string]float64{}
+	var total float64
+	for _, a := range apis {
+		d := newSearchDoc(a, groupName[a.GroupHash], byHash[a.Hash])
+		docs[a.Hash] = d
+		total += d.length
+		for t, w := range d.tf {
+			p := postings[t]
+			if p == nil {
+				p = map[string]float64{}
+				postings[t] = p
+			}
+			p[a.Hash] = w
+		}
+	}
+	x.mu.Lock()
+	x.docs, x.postings, x.totalLen = docs, postings, total
+	if ver > x.version || x.builtAt.IsZero() {
+		x.version = ver
+	}
+	x.builtAt, x.syncedAt = time.Now(), time.Now()
+	x.mu.Unlock()
+	return nil
+}
+
+// reindex 重建单个接口文档（已删除 / 已禁用则移出索引）
+func (x *WikiSearchIndex) reindex(ctx context.Context, hash string) error {
+	a, err := x.ListDAO.FindByHash(ctx, hash)
+	if err != nil {
+		return err
+	}
+	var d *searchDoc
+	if a != nil && a.Status == 1 {
+		fields, err := x.FieldsDAO.ListByHashes(ctx, []string{hash})
+		if err != nil {
+			return err
+		}
+		name := ""
+		if g, err := x.GroupDAO.FindByHash(ctx, a.GroupHash); err == nil && g != nil {
+			name = g.Name
+		}
+		d = newSearchDoc(*a, name, fields)
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.docs == nil {
+		return nil
+	}
+	if old := x.docs[hash]; old != nil {
+		for t := range old.tf {
+			if p := x.postings[t]; p != nil {
+				delete(p, hash)
+				if len(p) == 0 {
+					delete(x.postings, t)
+				}
+			}
+		}
+		x.totalLen -= old.length
+		delete(x.docs, hash)
+	}
+	if d != nil {
+		x.docs[hash] = d
+		x.totalLen += d.length
+		for t, w := range d.tf {
+			p := x.postings[t]
+			if p == nil {
+				p = map[string]float64{}
+				x.postings[t] = p
+			}
+			p[hash] = w
+		}
+	}
+	return nil
+}
+
+func newSearchDoc(a model.AdminInterfaceList, group string, fields []model.AdminField) *searchDoc {
+	d := &searchDoc{API: a, GroupName: group, tf: map[string]float64{}}
+	var names, descs []string
+	for _, f := range fields {
+		names = append(names, f.FieldName)
+		if f.ShowName != "" && f.ShowName != f.FieldName {
+			names = append(names, f.ShowName)
+		}
+		if f.Info != "" {
+			descs = append(descs, f.Info)
+		}
+	}
+	d.texts = [searchFieldCount]string{a.APIClass, a.Info, group, strings.Join(names, " "), strings.Join(descs, " ")}
+	for i, text := range d.texts {
+		for _, t := range searchTokens(text, true) {
+			d.tf[t] += searchWeights[i]
+			d.length += searchWeights[i]
+		}
+	}
+	return d
+}
+
+// Search 检索；allowed 非 nil 时仅返回其中的 "分组hash|接口hash"
+func (x *WikiSearchIndex) Search(ctx context.Context, keyword string, limit int, allowed map[string]struct{}) ([]SearchHit, error) {
+	if err := x.ensure(ctx); err != nil {
+		return nil, err
+	}
+	terms := searchTokens(keyword, false)
+	if len(terms) == 0 || limit <= 0 {
+		return []SearchHit{}, nil
+	}
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	n := float64(len(x.docs))
+	if n == 0 {
+		return []SearchHit{}, nil
+	}
+	avg := x.totalLen / n
+	scores := map[string]float64{}
+	matched := map[string]int{}
+	for i, t := range terms {
+		post := x.postings[t]
+		weight := 1.0
+		if len(post) == 0 && i == len(terms)-1 && isASCIIWord(t) { // 末词前缀匹配（边输边搜）
+			post = x.prefixPostings(t)
+			weight = 0.6
+		}
+		if len(post) == 0 {
+			continue
+		}
+		idf := math.Log(1 + (n-float64(len(post))+0.5)/(float64(len(post))+0.5))
+		for h, tf := range post {
+			d := x.docs[h]
+			norm := tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*d.length/avg))
+			scores[h] += weight * idf * norm
+			matched[h]++
+		}
+	}
+	hits := make([]SearchHit, 0, len(scores))
+	kw := strings.ToLower(strings.TrimSpace(keyword))
+	for h, sc := range scores {
+		d := x.docs[h]
+		if allowed != nil {
+			if _, ok := allowed[d.API.GroupHash+"|"+h]; !ok {
+				continue
+			}
+		}
+		sc *= float64(matched[h]) / float64(len(terms)) // 覆盖全部查询词的排前
+		if ac := strings.ToLower(d.API.APIClass); ac == kw {
+			sc *= 2
+		} else if strings.Contains(ac, kw) || strings.Contains(strings.ToLower(d.API.Info), kw) {
+			sc *= 1.5
+		}
+		hits = append(hits, SearchHit{ID: d.API.ID, APIClass: d.API.APIClass, Hash: h, Info: d.API.Info, GroupHash: d.API.GroupHash,
+			GroupName: d.GroupName, Status: d.API.Status, Score: math.Round(sc*1000) / 1000})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].APIClass < hits[j].APIClass
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	keys := [searchFieldCount]string{"api_class", "info", "group", "fields", "field_info"}
+	for i := range hits {
+		d := x.docs[hits[i].Hash]
+		hits[i].Highlight = map[string]string{}
+		for f, text := range d.texts {
+			if s, ok := highlight(text, terms); ok {
+				hits[i].Highlight[keys[f]] = s
+			}
+		}
+	}
+	return hits, nil
+}
+
+// prefixPostings 合并以 prefix 开头的词的倒排
+func (x *WikiSearchIndex) prefixPostings(prefix string) map[string]float64 {
+	out := map[string]float64{}
+	for t, p := range x.postings {
+		if !strings.HasPrefix(t, prefix) {
+			continue
+		}
+		for h, tf := range p {
+			if tf > out[h] {
+				out[h] = tf
+			}
+		}
+	}
+	return out
+}
+
+// ===== 分词 =====
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func isASCIIWord(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// searchTokens 分词。index=true（建索引）时中文输出单字与二元组、英文同时输出整词与拆分词；
+// 查询时中文连续两字以上只取二元组以提高精度。
+func searchTokens(s string, index bool) []string {
+	var out []string
+	var word, cjk []rune
+	flushWord := func() {
+		if len(word) == 0 {
+			return
+		}
+		parts := splitWord(string(word))
+		whole := strings.ToLower(string(word))
+		if len(parts) > 1 && index {
+			out = append(out, whole)
+		}
+		out = append(out, parts...)
+		word = word[:0]
+	}
+	flushCJK := func() {
+		switch {
+		case len(cjk) == 0:
+			return
+		case len(cjk) == 1:
+			out = append(out, string(cjk))
+		default:
+			for i := 0; i < len(cjk); i++ {
+				if index {
+					out = append(out, string(cjk[i]))
+				}
+				if i+1 < len(cjk) {
+					out = append(out, string(cjk[i:i+2]))
+				}
+			}
+		}
+		cjk = cjk[:0]
+	}
+	for _, r := range s {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return out
+}
+
+// splitWord 驼峰拆分并转小写：getUserInfo -> get user info，HTTPServer -> http server
+func splitWord(w string) []string {
+	rs := []rune(w)
+	var parts []string
+	start := 0
+	for i := 1; i < len(rs); i++ {
+		prev, cur := rs[i-1], rs[i]
+		boundary := unicode.IsLower(prev) && unicode.IsUpper(cur) ||
+			unicode.IsLetter(prev) != unicode.IsLetter(cur) ||
+			unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(rs) && unicode.IsLower(rs[i+1])
+		if boundary {
+			parts = append(parts, strings.ToLower(string(rs[start:i])))
+			start = i
+		}
+	}
+	return append(parts, strings.ToLower(string(rs[start:])))
+}
+
+// highlight 截取首个命中附近的片段并以 <em> 标记命中（文本已做 HTML 转义）
+func highlight(text string, terms []string) (string, bool) {
+	if text == "" {
+		return "", false
+	}
+	rs := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(rs) {
+		lower = rs
+	}
+	mark := make([]bool, len(rs))
+	first := -1
+	for _, t := range terms {
+		tr := []rune(t)
+		for i := 0; i+len(tr) <= len(lower); i++ {
+			if string(lower[i:i+len(tr)]) != t {
+				continue
+			}
+			for j := i; j < i+len(tr); j++ {
+				mark[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+	const window = 30
+	from, to := first-window/2, first+window
+	if from < 0 {
+		from = 0
+	}
+	if to > len(rs) {
+		to = len(rs)
+	}
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	for i := from; i < to; i++ {
+		if mark[i] && (i == from || !mark[i-1]) {
+			b.WriteString("<em>")
+		}
+		b.WriteString(html.EscapeString(string(rs[i])))
+		if mark[i] && (i == to-1 || !mark[i+1]) {
+			b.WriteString("</em>")
+		}
+	}
+	if to < len(rs) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
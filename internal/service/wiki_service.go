@@ -18,11 +18,16 @@ import (
 )
 
 type WikiService struct {
-	AppDAO    *dao.AdminAppDAO
-	GroupDAO  *dao.AdminGroupDAO
-	ListDAO   *dao.AdminInterfaceListDAO
-	FieldsDAO *dao.AdminFieldsDAO
-	Cache     cache.Cache // 使用统一 Cache 接口
+	AppDAO     *dao.AdminAppDAO
+	GroupDAO   *dao.AdminGroupDAO
+	ListDAO    *dao.AdminInterfaceListDAO
+	FieldsDAO  *dao.AdminFieldsDAO
+	Cache      cache.Cache // 使用统一 Cache 接口
+	Index      *WikiSearchIndex
+	Visibility *VisibilityResolver // 可见范围解析（应用分组模板 + 应用覆盖）
+	Revisions  *RevisionService    // 可选：接口变更记录（changelog）
+	Contracts  *ContractService    // 可选：接口契约变更事件
+	Views      *WikiViewService    // 可选：浏览统计（未配置时按旧逻辑同步累加分组 hot）
 }
 
 // WikiLoginResult 登录返回结果
@@ -47,14 +52,14 @@ type WikiUserInfo struct {
 }
 
 func NewWikiService(app *dao.AdminAppDAO, grp *dao.AdminGroupDAO, list *dao.AdminInterfaceListDAO, fields *dao.AdminFieldsDAO, c cache.Cache) *WikiService {
-	return &WikiService{AppDAO: app, GroupDAO: grp, ListDAO: list, FieldsDAO: fields, Cache: c}
+	return &WikiService{AppDAO: app, GroupDAO: grp, ListDAO: list, FieldsDAO: fields, Cache: c,
+		Index:      NewWikiSearchIndex(list, grp, fields, nil), // 单机索引；多节点由 wire 注入带 Redis 同步的共享实例
+		Visibility: NewVisibilityResolver(app, nil, list)}      // 未注入应用分组 DAO 时不支持继承
 }
 
 // 增加缓存 key 前缀常量
 const (
-	cachePrefixSearch   = "wiki:search:"
 	cachePrefixHotGroup = "wiki:hotgroups:"
-	cachePrefixFields   = "wiki:fields:"
 	cachePrefixAppInfo  = "wiki:appinfo:"
 )
 
@@ -89,8 +94,12 @@ func (s *WikiService) Logout(ctx context.Context, token string, uid int64) {
 	_ = s.Cache.Del(ctx, "WikiLogin:"+token, "WikiLogin:"+intToStr(uid))
 }
 
-// GroupList 根据 appInfo 构建分组+接口
+// GroupList 根据 appInfo 构建分组+接口；应用按可见范围（见 VisibilityResolver）过滤，只返回有可见接口的分组
 func (s *WikiService) GroupList(ctx context.Context, appInfo WikiUserInfo) ([]map[string]interface{}, error) {
+	v, err := s.Visibility.ResolveUser(ctx, appInfo)
+	if err != nil {
+		return nil, err
+	}
 	groups, err := s.GroupDAO.ListAll(ctx)
 	if err != nil {
 		return nil, err
@@ -102,132 +111,145 @@ func (s *WikiService) GroupList(ctx context.Context, appInfo WikiUserInfo) ([]ma
 	// hash -> api slice
 	apiByGroup := map[string][]model.AdminInterfaceList{}
 	for _, a := range apis {
-		apiByGroup[a.GroupHash] = append(apiByGroup[a.GroupHash], a)
+		if v.Allows(a.GroupHash, a.Hash) {
+			apiByGroup[a.GroupHash] = append(apiByGroup[a.GroupHash], a)
+		}
 	}
 	var res []map[string]interface{}
-	if appInfo.AppID == "-1" { // 后台用户全部
-		for _, g := range groups {
-			item := map[string]interface{}{"id": g.ID, "name": g.Name, "description": g.Description, "status": g.Status, "hash": g.Hash, "hot": g.Hot}
-			if apiSlice, ok := apiByGroup[g.Hash]; ok {
-				item["api_info"] = apiSlice
-			}
-			res = append(res, item)
-		}
-	} else {
-		// 解析 app_api_show JSON: { groupHash: [apiHash,...] }
-		show := parseAPIShow(appInfo.AppAPIShow)
-		// 构造 map: hash->api
-		apiMap := map[string]model.AdminInterfaceList{}
-		for _, a := range apis {
-			apiMap[a.Hash] = a
-		}
-		groupMap := map[string]model.AdminGroup{}
-		for _, g := range groups {
-			groupMap[g.Hash] = g
-		}
-		for gh, list := range show {
-			g, ok := groupMap[gh]
-			if !ok {
-				continue
-			}
-			item := map[string]interface{}{"id": g.ID, "name": g.Name, "description": g.Description, "status": g.Status, "hash": g.Hash, "hot": g.Hot}
-			var apiInfo []model.AdminInterfaceList
-			for _, ah := range list {
-				if api, ok2 := apiMap[ah]; ok2 {
-					apiInfo = append(apiInfo, api)
-				}
-			}
-			if len(apiInfo) > 0 {
-				item["api_info"] = apiInfo
-				res = append(res, item)
-			}
+	for _, g := range groups {
+		item := map[string]interface{}{"id": g.ID, "name": g.Name, "description": g.Description, "status": g.Status, "hash": g.Hash, "hot": g.Hot}
+		if apiSlice, ok := apiByGroup[g.Hash]; ok {
+			item["api_info"] = apiSlice
+		} else if v.Allowed != nil { // 后台用户保留空分组
+			continue
 		}
+		res = append(res, item)
 	}
 	return res, nil
 }
 
-func (s *WikiService) Detail(ctx context.Context, hash string, domain string) (map[string]interface{}, error) {
-	api, err := s.ListDAO.FindByHash(ctx, hash)
+// Detail 接口文档详情，可见范围校验通过后记录一次浏览（按接口 / 浏览应用，停用接口不计）；非 active 接口附带生命周期提示与替代接口
+func (s *WikiService) Detail(ctx context.Context, user WikiUserInfo, hash string, domain string) (map[string]interface{}, error) {
+	api, err := s.visibleAPI(ctx, user, hash)
 	if err != nil {
 		return nil, err
 	}
-	if api == nil {
-		return nil, errors.New("接口hash非法")
-	}
 	reqFields, _ := s.FieldsDAO.ListByHashAndType(ctx, hash, 0)
 	respFields, _ := s.FieldsDAO.ListByHashAndType(ctx, hash, 1)
-	_ = s.GroupDAO.IncrHot(ctx, api.GroupHash)
-	url := domain + "/api/" + func() string {
-		if api.HashType == 1 {
-			return api.APIClass
-		} else {
-			return api.Hash
-		}
-	}()
+	// 浏览量只记可见且启用的接口（后台预览停用接口不计入热门）
+	switch {
+	case api.Status != 1:
+	case s.Views != nil:
+		_ = s.Views.Record(ctx, WikiView{Hash: api.Hash, GroupHash: api.GroupHash, AppID: user.AppID})
+	default:
+		_ = s.GroupDAO.IncrHot(ctx, api.GroupHash)
+	}
+	url := domain + GatewayPath(*api)
 	dataType := map[int]string{0: "Integer", 1: "String", 2: "Boolean", 3: "Enum", 4: "Float", 5: "File", 6: "Array", 7: "Object", 8: "Mobile"}
+	var successor *model.AdminInterfaceList
+	if api.Replacement != "" {
+		successor, _ = s.ListDAO.FindByHash(ctx, api.Replacement)
+	}
 	return map[string]interface{}{
-		"request":  reqFields,
-		"response": respFields,
-		"dataType": dataType,
-		"apiList":  api,
-		"url":      url,
+		"lifecycle":     NewLifecycleBanner(api, successor), // active 接口为 null
+		"request":       reqFields,
+		"response":      respFields,
+		"request_tree":  BuildFieldTree(reqFields),
+		"response_tree": BuildFieldTree(respFields),
+		"dataType":      dataType,
+		"apiList":       api,
+		"url":           url,
 	}, nil
 }
 
-// Search 按关键字搜索接口(APIClass / Info 模糊匹配)，限制条数（增加缓存）
-func (s *WikiService) Search(ctx context.Context, keyword string, limit int) []map[string]interface{} {
-	keyword = strings.ToLower(strings.TrimSpace(keyword))
-	if keyword == "" || limit <= 0 {
-		return nil
+// Search 按关键字检索接口（接口名 / 说明 / 分组名 / 字段名与说明），按相关度排序并返回高亮片段；
+// 结果按用户可见范围过滤（app_id=-1 为后台全部）
+func (s *WikiService) Search(ctx context.Context, user WikiUserInfo, keyword string, limit int) ([]SearchHit, error) {
+	if strings.TrimSpace(keyword) == "" || limit <= 0 {
+		return []SearchHit{}, nil
 	}
-	ckey := cachePrefixSearch + keyword + ":" + intToStr(int64(limit))
-	if s.Cache != nil {
-		if str, err := s.Cache.Get(ctx, ckey); err == nil && str != "" {
-			if cache.IsNilSentinel(str) { // sentinel 命中
-				metrics.CacheNilHit.Inc()
-				return []map[string]interface{}{}
-			}
-			var cached []map[string]interface{}
-			_ = json.Unmarshal([]byte(str), &cached)
-			if len(cached) > 0 {
-				return cached
-			}
-		}
+	allowed, _, err := s.visibleSet(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.Index.Search(ctx, keyword, limit, allowed)
+}
+
+// ChangelogEntry wiki 变更记录
+type ChangelogEntry struct {
+	Hash      string `json:"hash"`
+	APIClass  string `json:"api_class"`
+	Info      string `json:"info"`
+	GroupName string `json:"group_name"`
+	Version   int    `json:"version"`
+	Action    string `json:"action"`
+	Summary   string `json:"summary"`
+	AddTime   int64  `json:"add_time"`
+}
+
+// Changelog 用户可见接口的最近变更（不含 baseline）；hash 非空时仅该接口
+func (s *WikiService) Changelog(ctx context.Context, user WikiUserInfo, hash string, limit int) ([]ChangelogEntry, error) {
+	out := []ChangelogEntry{}
+	if s.Revisions == nil || limit <= 0 {
+		return out, nil
 	}
-	list, err := s.ListDAO.ListAllActive(ctx)
+	apis, _, err := s.VisibleAPIs(ctx, user)
 	if err != nil {
-		return nil
+		return nil, err
 	}
-	res := make([]map[string]interface{}, 0, limit)
-	for _, v := range list {
-		if len(res) >= limit {
-			break
-		}
-		lc := strings.ToLower(v.APIClass)
-		li := strings.ToLower(v.Info)
-		if strings.Contains(lc, keyword) || strings.Contains(li, keyword) {
-			res = append(res, map[string]interface{}{
-				"id": v.ID, "api_class": v.APIClass, "hash": v.Hash, "info": v.Info, "group_hash": v.GroupHash, "status": v.Status,
-			})
+	byHash := make(map[string]WikiAPI, len(apis))
+	hashes := make([]string, 0, len(apis))
+	for _, a := range apis {
+		if hash == "" || a.API.Hash == hash {
+			byHash[a.API.Hash] = a
+			hashes = append(hashes, a.API.Hash)
 		}
 	}
-	if s.Cache != nil {
-		if len(res) == 0 { // 空结果防穿透
-			_ = s.Cache.SetEX(ctx, ckey, cache.WrapNil(true), 10*time.Second)
-			return res
+	if hash != "" && len(hashes) == 0 {
+		return nil, errors.New("接口不存在或无权查看")
+	}
+	list, err := s.Revisions.DAO.Recent(ctx, hashes, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range list {
+		a := byHash[r.Hash]
+		out = append(out, ChangelogEntry{Hash: r.Hash, APIClass: a.API.APIClass, Info: a.API.Info, GroupName: a.Group.Name,
+			Version: r.Version, Action: r.Action, Summary: r.Summary, AddTime: r.AddTime})
+	}
+	return out, nil
+}
+
+// ContractEvents 应用已授权接口的契约变更事件（后台用户为全部），since_id 增量拉取
+func (s *WikiService) ContractEvents(ctx context.Context, user WikiUserInfo, q dao.ContractEventQuery) (*ListContractEventsResult, error) {
+	if s.Contracts == nil {
+		return &ListContractEventsResult{List: []ContractEvent{}}, nil
+	}
+	_, app, err := s.visibleSet(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if app != nil { // 按授权列表而非展示列表：停用、删除的接口同样需要通知
+		hashes := []string{}
+		for _, h := range strings.Split(app.AppAPI, ",") {
+			if h = strings.TrimSpace(h); h != "" && (len(q.Hashes) == 0 || h == q.Hashes[0]) {
+				hashes = append(hashes, h)
+			}
 		}
-		b, _ := json.Marshal(res)
-		_ = s.Cache.SetEX(ctx, ckey, string(b), 30*time.Second)
+		q.Hashes = hashes
 	}
-	return res
+	return s.Contracts.Events(ctx, q)
 }
 
-// HotGroups 返回最热分组（按 Hot 值倒序）（增加缓存）
-func (s *WikiService) HotGroups(ctx context.Context, limit int) []map[string]interface{} {
+// HotGroups 返回最热分组（增加缓存）；hours=0 按全部时间热度（Hot 值）倒序，否则按最近 hours 小时浏览量，hot 为区间内浏览量
+func (s *WikiService) HotGroups(ctx context.Context, limit, hours int) []map[string]interface{} {
 	if limit <= 0 {
 		return nil
 	}
 	ckey := cachePrefixHotGroup + intToStr(int64(limit))
+	if hours > 0 {
+		ckey += ":" + intToStr(int64(hours)) + "h"
+	}
 	if s.Cache != nil {
 		if str, err := s.Cache.Get(ctx, ckey); err == nil && str != "" {
 			if cache.IsNilSentinel(str) {
@@ -245,7 +267,25 @@ func (s *WikiService) HotGroups(ctx context.Context, limit int) []map[string]int
 	if err != nil {
 		return nil
 	}
-	sort.Slice(groups, func(i, j int) bool { return groups[i].Hot > groups[j].Hot })
+	if hours > 0 && s.Views != nil {
+		counts, err := s.Views.Top(ctx, ViewDimGroup, "", hours)
+		if err != nil {
+			return nil
+		}
+		byHash := make(map[string]model.AdminGroup, len(groups))
+		for _, g := range groups {
+			byHash[g.Hash] = g
+		}
+		groups = groups[:0]
+		for _, c := range counts {
+			if g, ok := byHash[c.Key]; ok {
+				g.Hot = c.Views
+				groups = append(groups, g)
+			}
+		}
+	} else {
+		sort.Slice(groups, func(i, j int) bool { return groups[i].Hot > groups[j].Hot })
+	}
 	if len(groups) > limit {
 		groups = groups[:limit]
 	}
@@ -264,20 +304,62 @@ func (s *WikiService) HotGroups(ctx context.Context, limit int) []map[string]int
 	return res
 }
 
-// Fields 获取请求与响应字段（增加缓存）
-func (s *WikiService) Fields(ctx context.Context, hash string) (map[string]interface{}, error) {
+// HotAPI wiki 最多浏览接口
+type HotAPI struct {
+	Hash      string `json:"hash"`
+	APIClass  string `json:"api_class"`
+	Info      string `json:"info"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	GroupHash string `json:"group_hash"`
+	GroupName string `json:"group_name"`
+	Lifecycle string `json:"lifecycle"`
+	Views     int64  `json:"views"`
+}
+
+// HotAPIs 用户可见接口按最近 hours 小时浏览量倒序（hours=0 为全部时间）；mine 为 true 时仅统计当前应用的浏览
+func (s *WikiService) HotAPIs(ctx context.Context, user WikiUserInfo, hours, limit int, mine bool) ([]HotAPI, error) {
+	out := []HotAPI{}
+	if s.Views == nil || limit <= 0 {
+		return out, nil
+	}
+	apis, _, err := s.VisibleAPIs(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	byHash := make(map[string]WikiAPI, len(apis))
+	for _, a := range apis {
+		byHash[a.API.Hash] = a
+	}
+	appID := ""
+	if mine {
+		appID = user.AppID
+	}
+	counts, err := s.Views.Top(ctx, ViewDimAPI, appID, hours)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		a, ok := byHash[c.Key]
+		if !ok || c.Views <= 0 {
+			continue
+		}
+		out = append(out, HotAPI{Hash: a.API.Hash, APIClass: a.API.APIClass, Info: a.API.Info, Method: InterfaceMethod(a.API.Method),
+			Path: GatewayPath(a.API), GroupHash: a.Group.Hash, GroupName: a.Group.Name, Lifecycle: InterfaceLifecycle(&a.API), Views: c.Views})
+		if len(out) >= limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Fields 获取请求与响应字段；按用户可见范围校验（不做共享缓存，避免绕过校验）
+func (s *WikiService) Fields(ctx context.Context, user WikiUserInfo, hash string) (map[string]interface{}, error) {
 	if strings.TrimSpace(hash) == "" {
 		return nil, errors.New("hash required")
 	}
-	ckey := cachePrefixFields + hash
-	if s.Cache != nil {
-		if str, err := s.Cache.Get(ctx, ckey); err == nil && str != "" {
-			var m map[string]interface{}
-			_ = json.Unmarshal([]byte(str), &m)
-			if len(m) > 0 {
-				return m, nil
-			}
-		}
+	if _, err := s.visibleAPI(ctx, user, hash); err != nil {
+		return nil, err
 	}
 	req, err := s.FieldsDAO.ListByHashAndType(ctx, hash, 0)
 	if err != nil {
@@ -287,12 +369,29 @@ func (s *WikiService) Fields(ctx context.Context, hash string) (map[string]inter
 	if err != nil {
 		return nil, err
 	}
-	res := map[string]interface{}{"request": req, "response": resp, "dataType": s.DataTypeMap()}
-	if s.Cache != nil {
-		b, _ := json.Marshal(res)
-		_ = s.Cache.SetEX(ctx, ckey, string(b), 120*time.Second)
+	return map[string]interface{}{"request": req, "response": resp, "dataType": s.DataTypeMap(),
+		"request_tree": BuildFieldTree(req), "response_tree": BuildFieldTree(resp)}, nil
+}
+
+// visibleAPI 按 hash 读取接口并校验用户可见范围（后台用户不限）；不存在或不可见均返回 接口hash非法
+func (s *WikiService) visibleAPI(ctx context.Context, user WikiUserInfo, hash string) (*model.AdminInterfaceList, error) {
+	allowed, _, err := s.visibleSet(ctx, user)
+	if err != nil {
+		return nil, err
 	}
-	return res, nil
+	api, err := s.ListDAO.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if api == nil {
+		return nil, errors.New("接口hash非法")
+	}
+	if allowed != nil {
+		if _, ok := allowed[api.GroupHash+"|"+api.Hash]; !ok {
+			return nil, errors.New("接口hash非法")
+		}
+	}
+	return api, nil
 }
 
 // AppInfo 获取当前登录应用信息（后台登录 app_id=-1 则返回空）（增加缓存）
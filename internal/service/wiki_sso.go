@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// 后台登录态换取 wiki 会话：校验后台 JWT 与 JTI 后写入 Login:{token}（NewWikiAuth 视为 app_id=-1 的后台用户，可见全部接口），
+// 并按 JTI 记录会话集合 wiki:sso:{jti}，后台登出时一并吊销；会话内保留 jti，wiki 鉴权时再次校验 JTI 是否仍有效。
+const (
+	WikiAdminSessionPrefix = "Login:"
+	wikiSSOIndexPrefix     = "wiki:sso:"
+)
+
+var (
+	ErrSSOToken   = errors.New("invalid token")
+	ErrSSOExpired = errors.New("token expired")
+	ErrSSOUser    = errors.New("user disabled")
+)
+
+// WikiSSOResult wiki 单点登录结果，ApiAuth 与应用登录返回的字段同名
+type WikiSSOResult struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Nickname string `json:"nickname"`
+	AppID    string `json:"app_id"` // 固定 -1（后台用户）
+	ApiAuth  string `json:"apiAuth"`
+	ExpireIn int64  `json:"expire_in"` // 秒，不超过后台 JWT 剩余有效期
+}
+
+// wikiAdminSession Login:{token} 存储内容
+type wikiAdminSession struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Nickname string `json:"nickname"`
+	AppID    string `json:"app_id"`
+	JTI      string `json:"jti"`
+}
+
+// WikiSSO 校验后台 access token（签名、过期、JTI 未登出）并签发 wiki 会话，有效期取 ttl 与 JWT 剩余有效期的较小值
+func (s *AuthService) WikiSSO(ctx context.Context, accessToken string, ttl time.Duration) (*WikiSSOResult, error) {
+	if s.Redis == nil {
+		return nil, errors.New("redis not configured")
+	}
+	claims, err := s.JWT.Parse(accessToken)
+	if err != nil || claims.JTI == "" {
+		return nil, ErrSSOToken
+	}
+	if s.Redis.Get(ctx, s.redisJTIPrefix()+claims.JTI) == "" {
+		return nil, ErrSSOExpired
+	}
+	user, err := s.Users.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Status != 1 {
+		return nil, ErrSSOUser
+	}
+	if claims.ExpiresAt != nil {
+		if rem := time.Until(claims.ExpiresAt.Time); rem < ttl {
+			ttl = rem
+		}
+	}
+	if ttl < time.Second {
+		return nil, ErrSSOExpired
+	}
+	token := generateToken()
+	b, _ := json.Marshal(wikiAdminSession{ID: user.ID, Username: user.Username, Nickname: user.Nickname, AppID: "-1", JTI: claims.JTI})
+	idx := wikiSSOIndexPrefix + claims.JTI
+	pipe := s.Redis.Client.TxPipeline()
+	pipe.Set(ctx, WikiAdminSessionPrefix+token, string(b), ttl)
+	pipe.SAdd(ctx, idx, token)
+	pipe.Expire(ctx, idx, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return &WikiSSOResult{ID: user.ID, Username: user.Username, Nickname: user.Nickname, AppID: "-1", ApiAuth: token, ExpireIn: int64(ttl / time.Second)}, nil
+}
+
+// WikiSSOLogout wiki 端登出单个后台会话
+func (s *AuthService) WikiSSOLogout(ctx context.Context, apiAuth string) error {
+	if s.Redis == nil || apiAuth == "" {
+		return nil
+	}
+	key := WikiAdminSessionPrefix + apiAuth
+	var sess wikiAdminSession
+	if v := s.Redis.Get(ctx, key); v != "" && json.Unmarshal([]byte(v), &sess) == nil && sess.JTI != "" {
+		_ = s.Redis.Client.SRem(ctx, wikiSSOIndexPrefix+sess.JTI, apiAuth).Err()
+	}
+	return s.Redis.Client.Del(ctx, key).Err()
+}
+
+// revokeWikiSSO 吊销该 JTI 换取的全部 wiki 会话
+func (s *AuthService) revokeWikiSSO(ctx context.Context, jti string) error {
+	idx := wikiSSOIndexPrefix + jti
+	tokens, err := s.Redis.Client.SMembers(ctx, idx).Result()
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(tokens)+1)
+	for _, t := range tokens {
+		keys = append(keys, WikiAdminSessionPrefix+t)
+	}
+	keys = append(keys, idx)
+	return s.Redis.Client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	htmltpl "html/template"
+	"io"
+	"sort"
+	"strings"
+	texttpl "text/template"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/util/retcode"
+)
+
+// 离线静态文档：将可见接口渲染为 HTML 或 Markdown 并打包为 zip，
+// 包含分组索引、接口详情页（字段表 / 示例）、错误码表与数据类型说明，可直接发布到内网或交付客户。
+const (
+	StaticHTML     = "html"
+	StaticMarkdown = "markdown"
+)
+
+var ErrStaticFormat = errors.New("format 取值应为 html / markdown")
+
+// StaticDocOptions 静态文档参数
+type StaticDocOptions struct {
+	Format  string
+	Title   string
+	Version string
+	BaseURL string
+	User    WikiUserInfo
+}
+
+type staticField struct {
+	Name, Type, Must, Default, Range, Info string
+}
+
+type staticAPI struct {
+	Name, APIClass, Hash, Method, URL, File string
+	AccessToken                             bool
+	Request, Response                       []staticField
+	RequestExample, ResponseExample         string
+}
+
+type staticGroup struct {
+	Name, Description, Hash string
+	APIs                    []*staticAPI
+}
+
+type staticCode struct {
+	Name    string
+	Code    int
+	Message string
+}
+
+type staticDataType struct {
+	ID   int
+	Name string
+}
+
+type staticSite struct {
+	Title, Version, App, BaseURL, Generated string
+	Signed                                  bool
+	Groups                                  []*staticGroup
+	Codes                                   []staticCode
+	DataTypes                               []staticDataType
+}
+
+// StaticDocs 生成静态文档 zip 写入 w
+func (s *WikiService) StaticDocs(ctx context.Context, opt StaticDocOptions, w io.Writer) error {
+	if opt.Format != StaticHTML && opt.Format != StaticMarkdown {
+		return ErrStaticFormat
+	}
+	site, err := s.staticSite(ctx, opt)
+	if err != nil {
+		return err
+	}
+	ext := ".html"
+	if opt.Format == StaticMarkdown {
+		ext = ".md"
+	}
+	for _, g := range site.Groups {
+		for _, a := range g.APIs {
+			a.File = "api/" + a.Hash + ext
+		}
+	}
+	zw := zip.NewWriter(w)
+	render := func(name string, exec func(io.Writer) error) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			return err
+		}
+		return exec(f)
+	}
+	if opt.Format == StaticHTML {
+		err = renderStaticHTML(site, render)
+	} else {
+		err = renderStaticMarkdown(site, render)
+	}
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func (s *WikiService) staticSite(ctx context.Context, opt StaticDocOptions) (*staticSite, error) {
+	list, app, err := s.VisibleAPIs(ctx, opt.User)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.fieldsByHash(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	site := &staticSite{Title: opt.Title, Version: opt.Version, BaseURL: opt.BaseURL, Generated: time.Now().Format("2006-01-02 15:04:05")}
+	if app != nil {
+		site.App = app.AppName + "（" + app.AppID + "）"
+		site.Signed = app.SignEnable == 1
+	}
+	byGroup := map[string]*staticGroup{}
+	for _, item := range list {
+		api := item.API
+		g := byGroup[item.Group.Hash]
+		if g == nil {
+			g = &staticGroup{Name: item.Group.Name, Description: item.Group.Description, Hash: item.Group.Hash}
+			byGroup[item.Group.Hash] = g
+			site.Groups = append(site.Groups, g)
+		}
+		defs := fields[api.Hash]
+		a := &staticAPI{
+			Name: firstNonEmpty(api.Info, api.APIClass), APIClass: api.APIClass, Hash: api.Hash,
+			Method: InterfaceMethod(api.Method), URL: opt.BaseURL + GatewayPath(api), AccessToken: api.AccessToken == 1,
+			Request: staticFields(defs[0]), Response: staticFields(defs[1]),
+		}
+		if a.Method == "*" {
+			a.Method = "GET / POST"
+		}
+		if len(defs[0]) > 0 {
+			b, _ := json.MarshalIndent(FieldsExample(defs[0]), "", "  ")
+			a.RequestExample = string(b)
+		}
+		a.ResponseExample = prettyJSON(api.ReturnStr)
+		g.APIs = append(g.APIs, a)
+	}
+	for name, c := range retcode.All() {
+		site.Codes = append(site.Codes, staticCode{Name: name, Code: c.Code, Message: c.Message})
+	}
+	sort.Slice(site.Codes, func(i, j int) bool { return site.Codes[i].Code > site.Codes[j].Code })
+	for id, name := range DataTypeMap() {
+		site.DataTypes = append(site.DataTypes, staticDataType{ID: id, Name: name})
+	}
+	sort.Slice(site.DataTypes, func(i, j int) bool { return site.DataTypes[i].ID < site.DataTypes[j].ID })
+	return site, nil
+}
+
+func staticFields(defs []model.AdminField) []staticField {
+	out := make([]staticField, 0, len(defs))
+	for _, f := range defs {
+		must := "否"
+		if f.IsMust == 1 {
+			must = "是"
+		}
+		info := strings.TrimSpace(f.Info)
+		if info == "" && f.ShowName != f.FieldName {
+			info = f.ShowName
+		}
+		out = append(out, staticField{Name: FieldPath(f), Type: dataTypeMap[int(f.DataType)], Must: must, Default: f.Default, Range: f.Range, Info: info})
+	}
+	return out
+}
+
+// prettyJSON 合法 JSON 时格式化输出，否则原样返回
+func prettyJSON(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(s), "", "  "); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
+// ===== HTML =====
+
+const staticCSS = `body{font-family:-apple-system,"PingFang SC","Microsoft YaHei",sans-serif;margin:0;color:#222}
+header{background:#1f2d3d;color:#fff;padding:12px 24px}header a{color:#fff;margin-right:16px;text-decoration:none}
+main{padding:16px 24px;max-width:1100px}table{border-collapse:collapse;width:100%;margin:8px 0 16px}
+th,td{border:1px solid #ddd;padding:6px 8px;text-align:left;font-size:14px;vertical-align:top}th{background:#f5f7fa}
+pre{background:#f5f7fa;padding:12px;overflow:auto;font-size:13px}code{background:#f5f7fa;padding:1px 4px}
+.tag{display:inline-block;background:#409eff;color:#fff;border-radius:3px;padding:0 6px;font-size:12px}`
+
+const staticHTMLLayout = `{{define "head"}}<!DOCTYPE html><html lang="zh-CN"><head><meta charset="utf-8"><title>{{.Page}} - {{.Site.Title}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css"></head><body><header><a href="{{.Root}}index.html">{{.Site.Title}} {{.Site.Version}}</a>
+<a href="{{.Root}}error-codes.html">错误码</a><a href="{{.Root}}data-types.html">数据类型</a></header><main>{{end}}
+{{define "foot"}}<hr><p style="color:#999;font-size:12px">生成于 {{.Site.Generated}}</p></main></body></html>{{end}}
+{{define "fields"}}<table><tr><th>字段</th><th>类型</th><th>必填</th><th>默认值</th><th>范围</th><th>说明</th></tr>
+{{range .}}<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{.Must}}</td><td>{{.Default}}</td><td>{{.Range}}</td><td>{{.Info}}</td></tr>{{else}}<tr><td colspan="6">无</td></tr>{{end}}</table>{{end}}
+{{define "index"}}{{template "head" .}}<h1>{{.Site.Title}}</h1>{{if .Site.App}}<p>应用：{{.Site.App}}</p>{{end}}
+<p>网关地址：<code>{{.Site.BaseURL}}</code>；需要令牌的接口携带 <code>Access-Token</code> 头（<code>POST /gateway/accessToken</code> 以 app_id + app_secret 换取）。
+{{if .Site.Signed}}该应用已开启请求签名，需携带 <code>X-App-Id / X-Timestamp / X-Nonce / X-Signature</code>。{{end}}</p>
+{{range .Site.Groups}}<h2>{{.Name}}</h2>{{if .Description}}<p>{{.Description}}</p>{{end}}
+<table><tr><th>接口</th><th>方法</th><th>api_class</th><th>令牌</th></tr>{{range .APIs}}
+<tr><td><a href="{{.File}}">{{.Name}}</a></td><td>{{.Method}}</td><td><code>{{.APIClass}}</code></td><td>{{if .AccessToken}}需要{{else}}-{{end}}</td></tr>{{end}}</table>
+{{else}}<p>暂无可见接口</p>{{end}}{{template "foot" .}}{{end}}
+{{define "api"}}{{template "head" .}}{{with .API}}<h1>{{.Name}}</h1>
+<p><span class="tag">{{.Method}}</span> <code>{{.URL}}</code></p>
+<table><tr><th>api_class</th><td><code>{{.APIClass}}</code></td></tr><tr><th>hash</th><td><code>{{.Hash}}</code></td></tr>
+<tr><th>分组</th><td>{{$.Group}}</td></tr><tr><th>Access-Token</th><td>{{if .AccessToken}}需要{{else}}不需要{{end}}</td></tr></table>
+<h2>请求字段</h2>{{template "fields" .Request}}{{if .RequestExample}}<h3>请求示例</h3><pre>{{.RequestExample}}</pre>{{end}}
+<h2>响应字段</h2><p>响应统一为 <code>{"code": 1, "msg": "success", "data": ...}</code>，下表为 data 内容。</p>{{template "fields" .Response}}
+{{if .ResponseExample}}<h3>响应示例</h3><pre>{{.ResponseExample}}</pre>{{end}}{{end}}{{template "foot" .}}{{end}}
+{{define "codes"}}{{template "head" .}}<h1>错误码</h1><table><tr><th>code</th><th>标识</th><th>说明</th></tr>
+{{range .Site.Codes}}<tr><td>{{.Code}}</td><td><code>{{.Name}}</code></td><td>{{.Message}}</td></tr>{{end}}</table>{{template "foot" .}}{{end}}
+{{define "types"}}{{template "head" .}}<h1>数据类型</h1><table><tr><th>编号</th><th>类型</th></tr>
+{{range .Site.DataTypes}}<tr><td>{{.ID}}</td><td>{{.Name}}</td></tr>{{end}}</table>
+<p>字段名中的 <code>.</code> 表示层级（如 <code>user.name</code>），父字段为 Array 时子字段描述数组元素；range 为数值范围 / 长度范围 / 枚举候选。</p>{{template "foot" .}}{{end}}`
+
+var staticHTMLTpl = htmltpl.Must(htmltpl.New("static").Parse(staticHTMLLayout))
+
+type staticPage struct {
+	Site  *staticSite
+	Page  string
+	Root  string
+	Group string
+	API   *staticAPI
+}
+
+func renderStaticHTML(site *staticSite, render func(string, func(io.Writer) error) error) error {
+	if err := render("style.css", func(w io.Writer) error { _, err := io.WriteString(w, staticCSS); return err }); err != nil {
+		return err
+	}
+	pages := []struct{ file, tpl, title string }{{"index.html", "index", "接口索引"}, {"error-codes.html", "codes", "错误码"}, {"data-types.html", "types", "数据类型"}}
+	for _, p := range pages {
+		page := staticPage{Site: site, Page: p.title}
+		if err := render(p.file, func(w io.Writer) error { return staticHTMLTpl.ExecuteTemplate(w, p.tpl, page) }); err != nil {
+			return err
+		}
+	}
+	for _, g := range site.Groups {
+		for _, a := range g.APIs {
+			page := staticPage{Site: site, Page: a.Name, Root: "../", Group: g.Name, API: a}
+			if err := render(a.File, func(w io.Writer) error { return staticHTMLTpl.ExecuteTemplate(w, "api", page) }); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ===== Markdown =====
+
+const staticMDLayout = `{{define "fields"}}| 字段 | 类型 | 必填 | 默认值 | 范围 | 说明 |
+|------|------|------|--------|------|------|
+{{range .}}| ` + "`{{md .Name}}`" + ` | {{.Type}} | {{.Must}} | {{md .Default}} | {{md .Range}} | {{md .Info}} |
+{{else}}| 无 | | | | | |
+{{end}}{{end}}
+{{define "index"}}# {{.Site.Title}} {{.Site.Version}}
+{{if .Site.App}}
+应用：{{.Site.App}}
+{{end}}
+网关地址：` + "`{{.Site.BaseURL}}`" + `；需要令牌的接口携带 ` + "`Access-Token`" + ` 头（` + "`POST /gateway/accessToken`" + ` 以 app_id + app_secret 换取）。{{if .Site.Signed}}该应用已开启请求签名，需携带 ` + "`X-App-Id / X-Timestamp / X-Nonce / X-Signature`" + `。{{end}}
+
+- [错误码](error-codes.md)
+- [数据类型](data-types.md)
+{{range .Site.Groups}}
+## {{.Name}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+| 接口 | 方法 | api_class | 令牌 |
+|------|------|-----------|------|
+{{range .APIs}}| [{{md .Name}}]({{.File}}) | {{.Method}} | ` + "`{{md .APIClass}}`" + ` | {{if .AccessToken}}需要{{else}}-{{end}} |
+{{end}}{{else}}
+暂无可见接口
+{{end}}
+> 生成于 {{.Site.Generated}}
+{{end}}
+{{define "api"}}{{with .API}}# {{.Name}}
+
+[返回索引](../README.md)
+
+- 地址：` + "`{{.Method}} {{.URL}}`" + `
+- api_class：` + "`{{.APIClass}}`" + `
+- hash：` + "`{{.Hash}}`" + `
+- 分组：{{$.Group}}
+- Access-Token：{{if .AccessToken}}需要{{else}}不需要{{end}}
+
+## 请求字段
+
+{{template "fields" .Request}}{{if .RequestExample}}
+### 请求示例
+
+` + "```json\n{{.RequestExample}}\n```" + `
+{{end}}
+## 响应字段
+
+响应统一为 ` + "`{\"code\": 1, \"msg\": \"success\", \"data\": ...}`" + `，下表为 data 内容。
+
+{{template "fields" .Response}}{{if .ResponseExample}}
+### 响应示例
+
+` + "```json\n{{.ResponseExample}}\n```" + `
+{{end}}{{end}}{{end}}
+{{define "codes"}}# 错误码
+
+[返回索引](README.md)
+
+| code | 标识 | 说明 |
+|------|------|------|
+{{range .Site.Codes}}| {{.Code}} | ` + "`{{.Name}}`" + ` | {{md .Message}} |
+{{end}}{{end}}
+{{define "types"}}# 数据类型
+
+[返回索引](README.md)
+
+| 编号 | 类型 |
+|------|------|
+{{range .Site.DataTypes}}| {{.ID}} | {{.Name}} |
+{{end}}
+字段名中的 ` + "`.`" + ` 表示层级（如 ` + "`user.name`" + `），父字段为 Array 时子字段描述数组元素；range 为数值范围 / 长度范围 / 枚举候选。
+{{end}}`
+
+// mdCell 转义表格单元格中的竖线与换行
+func mdCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace(s)
+}
+
+var staticMDTpl = texttpl.Must(texttpl.New("static").Funcs(texttpl.FuncMap{"md": mdCell}).Parse(staticMDLayout))
+
+func renderStaticMarkdown(site *staticSite, render func(string, func(io.Writer) error) error) error {
+	pages := []struct{ file, tpl string }{{"README.md", "index"}, {"error-codes.md", "codes"}, {"data-types.md", "types"}}
+	for _, p := range pages {
+		page := staticPage{Site: site}
+		if err := render(p.file, func(w io.Writer) error { return staticMDTpl.ExecuteTemplate(w, p.tpl, page) }); err != nil {
+			return err
+		}
+	}
+	for _, g := range site.Groups {
+		for _, a := range g.APIs {
+			page := staticPage{Site: site, Group: g.Name, API: a}
+			if err := render(a.File, func(w io.Writer) error { return staticMDTpl.ExecuteTemplate(w, "api", page) }); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/repository/dao"
+	redisrepo "go-apiadmin/internal/repository/redis"
+)
+
+// TryItService wiki 在线调试：按请求字段默认值补齐用户参数，以登录应用的身份（令牌 / 签名）
+// 经网关调用接口，返回状态码、响应头、响应体与耗时；按用户每分钟限次，并逐次写审计记录。
+type TryItService struct {
+	Wiki    *WikiService
+	Fields  *FieldsService
+	Tokens  *AppTokenService
+	DAO     *dao.AdminTryItLogDAO
+	Redis   *redisrepo.Client
+	Client  *http.Client
+	BaseURL string        // 网关地址，请求 {BaseURL}/api/{hash|api_class}
+	Timeout time.Duration // 单次调试超时
+	Rate    int           // 每用户每分钟次数，<=0 不限
+	MaxBody int64         // 返回的响应体上限（字节）
+}
+
+// TryItParams 调试请求
+type TryItParams struct {
+	Hash    string                 `json:"hash"`
+	Method  string                 `json:"method"`  // 接口不限方法时生效，默认 POST
+	Params  map[string]interface{} `json:"params"`  // 请求参数，缺省字段按 default 补齐
+	Headers map[string]string      `json:"headers"` // 附加请求头（凭据相关头由服务端生成，不可覆盖）
+	AppID   string                 `json:"app_id"`  // 仅后台登录：以指定应用身份调用
+}
+
+// TryItRequest 实际发送的请求（Access-Token / 签名已打码）
+type TryItRequest struct {
+	Method string            `json:"method"`
+	URL    string            `json:"url"`
+	Header map[string]string `json:"header"`
+	Body   string            `json:"body"`
+}
+
+// TryItResult 调试结果
+type TryItResult struct {
+	Request    TryItRequest      `json:"request"`
+	Status     int               `json:"status"`
+	Header     map[string]string `json:"header"`
+	Body       string            `json:"body"`
+	Truncated  bool              `json:"truncated"` // 响应体超过上限被截断
+	DurationMS int64             `json:"duration_ms"`
+	Validation []FieldError      `json:"validation"` // 按字段定义的校验提示，不阻止发送
+	Error      string            `json:"error,omitempty"`
+}
+
+// TryItTemplate 调试表单：接口信息与按默认值预填的参数
+type TryItTemplate struct {
+	Hash        string                 `json:"hash"`
+	APIClass    string                 `json:"api_class"`
+	Info        string                 `json:"info"`
+	Method      string                 `json:"method"` // "*" 表示不限
+	URL         string                 `json:"url"`
+	AccessToken bool                   `json:"access_token"` // 需要 Access-Token（由服务端代为签发）
+	Sign        bool                   `json:"sign"`         // 当前应用开启签名（由服务端代为签名）
+	Params      map[string]interface{} `json:"params"`
+	Fields      []model.AdminField     `json:"fields"`
+}
+
+var (
+	ErrTryItDisabled = errors.New("在线调试未开启")
+	ErrTryItDenied   = errors.New("接口不存在或无权调试")
+	ErrTryItLimited  = errors.New("调试过于频繁，请稍后再试")
+	ErrTryItApp      = errors.New("调试应用不存在或已禁用")
+)
+
+const (
+	tryItRatePrefix = "tryit:rl:"
+	tryItParamsMax  = 4000
+)
+
+// tryItReservedHeaders 由服务端生成或不应透传的请求头
+var tryItReservedHeaders = map[string]struct{}{
+	"Access-Token": {}, "Authorization": {}, "X-App-Id": {}, "X-Timestamp": {}, "X-Nonce": {}, "X-Signature": {},
+	"Host": {}, "Content-Length": {}, "Cookie": {}, "X-Forwarded-For": {},
+}
+
+func NewTryItService(wiki *WikiService, fields *FieldsService, tokens *AppTokenService, d *dao.AdminTryItLogDAO, r *redisrepo.Client, baseURL string, timeout time.Duration, rate int, maxBody int64) *TryItService {
+	return &TryItService{Wiki: wiki, Fields: fields, Tokens: tokens, DAO: d, Redis: r, Client: &http.Client{},
+		BaseURL: strings.TrimRight(baseURL, "/"), Timeout: timeout, Rate: rate, MaxBody: maxBody}
+}
+
+// LoopbackURL 由监听地址得到本机访问地址（如 ":8080" -> http://127.0.0.1:8080）
+func LoopbackURL(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return ""
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// Template 调试表单（参数按请求字段 default 预填）
+func (s *TryItService) Template(ctx context.Context, user WikiUserInfo, hash string) (*TryItTemplate, error) {
+	if s == nil || s.BaseURL == "" {
+		return nil, ErrTryItDisabled
+	}
+	api, app, err := s.resolve(ctx, user, hash, "")
+	if err != nil {
+		return nil, err
+	}
+	defs, err := s.Fields.RequestFields(ctx, api.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return &TryItTemplate{Hash: api.Hash, APIClass: api.APIClass, Info: api.Info, Method: InterfaceMethod(api.Method),
+		URL: s.BaseURL + GatewayPath(*api), AccessToken: api.AccessToken == 1, Sign: app != nil && app.SignEnable == 1,
+		Params: ValidateFields(defs, nil).Payload, Fields: defs}, nil
+}
+
+// TryIt 发送调试请求；调用失败（网络错误 / 超时）时 Error 非空，仍返回已发送的请求与耗时
+func (s *TryItService) TryIt(ctx context.Context, user WikiUserInfo, p TryItParams, clientIP string) (*TryItResult, error) {
+	if s == nil || s.BaseURL == "" {
+		return nil, ErrTryItDisabled
+	}
+	api, app, err := s.resolve(ctx, user, p.Hash, p.AppID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.allow(ctx, user); err != nil {
+		return nil, err
+	}
+	check, err := s.Fields.Validate(ctx, api.Hash, p.Params)
+	if err != nil {
+		return nil, err
+	}
+	method := InterfaceMethod(api.Method)
+	if method == "*" {
+		switch method = strings.ToUpper(strings.TrimSpace(p.Method)); method {
+		case "":
+			method = http.MethodPost
+		case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions:
+		default:
+			return nil, ErrGatewayMethod
+		}
+	}
+	target := s.BaseURL + GatewayPath(*api)
+	var body []byte
+	header := http.Header{}
+	for k, v := range p.Headers {
+		k = http.CanonicalHeaderKey(strings.TrimSpace(k))
+		if _, ok := tryItReservedHeaders[k]; !ok && k != "" {
+			header.Set(k, v)
+		}
+	}
+	rawQuery := ""
+	if queryMethod(method) {
+		rawQuery = tryItQuery(check.Payload).Encode()
+		if rawQuery != "" {
+			target += "?" + rawQuery
+		}
+	} else {
+		body, _ = json.Marshal(check.Payload)
+		header.Set("Content-Type", "application/json")
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+	if app != nil {
+		if api.AccessToken == 1 { // 临时令牌，调试结束即作废
+			tok, err := s.Tokens.IssueFor(ctx, app, s.Timeout+time.Minute)
+			if err != nil {
+				return nil, err
+			}
+			defer s.Tokens.Revoke(context.WithoutCancel(ctx), tok.AccessToken)
+			header.Set("Access-Token", tok.AccessToken)
+		}
+		if app.SignEnable == 1 {
+			u, _ := url.Parse(target)
+			ts, nonce := strconv.FormatInt(time.Now().Unix(), 10), generateToken()
+			header.Set("X-App-Id", app.AppID)
+			header.Set("X-Timestamp", ts)
+			header.Set("X-Nonce", nonce)
+			header.Set("X-Signature", Sign(app.AppSecret, CanonicalString(SignRequest{
+				Timestamp: ts, Nonce: nonce, Method: method, Path: u.Path, RawQuery: rawQuery, Body: body,
+			})))
+		}
+	}
+	res := &TryItResult{Request: TryItRequest{Method: method, URL: target, Header: flatHeader(header), Body: string(body)},
+		Header: map[string]string{}, Validation: check.Errors}
+	for _, k := range []string{"Access-Token", "X-Signature"} {
+		if _, ok := res.Request.Header[k]; ok {
+			res.Request.Header[k] = "******"
+		}
+	}
+	start := time.Now()
+	err = s.send(ctx, method, target, header, body, res)
+	res.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		res.Error = err.Error()
+	}
+	s.audit(ctx, user, app, api, res, check.Payload, clientIP)
+	return res, nil
+}
+
+func (s *TryItService) send(ctx context.Context, method, target string, header http.Header, body []byte, res *TryItResult) error {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	res.Status = resp.StatusCode
+	res.Header = flatHeader(cloneHeader(resp.Header))
+	b, err := io.ReadAll(io.LimitReader(resp.Body, s.MaxBody+1))
+	if s.MaxBody > 0 && int64(len(b)) > s.MaxBody {
+		b, res.Truncated = b[:s.MaxBody], true
+	}
+	res.Body = string(b)
+	return err
+}
+
+// resolve 校验接口对当前用户可见；返回调用身份应用（后台登录未指定 appID 时为 nil）
+func (s *TryItService) resolve(ctx context.Context, user WikiUserInfo, hash, appID string) (*model.AdminInterfaceList, *model.AdminApp, error) {
+	hash = strings.TrimSpace(hash)
+	if hash == "" {
+		return nil, nil, ErrTryItDenied
+	}
+	allowed, app, err := s.Wiki.visibleSet(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	api, err := s.Wiki.ListDAO.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if api == nil || api.Status != 1 {
+		return nil, nil, ErrTryItDenied
+	}
+	if allowed != nil {
+		if _, ok := allowed[api.GroupHash+"|"+api.Hash]; !ok {
+			return nil, nil, ErrTryItDenied
+		}
+	} else if appID = strings.TrimSpace(appID); appID != "" {
+		if app, err = s.Wiki.AppDAO.FindByAppID(ctx, appID); err != nil {
+			return nil, nil, err
+		}
+		if app == nil {
+			return nil, nil, ErrTryItApp
+		}
+	}
+	if app != nil && app.AppStatus == 0 {
+		return nil, nil, ErrTryItApp
+	}
+	return api, app, nil
+}
+
+// allow 每用户每分钟限次；Redis 异常时放行
+func (s *TryItService) allow(ctx context.Context, user WikiUserInfo) error {
+	if s.Rate <= 0 || s.Redis == nil {
+		return nil
+	}
+	who := "app:" + user.AppID
+	if user.AppID == "-1" {
+		who = "admin:" + strconv.FormatInt(user.ID, 10)
+	}
+	key := tryItRatePrefix + who + ":" + strconv.FormatInt(time.Now().Unix()/60, 10)
+	n, err := s.Redis.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return nil
+	}
+	if n == 1 {
+		s.Redis.Client.Expire(ctx, key, 2*time.Minute)
+	}
+	if n > int64(s.Rate) {
+		return ErrTryItLimited
+	}
+	return nil
+}
+
+// audit 写审计记录，失败不影响调试结果
+func (s *TryItService) audit(ctx context.Context, user WikiUserInfo, app *model.AdminApp, api *model.AdminInterfaceList, res *TryItResult, params map[string]interface{}, clientIP string) {
+	if s.DAO == nil {
+		return
+	}
+	m := &model.AdminTryItLog{UserID: user.ID, Hash: api.Hash, APIClass: api.APIClass, Method: res.Request.Method,
+		URL: truncateRunes(res.Request.URL, 1000), Status: res.Status, DurationMS: res.DurationMS, Error: truncateRunes(res.Error, 500),
+		ClientIP: clientIP, AddTime: time.Now().Unix()}
+	if app != nil {
+		m.AppID = app.AppID
+	}
+	if user.AppID == "-1" {
+		m.Admin = 1
+	}
+	b, _ := json.Marshal(params)
+	m.Params = truncateRunes(string(b), tryItParamsMax)
+	_ = s.DAO.Create(context.WithoutCancel(ctx), m)
+}
+
+// ListTryItLogsResult 调试审计列表
+type ListTryItLogsResult struct {
+	List  []model.AdminTryItLog `json:"list"`
+	Total int64                 `json:"total"`
+}
+
+// Logs 调试审计记录（hash / appID 可选）
+func (s *TryItService) Logs(ctx context.Context, hash, appID string, page, limit int) (*ListTryItLogsResult, error) {
+	list, total, err := s.DAO.List(ctx, hash, appID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []model.AdminTryItLog{}
+	}
+	return &ListTryItLogsResult{List: list, Total: total}, nil
+}
+
+func queryMethod(m string) bool {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// tryItQuery 参数转 query：标量按字符串，数组 / 对象按 JSON
+func tryItQuery(params map[string]interface{}) url.Values {
+	q := url.Values{}
+	for k, v := range params {
+		switch t := v.(type) {
+		case nil:
+		case string:
+			q.Set(k, t)
+		case float64, bool, json.Number, int, int64:
+			q.Set(k, fmt.Sprint(t))
+		default:
+			b, _ := json.Marshal(t)
+			q.Set(k, string(b))
+		}
+	}
+	return q
+}
+
+func flatHeader(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, vs := range h {
+		out[k] = strings.Join(vs, ", ")
+	}
+	return out
+}
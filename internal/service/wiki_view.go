@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/metrics"
+	"go-apiadmin/internal/repository/dao"
+	redisrepo "go-apiadmin/internal/repository/redis"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// wiki 浏览统计：按小时桶写入 Redis 有序集合（wikiview:{dim}:{小时起点unix秒}），
+// dim 为 api（接口）/ group（分组）/ app:{app_id}（该应用浏览的接口）/ raw（hash|app_id|group_hash 明细，用于落库）。
+// 定期将 raw 桶整桶写入 admin_wiki_view；已结束的小时落库后一次性累加到分组 hot（全部时间热度）。
+const (
+	ViewDimAPI   = "api"
+	ViewDimGroup = "group"
+
+	viewRetention      = 8 * 24 * time.Hour // Redis 桶保留时间
+	viewRedisMaxHours  = 7 * 24             // 不超过 7 天的区间直接由 Redis 汇总，更长区间查库
+	viewFlushLookback  = 24                 // 每次落库回看的已结束小时数
+	MaxViewRangeHours  = 90 * 24
+	viewFlushTimeout   = 30 * time.Second
+	viewSettle         = 60 // 小时结束后等待其他节点写完的秒数，之后才视为已结束
+	viewMemberSep      = "|"
+	viewFlushedMarkDim = "flushed"
+)
+
+// ErrViewRange 统计区间非法
+var ErrViewRange = errors.New("range 取值应为 all 或 Nh / Nd（如 24h、7d），最长 90d")
+
+// ParseViewRange 解析统计区间为小时数；空或 all 返回 0 表示全部时间
+func ParseViewRange(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "all" {
+		return 0, nil
+	}
+	if len(s) < 2 {
+		return 0, ErrViewRange
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, ErrViewRange
+	}
+	switch s[len(s)-1] {
+	case 'h':
+	case 'd':
+		n *= 24
+	default:
+		return 0, ErrViewRange
+	}
+	if n > MaxViewRangeHours {
+		return 0, ErrViewRange
+	}
+	return n, nil
+}
+
+type WikiViewService struct {
+	Redis    *redisrepo.Client
+	DAO      *dao.AdminWikiViewDAO
+	GroupDAO *dao.AdminGroupDAO
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewWikiViewService(r *redisrepo.Client, d *dao.AdminWikiViewDAO, grp *dao.AdminGroupDAO) *WikiViewService {
+	return &WikiViewService{Redis: r, DAO: d, GroupDAO: grp}
+}
+
+// WikiView 单次接口文档浏览
+type WikiView struct {
+	Hash      string
+	GroupHash string
+	AppID     string // 浏览应用，后台登录为 -1
+	At        time.Time
+}
+
+func viewKey(dim string, hour int64) string {
+	return "wikiview:" + dim + ":" + strconv.FormatInt(hour, 10)
+}
+
+func viewHour(t time.Time) int64 { return t.Unix() / 3600 * 3600 }
+
+// Record 累加当前小时浏览计数；未配置 Redis 时直接写库
+func (s *WikiViewService) Record(ctx context.Context, v WikiView) error {
+	if s == nil || v.Hash == "" {
+		return nil
+	}
+	if v.At.IsZero() {
+		v.At = time.Now()
+	}
+	hour := viewHour(v.At)
+	if s.Redis == nil {
+		if s.DAO == nil {
+			return nil
+		}
+		m := &model.AdminWikiView{Hour: hour, Hash: v.Hash, AppID: v.AppID, GroupHash: v.GroupHash, Views: 1, UpdateTime: v.At.Unix()}
+		if err := s.DAO.Incr(ctx, m); err != nil {
+			return err
+		}
+		if v.GroupHash != "" && s.GroupDAO != nil {
+			return s.GroupDAO.IncrHotBy(ctx, v.GroupHash, 1)
+		}
+		return nil
+	}
+	pipe := s.Redis.Client.Pipeline()
+	incr := func(dim, member string) {
+		k := viewKey(dim, hour)
+		pipe.ZIncrBy(ctx, k, 1, member)
+		pipe.Expire(ctx, k, viewRetention)
+	}
+	incr(ViewDimAPI, v.Hash)
+	if v.GroupHash != "" {
+		incr(ViewDimGroup, v.GroupHash)
+	}
+	if v.AppID != "" {
+		incr("app:"+v.AppID, v.Hash)
+	}
+	incr("raw", strings.Join([]string{v.Hash, v.AppID, v.GroupHash}, viewMemberSep))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Top 最近 hours 小时（含当前小时）浏览量倒序；dim 为 api 时 appID 非空仅统计该应用的浏览。
+// hours=0 为全部时间：分组取 admin_group.hot，接口取库内累计。
+func (s *WikiViewService) Top(ctx context.Context, dim, appID string, hours int) ([]dao.ViewCount, error) {
+	if dim != ViewDimAPI && dim != ViewDimGroup {
+		return nil, errors.New("invalid dim")
+	}
+	if dim == ViewDimGroup {
+		appID = ""
+	}
+	if hours == 0 && dim == ViewDimGroup {
+		return s.groupHot(ctx)
+	}
+	if s.Redis != nil && hours > 0 && hours <= viewRedisMaxHours {
+		redisDim := dim
+		if appID != "" {
+			redisDim = "app:" + appID
+		}
+		cur := viewHour(time.Now())
+		keys := make([]string, hours)
+		for i := range keys {
+			keys[i] = viewKey(redisDim, cur-int64(i)*3600)
+		}
+		zs, err := s.Redis.Client.ZUnionWithScores(ctx, redis.ZStore{Keys: keys, Aggregate: "SUM"}).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		out := make([]dao.ViewCount, 0, len(zs))
+		for _, z := range zs {
+			if m, ok := z.Member.(string); ok {
+				out = append(out, dao.ViewCount{Key: m, Views: int64(z.Score)})
+			}
+		}
+		sortViewCounts(out)
+		return out, nil
+	}
+	if s.DAO == nil {
+		return []dao.ViewCount{}, nil
+	}
+	var since int64
+	if hours > 0 {
+		since = viewHour(time.Now()) - int64(hours-1)*3600
+	}
+	column := "hash"
+	if dim == ViewDimGroup {
+		column = "group_hash"
+	}
+	out, err := s.DAO.Top(ctx, column, since, appID)
+	if err != nil {
+		return nil, err
+	}
+	sortViewCounts(out)
+	return out, nil
+}
+
+func (s *WikiViewService) groupHot(ctx context.Context) ([]dao.ViewCount, error) {
+	groups, err := s.GroupDAO.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dao.ViewCount, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, dao.ViewCount{Key: g.Hash, Views: g.Hot})
+	}
+	sortViewCounts(out)
+	return out, nil
+}
+
+// sortViewCounts 浏览量倒序，相同按 key 升序保证结果稳定
+func sortViewCounts(list []dao.ViewCount) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Views != list[j].Views {
+			return list[i].Views > list[j].Views
+		}
+		return list[i].Key < list[j].Key
+	})
+}
+
+// Flush 将当前小时及最近未落库的已结束小时写入 admin_wiki_view（整桶覆盖，多节点重复执行幂等）；
+// 已结束小时以 Redis 标记保证只落库、累加分组 hot 一次
+func (s *WikiViewService) Flush(ctx context.Context) error {
+	if s == nil || s.Redis == nil || s.DAO == nil {
+		return nil
+	}
+	now := time.Now()
+	cur := viewHour(now)
+	for i := viewFlushLookback; i >= 0; i-- {
+		hour := cur - int64(i)*3600
+		mark := viewKey(viewFlushedMarkDim, hour)
+		final := now.Unix() >= hour+3600+viewSettle
+		if final {
+			n, err := s.Redis.Client.Exists(ctx, mark).Result()
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				continue
+			}
+		}
+		zs, err := s.Redis.Client.ZRangeWithScores(ctx, viewKey("raw", hour), 0, -1).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		rows := make([]model.AdminWikiView, 0, len(zs))
+		groups := map[string]int64{}
+		for _, z := range zs {
+			m, _ := z.Member.(string)
+			parts := strings.SplitN(m, viewMemberSep, 3)
+			if len(parts) != 3 || parts[0] == "" {
+				continue
+			}
+			n := int64(z.Score)
+			rows = append(rows, model.AdminWikiView{Hour: hour, Hash: parts[0], AppID: parts[1], GroupHash: parts[2], Views: n, UpdateTime: now.Unix()})
+			if parts[2] != "" {
+				groups[parts[2]] += n
+			}
+		}
+		if err := s.DAO.SaveHourly(ctx, rows); err != nil {
+			return err
+		}
+		if !final {
+			continue
+		}
+		ok, err := s.Redis.Client.SetNX(ctx, mark, 1, viewRetention).Result()
+		if err != nil {
+			return err
+		}
+		if !ok || s.GroupDAO == nil {
+			continue
+		}
+		for g, n := range groups {
+			if err := s.GroupDAO.IncrHotBy(ctx, g, n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Start 后台按间隔落库；Close 停止并执行最后一次落库
+func (s *WikiViewService) Start(interval time.Duration) {
+	if s == nil || s.stop != nil {
+		return
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	s.stop, s.done = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(s.done)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-s.stop:
+				s.flushOnce()
+				return
+			case <-t.C:
+				s.flushOnce()
+			}
+		}
+	}()
+}
+
+func (s *WikiViewService) flushOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), viewFlushTimeout)
+	defer cancel()
+	if err := s.Flush(ctx); err != nil {
+		metrics.WikiViewFlushTotal.WithLabelValues("error").Inc()
+		return
+	}
+	metrics.WikiViewFlushTotal.WithLabelValues("ok").Inc()
+}
+
+func (s *WikiViewService) Close(ctx context.Context) error {
+	if s == nil || s.stop == nil {
+		return nil
+	}
+	close(s.stop)
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"go-apiadmin/internal/domain/model"
+	"go-apiadmin/internal/repository/dao"
+)
+
+// 文档可见范围：{group_hash: [api_hash, ...]}，接口列表中的 "*" 表示该分组下全部接口（含之后新增的）。
+// 应用分组的 api_show 为可见范围模板；show_mode=1 的应用继承所属（启用的）应用分组模板，
+// 再追加自身 app_api_show、排除 app_api_hide；show_mode=0 的应用仅使用 app_api_show（旧行为）。
+const (
+	ShowModeCustom  int8 = 0
+	ShowModeInherit int8 = 1
+
+	apiShowAll = "*"
+
+	VisibilitySourceProfile = "profile" // 来自应用分组模板
+	VisibilitySourceApp     = "app"     // 应用自身追加
+)
+
+var (
+	ErrAPIShow         = errors.New("可见范围格式应为 {\"分组hash\": [\"接口hash\" 或 \"*\"]}")
+	ErrShowMode        = errors.New("show_mode 取值应为 0（自定义）或 1（继承应用分组）")
+	ErrVisibilityApp   = errors.New("应用不存在")
+	ErrVisibilityGroup = errors.New("应用分组不存在")
+)
+
+// ValidateAPIShow 校验可见范围 JSON（空串合法）
+func ValidateAPIShow(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	var m map[string][]string
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return ErrAPIShow
+	}
+	return nil
+}
+
+// VisibilityResolver wiki 可见范围统一解析：分组列表、检索、详情、导出、SDK、在线调试共用
+type VisibilityResolver struct {
+	AppDAO      *dao.AdminAppDAO
+	AppGroupDAO *dao.AdminAppGroupDAO // 为空时不支持继承（按自定义处理）
+	ListDAO     *dao.AdminInterfaceListDAO
+}
+
+func NewVisibilityResolver(app *dao.AdminAppDAO, appg *dao.AdminAppGroupDAO, list *dao.AdminInterfaceListDAO) *VisibilityResolver {
+	return &VisibilityResolver{AppDAO: app, AppGroupDAO: appg, ListDAO: list}
+}
+
+// Visibility 解析结果；Allowed 为 nil 表示不限制（后台登录）
+type Visibility struct {
+	App     *model.AdminApp
+	Profile *model.AdminAppGroup // 实际继承的应用分组
+	Allowed map[string]struct{}  // "分组hash|接口hash"
+	Source  map[string]string    // Allowed 中各项来源 profile / app
+	Hidden  map[string]struct{}  // 被 app_api_hide 排除的模板项
+	actives map[string][]string  // 分组 hash -> 启用接口 hash，展开 "*" 时按需加载
+}
+
+// Allows 接口是否可见
+func (v *Visibility) Allows(groupHash, hash string) bool {
+	if v == nil || v.Allowed == nil {
+		return true
+	}
+	_, ok := v.Allowed[groupHash+"|"+hash]
+	return ok
+}
+
+// ResolveUser wiki 登录用户（应用或后台 app_id=-1）的可见范围；应用信息实时读取，不依赖登录缓存
+func (r *VisibilityResolver) ResolveUser(ctx context.Context, user WikiUserInfo) (*Visibility, error) {
+	if user.AppID == "-1" {
+		return &Visibility{}, nil
+	}
+	var app *model.AdminApp
+	var err error
+	if user.ID > 0 {
+		app, err = r.AppDAO.FindByID(ctx, user.ID)
+	} else {
+		app, err = r.AppDAO.FindByAppID(ctx, user.AppID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrVisibilityApp
+	}
+	return r.ResolveApp(ctx, app)
+}
+
+// ResolveApp 应用的可见范围
+func (r *VisibilityResolver) ResolveApp(ctx context.Context, app *model.AdminApp) (*Visibility, error) {
+	v := &Visibility{App: app, Allowed: map[string]struct{}{}, Source: map[string]string{}, Hidden: map[string]struct{}{}}
+	if app.ShowMode == ShowModeInherit && app.AppGroup != "" && r.AppGroupDAO != nil {
+		g, err := r.AppGroupDAO.FindByHash(ctx, app.AppGroup)
+		if err != nil {
+			return nil, err
+		}
+		if g != nil && g.Status == 1 {
+			v.Profile = g
+			if err := r.apply(ctx, v, g.APIShow, VisibilitySourceProfile); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := r.apply(ctx, v, app.AppAPIShow, VisibilitySourceApp); err != nil {
+		return nil, err
+	}
+	if app.ShowMode == ShowModeInherit {
+		keys, err := r.expand(ctx, v, app.AppAPIHide)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			if _, ok := v.Allowed[k]; ok {
+				delete(v.Allowed, k)
+				delete(v.Source, k)
+				v.Hidden[k] = struct{}{}
+			}
+		}
+	}
+	return v, nil
+}
+
+// ResolveProfile 应用分组模板本身的可见范围（预览用）
+func (r *VisibilityResolver) ResolveProfile(ctx context.Context, g *model.AdminAppGroup) (*Visibility, error) {
+	v := &Visibility{Profile: g, Allowed: map[string]struct{}{}, Source: map[string]string{}, Hidden: map[string]struct{}{}}
+	if err := r.apply(ctx, v, g.APIShow, VisibilitySourceProfile); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// apply 追加可见项；已存在的保持原来源
+func (r *VisibilityResolver) apply(ctx context.Context, v *Visibility, show, source string) error {
+	keys, err := r.expand(ctx, v, show)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if _, ok := v.Allowed[k]; !ok {
+			v.Allowed[k] = struct{}{}
+			v.Source[k] = source
+		}
+	}
+	return nil
+}
+
+// expand 解析可见范围为 "分组hash|接口hash" 列表，"*" 展开为分组下的启用接口
+func (r *VisibilityResolver) expand(ctx context.Context, v *Visibility, show string) ([]string, error) {
+	var keys []string
+	for gh, list := range parseAPIShow(show) {
+		for _, h := range list {
+			h = strings.TrimSpace(h)
+			if h != apiShowAll {
+				if h != "" {
+					keys = append(keys, gh+"|"+h)
+				}
+				continue
+			}
+			if v.actives == nil {
+				apis, err := r.ListDAO.ListAllActive(ctx)
+				if err != nil {
+					return nil, err
+				}
+				v.actives = map[string][]string{}
+				for _, a := range apis {
+					v.actives[a.GroupHash] = append(v.actives[a.GroupHash], a.Hash)
+				}
+			}
+			for _, ah := range v.actives[gh] {
+				keys = append(keys, gh+"|"+ah)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// VisibilityPreview 后台预览应用 / 应用分组模板可见的启用接口
+type VisibilityPreview struct {
+	AppID    string                   `json:"app_id,omitempty"`
+	AppName  string                   `json:"app_name,omitempty"`
+	ShowMode int8                     `json:"show_mode"`
+	Profile  *AppGroupDTO             `json:"profile"` // 继承的应用分组，未继承为 null
+	Groups   []VisibilityPreviewGroup `json:"groups"`
+	Hidden   []string                 `json:"hidden"` // 被 app_api_hide 排除的接口 hash
+	Count    int                      `json:"count"`
+}
+
+type VisibilityPreviewGroup struct {
+	Hash string                 `json:"hash"`
+	Name string                 `json:"name"`
+	APIs []VisibilityPreviewAPI `json:"apis"`
+}
+
+type VisibilityPreviewAPI struct {
+	Hash     string `json:"hash"`
+	APIClass string `json:"api_class"`
+	Info     string `json:"info"`
+	Source   string `json:"source"` // profile / app
+}
+
+// PreviewApp 按 id 或 app_id 预览应用在 wiki 中可见的接口
+func (s *WikiService) PreviewApp(ctx context.Context, id int64, appID string) (*VisibilityPreview, error) {
+	var app *model.AdminApp
+	var err error
+	if id > 0 {
+		app, err = s.AppDAO.FindByID(ctx, id)
+	} else if appID = strings.TrimSpace(appID); appID != "" {
+		app, err = s.AppDAO.FindByAppID(ctx, appID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrVisibilityApp
+	}
+	v, err := s.Visibility.ResolveApp(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.preview(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	out.AppID, out.AppName, out.ShowMode = app.AppID, app.AppName, app.ShowMode
+	return out, nil
+}
+
+// PreviewProfile 预览应用分组模板可见的接口
+func (s *WikiService) PreviewProfile(ctx context.Context, id int64) (*VisibilityPreview, error) {
+	if s.Visibility.AppGroupDAO == nil {
+		return nil, ErrVisibilityGroup
+	}
+	g, err := s.Visibility.AppGroupDAO.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, ErrVisibilityGroup
+	}
+	v, err := s.Visibility.ResolveProfile(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.preview(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	out.ShowMode = ShowModeInherit
+	return out, nil
+}
+
+func (s *WikiService) preview(ctx context.Context, v *Visibility) (*VisibilityPreview, error) {
+	groups, err := s.GroupDAO.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	apis, err := s.ListDAO.ListAllActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := &VisibilityPreview{Groups: []VisibilityPreviewGroup{}, Hidden: []string{}}
+	if v.Profile != nil {
+		g := v.Profile
+		out.Profile = &AppGroupDTO{ID: g.ID, Name: g.Name, Description: g.Description, Status: g.Status, Hash: g.Hash}
+	}
+	byGroup := map[string][]VisibilityPreviewAPI{}
+	for _, a := range apis {
+		k := a.GroupHash + "|" + a.Hash
+		if _, ok := v.Hidden[k]; ok {
+			out.Hidden = append(out.Hidden, a.Hash)
+		}
+		if !v.Allows(a.GroupHash, a.Hash) {
+			continue
+		}
+		byGroup[a.GroupHash] = append(byGroup[a.GroupHash], VisibilityPreviewAPI{Hash: a.Hash, APIClass: a.APIClass, Info: a.Info, Source: v.Source[k]})
+		out.Count++
+	}
+	for _, g := range groups {
+		list := byGroup[g.Hash]
+		if len(list) == 0 {
+			continue
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].APIClass < list[j].APIClass })
+		out.Groups = append(out.Groups, VisibilityPreviewGroup{Hash: g.Hash, Name: g.Name, APIs: list})
+	}
+	sort.Strings(out.Hidden)
+	return out, nil
+}
@@ -0,0 +1,18 @@
+package httputil
+
+import (
+	"net/http"
+	"strings"
+)
+
+// BaseURL 当前请求的 scheme://host（兼容反向代理 X-Forwarded-Proto，仅接受 http / https）
+func BaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if p := strings.ToLower(strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-Proto"), ",")[0])); p == "http" || p == "https" {
+		scheme = p
+	}
+	return scheme + "://" + r.Host
+}
@@ -24,6 +24,14 @@ const (
 	DELETE_FAILED        = -20
 	ADD_FAILED           = -21
 	UPDATE_FAILED        = -22
+	SIGN_INVALID         = -23
+	SIGN_EXPIRED         = -24
+	SIGN_REPLAYED        = -25
+	RATE_LIMITED         = -26
+	QUOTA_EXCEEDED       = -27
+	UPSTREAM_UNAVAILABLE = -28
+	BREAKING_CHANGE      = -29
+	API_SUNSET           = -30
 	PARAM_INVALID        = -995
 	ACCESS_TOKEN_TIMEOUT = -996
 	SESSION_TIMEOUT      = -997
@@ -60,6 +68,14 @@ func All() map[string]CodeInfo {
 		"DELETE_FAILED":        {DELETE_FAILED, "删除失败"},
 		"ADD_FAILED":           {ADD_FAILED, "添加记录失败"},
 		"UPDATE_FAILED":        {UPDATE_FAILED, "更新记录失败"},
+		"SIGN_INVALID":         {SIGN_INVALID, "签名校验失败"},
+		"SIGN_EXPIRED":         {SIGN_EXPIRED, "请求已过期"},
+		"SIGN_REPLAYED":        {SIGN_REPLAYED, "请求重复提交"},
+		"RATE_LIMITED":         {RATE_LIMITED, "请求过于频繁"},
+		"QUOTA_EXCEEDED":       {QUOTA_EXCEEDED, "调用次数已超出配额"},
+		"UPSTREAM_UNAVAILABLE": {UPSTREAM_UNAVAILABLE, "后端服务不可用"},
+		"BREAKING_CHANGE":      {BREAKING_CHANGE, "存在破坏性变更，需确认"},
+		"API_SUNSET":           {API_SUNSET, "接口已下线"},
 		"PARAM_INVALID":        {PARAM_INVALID, "数据类型非法"},
 		"ACCESS_TOKEN_TIMEOUT": {ACCESS_TOKEN_TIMEOUT, "身份令牌过期"},
 		"SESSION_TIMEOUT":      {SESSION_TIMEOUT, "SESSION过期"},
@@ -12,7 +12,11 @@ type Body struct {
 	Data interface{} `json:"data"`
 }
 
+// CodeKey 上下文中记录本次响应业务码的 key（网关调用日志据此区分被拒绝的调用）
+const CodeKey = "resp_code"
+
 func JSON(c *gin.Context, code int, msg string, data interface{}) {
+	c.Set(CodeKey, code)
 	c.JSON(200, Body{Code: code, Msg: msg, Data: data})
 }
 